@@ -0,0 +1,66 @@
+package steam
+
+import (
+	"strconv"
+	"sync"
+)
+
+// apiVersions maps the "Service/Method" of the WebAPI endpoints the
+// package calls through apiURL to the version it uses.
+var (
+	apiVersionsMu sync.RWMutex
+	apiVersions   = map[string]int{
+		apiGetTradeOffer:                     1,
+		apiGetTradeOffers:                    1,
+		apiGetTradeOffersSummary:             1,
+		apiDeclineTradeOffer:                 1,
+		apiCancelTradeOffer:                  1,
+		apiGetTradeHoldDurations:             1,
+		apiGetTradeHistory:                   1,
+		apiGetInventoryItemsWithDescriptions: 1,
+	}
+)
+
+// SetAPIVersion changes the version of a WebAPI method ("IEconService/GetTradeOffers")
+// for every session, e.g. to switch to a v2 Valve shipped before the package
+// caught up.  Sessions with their own override keep it.
+func SetAPIVersion(method string, version int) {
+	apiVersionsMu.Lock()
+	apiVersions[method] = version
+	apiVersionsMu.Unlock()
+}
+
+// APIVersion returns the version of a WebAPI method the session calls.
+func (session *Session) APIVersion(method string) int {
+	if version, ok := session.apiVersions[method]; ok {
+		return version
+	}
+
+	apiVersionsMu.RLock()
+	defer apiVersionsMu.RUnlock()
+
+	if version, ok := apiVersions[method]; ok {
+		return version
+	}
+
+	return 1
+}
+
+// SetAPIVersion overrides the version of a WebAPI method for this session
+// only, 0 goes back to the package-wide version.
+func (session *Session) SetAPIVersion(method string, version int) {
+	if version == 0 {
+		delete(session.apiVersions, method)
+		return
+	}
+
+	if session.apiVersions == nil {
+		session.apiVersions = make(map[string]int)
+	}
+	session.apiVersions[method] = version
+}
+
+// apiURL is the URL of a WebAPI method at the version the session uses.
+func (session *Session) apiURL(method string) string {
+	return session.APIBaseURL() + "/" + method + "/v" + strconv.Itoa(session.APIVersion(method)) + "/"
+}
@@ -0,0 +1,89 @@
+package steam
+
+import (
+	"encoding/json"
+	"net/url"
+	"strconv"
+)
+
+// AppPrice is the store price of an app, amounts are in cents.
+type AppPrice struct {
+	Currency         string `json:"currency"` // ISO 4217, e.g. "EUR"
+	Initial          int    `json:"initial"`
+	Final            int    `json:"final"`
+	DiscountPercent  int    `json:"discount_percent"`
+	InitialFormatted string `json:"initial_formatted"`
+	FinalFormatted   string `json:"final_formatted"`
+}
+
+type AppPlatforms struct {
+	Windows bool `json:"windows"`
+	Mac     bool `json:"mac"`
+	Linux   bool `json:"linux"`
+}
+
+type AppReleaseDate struct {
+	ComingSoon bool   `json:"coming_soon"`
+	Date       string `json:"date"` // localized, e.g. "21 Aug, 2012"
+}
+
+// AppDetails is what the storefront API knows about an app.
+type AppDetails struct {
+	AppID            int             `json:"steam_appid"`
+	Type             string          `json:"type"` // "game", "dlc", "music"...
+	Name             string          `json:"name"`
+	IsFree           bool            `json:"is_free"`
+	ShortDescription string          `json:"short_description"`
+	HeaderImage      string          `json:"header_image"`
+	Developers       []string        `json:"developers"`
+	Publishers       []string        `json:"publishers"`
+	DLC              []int           `json:"dlc"`
+	Price            *AppPrice       `json:"price_overview"` // nil for free or unreleased apps
+	Platforms        AppPlatforms    `json:"platforms"`
+	ReleaseDate      *AppReleaseDate `json:"release_date"`
+}
+
+// GetAppDetails looks the apps up through the storefront API, cc being the
+// country code prices are given for and lang the language of the texts,
+// both optional.  Apps the store does not know (or hides in the country)
+// are missing from the result.
+func (session *Session) GetAppDetails(appIDs []int, cc, lang string) (map[int]*AppDetails, error) {
+	details := make(map[int]*AppDetails, len(appIDs))
+	for _, appID := range appIDs {
+		params := url.Values{
+			"appids": {strconv.Itoa(appID)},
+		}
+		if len(cc) != 0 {
+			params.Set("cc", cc)
+		}
+		if len(lang) != 0 {
+			params.Set("l", lang)
+		}
+
+		// appdetails takes several appids only together with
+		// filters=price_overview, hence one request per app.
+		body, err := session.getPage("https://store.steampowered.com/api/appdetails?" + params.Encode())
+		if err != nil {
+			return nil, err
+		}
+
+		var response map[string]struct {
+			Success bool        `json:"success"`
+			Data    *AppDetails `json:"data"`
+		}
+		if err = json.Unmarshal(body, &response); err != nil {
+			return nil, err
+		}
+
+		app, ok := response[strconv.Itoa(appID)]
+		if !ok {
+			return nil, ErrInvalidResponse
+		}
+
+		if app.Success && app.Data != nil {
+			details[appID] = app.Data
+		}
+	}
+
+	return details, nil
+}
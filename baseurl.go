@@ -0,0 +1,127 @@
+package steam
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// CommunityBaseUrl is the Steam Community every community page is
+// requested from unless the session points elsewhere.
+const CommunityBaseUrl = "https://steamcommunity.com"
+
+func parseBaseURL(base string) (string, error) {
+	if len(base) == 0 {
+		return "", nil
+	}
+
+	u, err := url.Parse(base)
+	if err != nil {
+		return "", err
+	}
+
+	if len(u.Scheme) == 0 || len(u.Host) == 0 {
+		return "", fmt.Errorf("base URL %q is not absolute", base)
+	}
+
+	return strings.TrimSuffix(u.String(), "/"), nil
+}
+
+// SetAPIBaseURL sends the WebAPI requests of the session to base (e.g.
+// "http://127.0.0.1:8080/api") instead of APIBaseUrl, an empty base goes
+// back to it.  Clones made afterwards inherit it.
+func (session *Session) SetAPIBaseURL(base string) error {
+	u, err := parseBaseURL(base)
+	if err != nil {
+		return err
+	}
+
+	session.apiBaseURL = u
+	return nil
+}
+
+// SetCommunityBaseURL is SetAPIBaseURL for the Steam Community pages and
+// AJAX endpoints, CommunityBaseUrl by default.  The cookies of the current
+// community base are copied over, so a logged in session stays logged in.
+func (session *Session) SetCommunityBaseURL(base string) error {
+	u, err := parseBaseURL(base)
+	if err != nil {
+		return err
+	}
+
+	previous := session.communityURL()
+	session.communityBaseURL = u
+	if session.client.Jar != nil {
+		session.client.Jar.SetCookies(session.communityURL(), session.client.Jar.Cookies(previous))
+	}
+
+	return nil
+}
+
+// APIBaseURL returns where the WebAPI requests of the session go.
+func (session *Session) APIBaseURL() string {
+	if len(session.apiBaseURL) == 0 {
+		return APIBaseUrl
+	}
+
+	return session.apiBaseURL
+}
+
+// CommunityBaseURL returns where the community requests of the session go.
+func (session *Session) CommunityBaseURL() string {
+	if len(session.communityBaseURL) == 0 {
+		return CommunityBaseUrl
+	}
+
+	return session.communityBaseURL
+}
+
+// communityURL is CommunityBaseURL as the URL its cookies are kept for.
+func (session *Session) communityURL() *url.URL {
+	u, _ := url.Parse(session.CommunityBaseURL())
+	return u
+}
+
+// apiHost is the host, with its port, the WebAPI requests of the session
+// go to.
+func (session *Session) apiHost() string {
+	u, _ := url.Parse(session.APIBaseURL())
+	return u.Host
+}
+
+// withDefaultHost returns req as it would be without SetAPIBaseURL and
+// SetCommunityBaseURL: a request under one of the base URLs is moved to
+// the Steam host it replaces, without the path of the base.  The
+// transports classify requests on the result, it is not sent.
+func (session *Session) withDefaultHost(req *http.Request) *http.Request {
+	bases := []struct{ base, host string }{
+		{session.apiBaseURL, DefaultAPIHost},
+		{session.communityBaseURL, "steamcommunity.com"},
+	}
+
+	for _, b := range bases {
+		if len(b.base) == 0 {
+			continue
+		}
+
+		u, err := url.Parse(b.base)
+		if err != nil || req.URL.Host != u.Host {
+			continue
+		}
+		if len(u.Path) != 0 && req.URL.Path != u.Path && !strings.HasPrefix(req.URL.Path, u.Path+"/") {
+			continue
+		}
+
+		moved := *req.URL
+		moved.Scheme, moved.Host = "https", b.host
+		moved.Path, moved.RawPath = strings.TrimPrefix(req.URL.Path, u.Path), ""
+
+		r := *req
+		r.URL = &moved
+		r.Host = b.host
+		return &r
+	}
+
+	return req
+}
@@ -0,0 +1,128 @@
+package steam
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestBaseURLs(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		paths []string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		paths = append(paths, r.URL.Path)
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"success":true,"response":{}}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	session := NewSession(srv.Client(), "key")
+	if err := session.SetAPIBaseURL(srv.URL + "/api/"); err != nil {
+		t.Fatal(err)
+	}
+	if err := session.SetCommunityBaseURL(srv.URL + "/community"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := session.SetAPIBaseURL("/relative"); err == nil {
+		t.Error("a relative base URL was accepted")
+	}
+
+	if got := session.APIBaseURL(); got != srv.URL+"/api" {
+		t.Errorf("API base %q, want %q", got, srv.URL+"/api")
+	}
+
+	clone, err := session.Clone()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = clone.GetTradeOffers(TradeFilterSentOffers, time.Time{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = session.GetMyMarketListings(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"/api/IEconService/GetTradeOffers/v1/", "/community/market/mylistings/render/"}
+	if len(paths) != len(want) || paths[0] != want[0] || paths[1] != want[1] {
+		t.Errorf("requested %q, want %q", paths, want)
+	}
+
+	if err = session.SetAPIBaseURL(""); err != nil || session.APIBaseURL() != APIBaseUrl {
+		t.Errorf("an empty base went to %q, %v", session.APIBaseURL(), err)
+	}
+}
+
+func TestBaseURLTransports(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		paths []string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		paths = append(paths, r.URL.Path)
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		if strings.HasPrefix(r.URL.Path, "/community/inventory/") {
+			w.Write([]byte(`{"success":1,"assets":[],"descriptions":[]}`))
+			return
+		}
+		w.Write([]byte(`{"success":true,"response":{}}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	session := NewSession(srv.Client(), "key")
+	if err := session.SetAPIBaseURL(srv.URL + "/api"); err != nil {
+		t.Fatal(err)
+	}
+	if err := session.SetCommunityBaseURL(srv.URL + "/community"); err != nil {
+		t.Fatal(err)
+	}
+	session.EnableCircuitBreakers(BreakerConfig{})
+	session.EnableLatencyTracking(time.Minute)
+	if err := session.EnableAPIFailover(APIFailoverConfig{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := session.GetTradeOffers(TradeFilterSentOffers, time.Time{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := session.GetMyMarketListings(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := session.GetInventory(76561197960287930, 730, 2, false); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"/api/IEconService/GetTradeOffers/v1/", "/community/market/mylistings/render/", "/community/inventory/76561197960287930/730/2"}
+	if len(paths) != len(want) || paths[0] != want[0] || paths[1] != want[1] || paths[2] != want[2] {
+		t.Errorf("requested %q, want %q", paths, want)
+	}
+
+	states := session.BreakerStates()
+	for _, group := range []string{EndpointGroupEcon, EndpointGroupMarket, EndpointGroupCommunity} {
+		if _, ok := states[group]; !ok {
+			t.Errorf("no %s breaker in %v", group, states)
+		}
+	}
+	if _, ok := states[EndpointGroupOther]; ok {
+		t.Errorf("requests to the base URLs were grouped as other: %v", states)
+	}
+
+	stats := session.EndpointStats()
+	for _, name := range []string{"IEconService/GetTradeOffers", "steamcommunity.com/market", "steamcommunity.com/inventory"} {
+		if _, ok := stats[name]; !ok {
+			t.Errorf("no latency for %s in %v", name, stats)
+		}
+	}
+}
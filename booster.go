@@ -0,0 +1,97 @@
+package steam
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	boosterCreatorURL = "/tradingcards/boostercreator/"
+
+	// boosterAvailableLayout is the layout of available_at_time, e.g.
+	// "Nov 3 @ 9:01am", in the time zone of the account.
+	boosterAvailableLayout = "Jan 2 @ 3:04pm"
+)
+
+// boosterDataExp matches the apps handed to the booster creator script:
+//
+//	CBoosterCreatorPage.Init( [{"appid":730,...}, ...], ...
+var boosterDataExp = regexp.MustCompile(`(?s)CBoosterCreatorPage\.Init\(\s*(\[.*?\])\s*,`)
+
+// BoosterApp is a game the account can make booster packs of.
+type BoosterApp struct {
+	AppID  uint32 `json:"appid"`
+	Name   string `json:"name"`
+	Series int    `json:"series"`
+	Price  uint64 `json:"price,string"` // in gems
+
+	// Unavailable is set while the pack of the day was already made,
+	// AvailableAtText tells until when as Steam wrote it.
+	Unavailable     bool   `json:"unavailable"`
+	AvailableAtText string `json:"available_at_time"`
+}
+
+// AvailableAt parses AvailableAtText in loc, the time zone of the account.
+// The text has no year, the next matching date after now is used.
+func (app *BoosterApp) AvailableAt(now time.Time, loc *time.Location) (time.Time, bool) {
+	if !app.Unavailable || len(app.AvailableAtText) == 0 {
+		return now, true
+	}
+
+	t, err := time.ParseInLocation(boosterAvailableLayout, strings.TrimSpace(app.AvailableAtText), loc)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	t = t.AddDate(now.In(loc).Year(), 0, 0)
+	if t.Before(now.Add(-24 * time.Hour)) {
+		t = t.AddDate(1, 0, 0)
+	}
+
+	return t, true
+}
+
+// GetBoosterEligibility reads the booster creator page: the games the
+// account can make packs of, their price in gems and when the ones made
+// today can be made again.
+func (session *Session) GetBoosterEligibility() ([]*BoosterApp, error) {
+	body, err := session.getPage(session.CommunityBaseURL() + boosterCreatorURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseBoosterCreator(body)
+}
+
+func parseBoosterCreator(body []byte) ([]*BoosterApp, error) {
+	p, err := parsePage("tradingcards/boostercreator", body)
+	if err != nil {
+		return nil, err
+	}
+
+	m := boosterDataExp.FindStringSubmatch(p.scripts)
+	if m == nil {
+		return nil, p.error("CBoosterCreatorPage.Init", ErrInvalidResponse)
+	}
+
+	var apps []*BoosterApp
+	if err = json.Unmarshal([]byte(m[1]), &apps); err != nil {
+		return nil, p.error("CBoosterCreatorPage.Init", err)
+	}
+
+	return apps, nil
+}
+
+// BoosterAppByID returns the entry of appID, nil when the account cannot make
+// packs of it.
+func BoosterAppByID(apps []*BoosterApp, appID uint32) *BoosterApp {
+	for _, app := range apps {
+		if app.AppID == appID {
+			return app
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,244 @@
+package steam
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+type BreakerState int
+
+const (
+	BreakerClosed BreakerState = iota
+	BreakerOpen
+	BreakerHalfOpen
+)
+
+func (state BreakerState) String() string {
+	switch state {
+	case BreakerClosed:
+		return "closed"
+	case BreakerOpen:
+		return "open"
+	case BreakerHalfOpen:
+		return "half-open"
+	}
+
+	return "unknown"
+}
+
+const (
+	EndpointGroupMarket    = "market"
+	EndpointGroupEcon      = "econ"
+	EndpointGroupCommunity = "community"
+	EndpointGroupAPI       = "api"
+	EndpointGroupStore     = "store"
+	EndpointGroupOther     = "other"
+)
+
+var ErrCircuitOpen = errors.New("circuit breaker is open for this endpoint group")
+
+// BreakerConfig configures the per endpoint group circuit breakers.  A
+// breaker opens once at least MinRequests were made in Window and the share
+// of failures (transport errors and 5xx) reaches ErrorRate.  After
+// OpenDuration a single probe request is let through, its outcome closes or
+// re-opens the breaker.
+type BreakerConfig struct {
+	ErrorRate    float64
+	MinRequests  int
+	Window       time.Duration
+	OpenDuration time.Duration
+}
+
+var DefaultBreakerConfig = BreakerConfig{
+	ErrorRate:    0.5,
+	MinRequests:  10,
+	Window:       time.Minute,
+	OpenDuration: 30 * time.Second,
+}
+
+type circuitBreaker struct {
+	config BreakerConfig
+
+	mu          sync.Mutex
+	state       BreakerState
+	windowStart time.Time
+	requests    int
+	failures    int
+	openedAt    time.Time
+	probing     bool
+}
+
+func (b *circuitBreaker) allow(now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case BreakerOpen:
+		if now.Sub(b.openedAt) < b.config.OpenDuration {
+			return false
+		}
+
+		b.state = BreakerHalfOpen
+		b.probing = true
+		return true
+	case BreakerHalfOpen:
+		if b.probing {
+			return false
+		}
+
+		b.probing = true
+		return true
+	}
+
+	if now.Sub(b.windowStart) > b.config.Window {
+		b.windowStart = now
+		b.requests = 0
+		b.failures = 0
+	}
+
+	return true
+}
+
+func (b *circuitBreaker) record(now time.Time, failed bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == BreakerHalfOpen {
+		b.probing = false
+		if failed {
+			b.state = BreakerOpen
+			b.openedAt = now
+		} else {
+			b.state = BreakerClosed
+			b.windowStart = now
+			b.requests = 0
+			b.failures = 0
+		}
+		return
+	}
+
+	b.requests++
+	if failed {
+		b.failures++
+	}
+
+	if b.requests >= b.config.MinRequests && float64(b.failures)/float64(b.requests) >= b.config.ErrorRate {
+		b.state = BreakerOpen
+		b.openedAt = now
+	}
+}
+
+func (b *circuitBreaker) State() BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
+
+// EndpointGroup tells which breaker a request URL belongs to.
+func EndpointGroup(req *http.Request) string {
+	host := req.URL.Hostname()
+	switch {
+	case host == "steamcommunity.com" && strings.HasPrefix(req.URL.Path, "/market"):
+		return EndpointGroupMarket
+	case host == "steamcommunity.com":
+		return EndpointGroupCommunity
+	case host == "api.steampowered.com" && strings.HasPrefix(req.URL.Path, "/IEconService"):
+		return EndpointGroupEcon
+	case host == "api.steampowered.com":
+		return EndpointGroupAPI
+	case host == "store.steampowered.com":
+		return EndpointGroupStore
+	}
+
+	return EndpointGroupOther
+}
+
+type breakerTransport struct {
+	session *Session
+	config  BreakerConfig
+	next    http.RoundTripper
+
+	mu       sync.Mutex
+	breakers map[string]*circuitBreaker
+}
+
+func (t *breakerTransport) breaker(group string) *circuitBreaker {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	b, ok := t.breakers[group]
+	if !ok {
+		b = &circuitBreaker{config: t.config, windowStart: time.Now()}
+		t.breakers[group] = b
+	}
+
+	return b
+}
+
+// setConfig makes the breakers of every group, existing or not, use config.
+func (t *breakerTransport) setConfig(config BreakerConfig) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.config = config
+	for _, b := range t.breakers {
+		b.mu.Lock()
+		b.config = config
+		b.mu.Unlock()
+	}
+}
+
+func (t *breakerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	b := t.breaker(EndpointGroup(t.session.withDefaultHost(req)))
+	if !b.allow(time.Now()) {
+		return nil, ErrCircuitOpen
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	b.record(time.Now(), err != nil || resp.StatusCode >= 500)
+	return resp, err
+}
+
+func (t *breakerTransport) states() map[string]string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	states := make(map[string]string, len(t.breakers))
+	for group, b := range t.breakers {
+		states[group] = b.State().String()
+	}
+
+	return states
+}
+
+// EnableCircuitBreakers wraps the session client so that each endpoint group
+// (market, econ, community...) fails fast with ErrCircuitOpen while Steam
+// keeps failing it, leaving the other groups untouched.  Enabling it again
+// replaces the config, the breakers keep their state.
+func (session *Session) EnableCircuitBreakers(config BreakerConfig) {
+	// Installed once, enabling again swaps the config in place.
+	if t := session.breakers; t != nil {
+		t.setConfig(config)
+		return
+	}
+
+	next := session.client.Transport
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	session.breakers = &breakerTransport{session: session, config: config, next: next, breakers: make(map[string]*circuitBreaker)}
+	session.client.Transport = session.breakers
+}
+
+// BreakerStates returns the state of every endpoint group seen so far.
+func (session *Session) BreakerStates() map[string]string {
+	if session.breakers == nil {
+		return map[string]string{}
+	}
+
+	return session.breakers.states()
+}
@@ -0,0 +1,48 @@
+package steam
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestEnableCircuitBreakersAgain(t *testing.T) {
+	session := newTestSession(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+
+	get := func() error {
+		resp, err := session.client.Get("https://steamcommunity.com/market/")
+		if err == nil {
+			resp.Body.Close()
+		}
+		return err
+	}
+
+	session.EnableCircuitBreakers(BreakerConfig{ErrorRate: 0.5, MinRequests: 100, Window: time.Minute, OpenDuration: time.Minute})
+	transport := session.client.Transport
+	for i := 0; i < 3; i++ {
+		if err := get(); err != nil {
+			t.Fatalf("request %d: %v", i, err)
+		}
+	}
+
+	// The market breaker exists already and has to take the new config.
+	session.EnableCircuitBreakers(BreakerConfig{ErrorRate: 0.5, MinRequests: 4, Window: time.Minute, OpenDuration: time.Minute})
+	if session.client.Transport != transport {
+		t.Fatal("transport wrapped again")
+	}
+
+	if err := get(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := get(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("got %v, want ErrCircuitOpen", err)
+	}
+
+	if state := session.BreakerStates()[EndpointGroupMarket]; state != "open" {
+		t.Errorf("market breaker %s, want open", state)
+	}
+}
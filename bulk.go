@@ -0,0 +1,171 @@
+package steam
+
+import (
+	"errors"
+	"strconv"
+	"time"
+)
+
+// Steam refuses new offers past these many active sent offers.
+const (
+	defaultMaxActiveOffers           = 30
+	defaultMaxActiveOffersPerPartner = 5
+
+	defaultBulkInterval = 2 * time.Second
+	confirmAttempts     = 3
+)
+
+var (
+	ErrActiveOfferLimit = errors.New("too many active sent offers")
+	ErrNoConfirmation   = errors.New("no confirmation for the offer")
+)
+
+// OutgoingOffer is one offer of SendTradeOffers, Token is empty for
+// friends.
+type OutgoingOffer struct {
+	Offer   *TradeOffer
+	Partner SteamID
+	Token   string
+}
+
+// BulkOptions configures SendTradeOffers, the zero value sends one offer
+// every 2 seconds within Steam's caps and leaves them unconfirmed.
+type BulkOptions struct {
+	Interval            time.Duration // between two sends
+	MaxActive           int           // active sent offers, defaults to 30
+	MaxActivePerPartner int           // defaults to 5
+
+	// IdentitySecret confirms every offer that needs it when set.
+	IdentitySecret string
+	TimeOffset     time.Duration
+}
+
+// BulkResult is the outcome of one OutgoingOffer, Offer.ID is set once
+// sent.
+type BulkResult struct {
+	Offer     *OutgoingOffer
+	Sent      bool
+	Confirmed bool
+	Err       error
+}
+
+// activeSentOffers counts the active sent offers by partner account ID.
+func (session *Session) activeSentOffers() (total int, byPartner map[uint32]int, err error) {
+	response, err := session.GetTradeOffers(TradeFilterSentOffers|TradeFilterActiveOnly, time.Now())
+	if err != nil {
+		return 0, nil, err
+	}
+
+	byPartner = make(map[uint32]int)
+	for _, offer := range response.SentOffers {
+		if offer.State == TradeStateActive || offer.State == TradeStateCreatedNeedsConfirmation {
+			total++
+			byPartner[offer.Partner]++
+		}
+	}
+
+	return total, byPartner, nil
+}
+
+// confirmOffer allows the mobile confirmation of a sent offer, which may
+// take Steam a moment to list.
+func (session *Session) confirmOffer(offerID uint64, opts *BulkOptions) error {
+	creator := strconv.FormatUint(offerID, 10)
+	for attempt := 0; attempt < confirmAttempts; attempt++ {
+		if attempt != 0 {
+			time.Sleep(opts.Interval)
+		}
+
+		current := time.Now().Add(opts.TimeOffset).Unix()
+		confirmations, err := session.GetConfirmations(opts.IdentitySecret, current)
+		if err != nil {
+			return err
+		}
+
+		for _, confirmation := range confirmations {
+			if confirmation.Type == ConfirmationTypeTrade && confirmation.Creator == creator {
+				return session.AnswerConfirmation(confirmation, opts.IdentitySecret, "allow", current)
+			}
+		}
+	}
+
+	return ErrNoConfirmation
+}
+
+// SendTradeOffers sends offers one at a time, Interval apart, taking turns
+// between partners so one partner's queue does not hold the others back.
+// Offers that would go past the active offer caps are not sent and fail
+// with ErrActiveOfferLimit.  Results are in the order of offers.
+func (session *Session) SendTradeOffers(offers []OutgoingOffer, opts BulkOptions) ([]*BulkResult, error) {
+	if opts.Interval <= 0 {
+		opts.Interval = defaultBulkInterval
+	}
+	if opts.MaxActive <= 0 {
+		opts.MaxActive = defaultMaxActiveOffers
+	}
+	if opts.MaxActivePerPartner <= 0 {
+		opts.MaxActivePerPartner = defaultMaxActiveOffersPerPartner
+	}
+
+	total, byPartner, err := session.activeSentOffers()
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*BulkResult, len(offers))
+	queues := make(map[uint32][]int)
+	var partners []uint32
+	for i := range offers {
+		offer := &offers[i]
+		results[i] = &BulkResult{Offer: offer}
+
+		accountID := offer.Partner.GetAccountID()
+		if _, ok := queues[accountID]; !ok {
+			partners = append(partners, accountID)
+		}
+		queues[accountID] = append(queues[accountID], i)
+	}
+
+	first := true
+	for len(partners) != 0 {
+		var next []uint32
+		for _, accountID := range partners {
+			i := queues[accountID][0]
+			queues[accountID] = queues[accountID][1:]
+			if len(queues[accountID]) != 0 {
+				next = append(next, accountID)
+			}
+
+			result := results[i]
+			if total >= opts.MaxActive || byPartner[accountID] >= opts.MaxActivePerPartner {
+				result.Err = ErrActiveOfferLimit
+				continue
+			}
+
+			if !first {
+				time.Sleep(opts.Interval)
+			}
+			first = false
+
+			offer := result.Offer
+			if result.Err = session.SendTradeOffer(offer.Offer, offer.Partner, offer.Token); result.Err != nil {
+				session.log().Warnf("bulk send to %d failed: %v", accountID, result.Err)
+				continue
+			}
+
+			result.Sent = true
+			total++
+			byPartner[accountID]++
+
+			if offer.Offer.State == TradeStateCreatedNeedsConfirmation && len(opts.IdentitySecret) != 0 {
+				if result.Err = session.confirmOffer(offer.Offer.ID, &opts); result.Err == nil {
+					result.Confirmed = true
+				}
+			}
+		}
+
+		partners = next
+	}
+
+	return results, nil
+}
@@ -0,0 +1,176 @@
+// Package bundle stores everything a bot needs for one account (login,
+// authenticator secrets and the logged in session) in a single file
+// encrypted with a passphrase, instead of a plaintext maFile next to a
+// plaintext password:
+//
+//	b, err := bundle.Open("bot.bundle", passphrase)
+//	...
+//	session := steam.NewSession(&http.Client{}, "")
+//	if b.Session != nil {
+//		err = session.Restore(b.Session)
+//	}
+//	...
+//	b.Session = session.State()
+//	err = b.Save("bot.bundle", passphrase)
+//
+// The file is AES-256-GCM encrypted with a key derived from the passphrase
+// with PBKDF2-HMAC-SHA256.
+package bundle
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+
+	"github.com/hiship/go-steam"
+	"golang.org/x/crypto/pbkdf2"
+)
+
+const (
+	magic     = "GOSTEAMB1"
+	saltSize  = 16
+	keySize   = 32
+	headerLen = len(magic) + 4 + saltSize
+)
+
+// Iterations is the PBKDF2 iteration count used by Save, Open reads it
+// from the file.
+var Iterations uint32 = 600000
+
+var (
+	ErrInvalidBundle = errors.New("not a bundle file")
+	ErrWrongPassword = errors.New("wrong passphrase or corrupted bundle")
+	ErrEmptyPassword = errors.New("bundle passphrase is empty")
+)
+
+type Bundle struct {
+	AccountName    string              `json:"account_name"`
+	Password       string              `json:"password"`
+	SharedSecret   string              `json:"shared_secret,omitempty"`
+	IdentitySecret string              `json:"identity_secret,omitempty"`
+	RevocationCode string              `json:"revocation_code,omitempty"`
+	Session        *steam.SessionState `json:"session,omitempty"`
+}
+
+// Open decrypts the bundle at path.
+func Open(path, passphrase string) (*Bundle, error) {
+	if len(passphrase) == 0 {
+		return nil, ErrEmptyPassword
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) < headerLen || string(data[:len(magic)]) != magic {
+		return nil, ErrInvalidBundle
+	}
+
+	header := data[:headerLen]
+	iterations := binary.BigEndian.Uint32(header[len(magic):])
+	salt := header[len(magic)+4:]
+
+	gcm, err := newGCM(passphrase, salt, iterations)
+	if err != nil {
+		return nil, err
+	}
+
+	rest := data[headerLen:]
+	if len(rest) < gcm.NonceSize() {
+		return nil, ErrInvalidBundle
+	}
+
+	/* The header is authenticated along with the payload.  */
+	plain, err := gcm.Open(nil, rest[:gcm.NonceSize()], rest[gcm.NonceSize():], header)
+	if err != nil {
+		return nil, ErrWrongPassword
+	}
+
+	b := &Bundle{}
+	if err = json.Unmarshal(plain, b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// Save encrypts the bundle to path, replacing it atomically.  The file is
+// only readable by its owner.
+func (b *Bundle) Save(path, passphrase string) error {
+	if len(passphrase) == 0 {
+		return ErrEmptyPassword
+	}
+
+	plain, err := json.Marshal(b)
+	if err != nil {
+		return err
+	}
+
+	header := make([]byte, headerLen)
+	copy(header, magic)
+	binary.BigEndian.PutUint32(header[len(magic):], Iterations)
+	salt := header[len(magic)+4:]
+	if _, err = rand.Read(salt); err != nil {
+		return err
+	}
+
+	gcm, err := newGCM(passphrase, salt, Iterations)
+	if err != nil {
+		return err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err = rand.Read(nonce); err != nil {
+		return err
+	}
+
+	data := append(header, nonce...)
+	data = gcm.Seal(data, nonce, plain, header)
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err = tmp.Chmod(0600); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if _, err = tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+// deriveKey is PBKDF2 (RFC 8018) with HMAC-SHA256.
+func deriveKey(passphrase string, salt []byte, iterations uint32) []byte {
+	return pbkdf2.Key([]byte(passphrase), salt, int(iterations), keySize, sha256.New)
+}
+
+func newGCM(passphrase string, salt []byte, iterations uint32) (cipher.AEAD, error) {
+	if iterations == 0 {
+		return nil, ErrInvalidBundle
+	}
+
+	block, err := aes.NewCipher(deriveKey(passphrase, salt, iterations))
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
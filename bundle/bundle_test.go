@@ -0,0 +1,105 @@
+package bundle
+
+import (
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDeriveKey(t *testing.T) {
+	// PBKDF2-HMAC-SHA256 vectors of RFC 7914, section 11, cut to the key
+	// size.  A change here would lock every existing bundle out.
+	tests := []struct {
+		passphrase string
+		salt       string
+		iterations uint32
+		key        string
+	}{
+		{"passwd", "salt", 1, "55ac046e56e3089fec1691c22544b605f94185216dde0465e68b9d57c20dacbc"},
+		{"Password", "NaCl", 80000, "4ddcd8f60b98be21830cee5ef22701f9641a4418d04c0414aeff08876b34ab56"},
+	}
+
+	for _, tt := range tests {
+		if got := hex.EncodeToString(deriveKey(tt.passphrase, []byte(tt.salt), tt.iterations)); got != tt.key {
+			t.Errorf("deriveKey(%q, %q, %d) = %s, want %s", tt.passphrase, tt.salt, tt.iterations, got, tt.key)
+		}
+	}
+}
+
+func lowIterations(t *testing.T) {
+	previous := Iterations
+	Iterations = 1000
+	t.Cleanup(func() { Iterations = previous })
+}
+
+func TestSaveOpen(t *testing.T) {
+	lowIterations(t)
+	path := filepath.Join(t.TempDir(), "bot.bundle")
+
+	b := &Bundle{AccountName: "bot", Password: "hunter2", SharedSecret: "c2hhcmVk", IdentitySecret: "aWRlbnRpdHk="}
+	if err := b.Save(path, "passphrase"); err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("bundle mode %v, want 0600", info.Mode().Perm())
+	}
+
+	got, err := Open(path, "passphrase")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if *got != *b {
+		t.Errorf("opened %+v, want %+v", got, b)
+	}
+}
+
+func TestOpenFailures(t *testing.T) {
+	lowIterations(t)
+	dir := t.TempDir()
+	path := filepath.Join(dir, "bot.bundle")
+
+	if err := (&Bundle{AccountName: "bot"}).Save(path, "passphrase"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Open(path, "wrong"); !errors.Is(err, ErrWrongPassword) {
+		t.Errorf("wrong passphrase: got %v, want ErrWrongPassword", err)
+	}
+
+	if _, err := Open(path, ""); !errors.Is(err, ErrEmptyPassword) {
+		t.Errorf("empty passphrase: got %v, want ErrEmptyPassword", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The header is authenticated, a changed iteration count must not
+	// open either.
+	tampered := append([]byte(nil), data...)
+	tampered[len(magic)+3] ^= 1
+	tamperedPath := filepath.Join(dir, "tampered.bundle")
+	if err = os.WriteFile(tamperedPath, tampered, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = Open(tamperedPath, "passphrase"); !errors.Is(err, ErrWrongPassword) {
+		t.Errorf("tampered header: got %v, want ErrWrongPassword", err)
+	}
+
+	notBundle := filepath.Join(dir, "plain.json")
+	if err = os.WriteFile(notBundle, []byte(`{"shared_secret":"x"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = Open(notBundle, "passphrase"); !errors.Is(err, ErrInvalidBundle) {
+		t.Errorf("plain file: got %v, want ErrInvalidBundle", err)
+	}
+}
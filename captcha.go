@@ -0,0 +1,199 @@
+package steam
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+const (
+	storeCaptchaRefreshURL    = "https://store.steampowered.com/join/refreshcaptcha/"
+	storeCaptchaRenderURL     = "https://store.steampowered.com/login/rendercaptcha/"
+	communityCaptchaRenderURL = "/login/rendercaptcha/"
+	communityDoLoginURL       = "/login/dologin/"
+
+	// maxLoginCaptchas bounds the captchas solved for a single login.
+	maxLoginCaptchas = 3
+	// loginCaptchaTimeout bounds the time the solver has for a captcha.
+	loginCaptchaTimeout = 5 * time.Minute
+)
+
+var (
+	ErrCaptchaRequired = errors.New("captcha required but no solver is set")
+	ErrCaptchaRejected = errors.New("captcha answers rejected")
+)
+
+// CaptchaSolver reads the text of a Steam captcha, e.g. by handing the
+// image to a solving service or a human.  gid identifies the captcha,
+// image is the PNG Steam rendered for it.
+type CaptchaSolver interface {
+	SolveCaptcha(ctx context.Context, gid string, image []byte) (string, error)
+}
+
+// CaptchaSolverFunc adapts a function to CaptchaSolver.
+type CaptchaSolverFunc func(ctx context.Context, gid string, image []byte) (string, error)
+
+func (f CaptchaSolverFunc) SolveCaptcha(ctx context.Context, gid string, image []byte) (string, error) {
+	return f(ctx, gid, image)
+}
+
+type nopCaptchaSolver struct{}
+
+func (nopCaptchaSolver) SolveCaptcha(context.Context, string, []byte) (string, error) {
+	return "", ErrCaptchaRequired
+}
+
+// SetCaptchaSolver makes the captcha guarded endpoints solve their
+// captchas with solver, nil restores the default which fails with
+// ErrCaptchaRequired.  Account creation always needs one.  Login uses it
+// when the authentication service throttles the account: the captcha of
+// the community login is solved, then the login is tried again.
+func (session *Session) SetCaptchaSolver(solver CaptchaSolver) {
+	session.captchaSolver = solver
+}
+
+func (session *Session) captcha() CaptchaSolver {
+	if session.captchaSolver == nil {
+		return nopCaptchaSolver{}
+	}
+
+	return session.captchaSolver
+}
+
+// refreshCaptcha asks the store for a new captcha and returns its gid.
+func (session *Session) refreshCaptcha() (string, error) {
+	resp, err := session.client.PostForm(storeCaptchaRefreshURL, url.Values{"count": {"1"}})
+	if resp != nil {
+		defer func(Body io.ReadCloser) {
+			err := Body.Close()
+			if err != nil {
+				return
+			}
+		}(resp.Body)
+	}
+
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("http error: %d", resp.StatusCode)
+	}
+
+	var response struct {
+		GID json.Number `json:"gid"`
+	}
+	if err = session.readJSON(resp, &response); err != nil {
+		return "", err
+	}
+
+	if len(response.GID) == 0 || response.GID == "-1" {
+		return "", ErrInvalidResponse
+	}
+
+	return response.GID.String(), nil
+}
+
+// solveCaptcha fetches the image of the captcha gid from renderURL and has
+// the solver of the session read it.
+func (session *Session) solveCaptcha(ctx context.Context, renderURL, gid string) (string, error) {
+	resp, err := session.client.Get(renderURL + "?" + url.Values{"gid": {gid}}.Encode())
+	if resp != nil {
+		defer func(Body io.ReadCloser) {
+			err := Body.Close()
+			if err != nil {
+				return
+			}
+		}(resp.Body)
+	}
+
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("http error: %d", resp.StatusCode)
+	}
+
+	image, err := io.ReadAll(session.limitBody(resp))
+	if err != nil {
+		return "", err
+	}
+
+	text, err := session.captcha().SolveCaptcha(ctx, gid, image)
+	if err != nil {
+		return "", fmt.Errorf("captcha %s: %w", gid, err)
+	}
+
+	return text, nil
+}
+
+// loginCaptcha clears the captcha Steam puts in front of a throttled
+// account.  The community login answers captcha_needed with a gid until
+// it is given the right text, crypt and timestamp are the encrypted
+// password and the RSA timestamp of the login.
+func (session *Session) loginCaptcha(accountName, crypt string, timestamp uint64) error {
+	gid, text := "-1", ""
+	for attempt := 0; ; attempt++ {
+		var response struct {
+			Success       bool        `json:"success"`
+			CaptchaNeeded bool        `json:"captcha_needed"`
+			CaptchaGID    json.Number `json:"captcha_gid"`
+		}
+		err := session.postCommunityLogin(url.Values{
+			"username":       {accountName},
+			"password":       {crypt},
+			"rsatimestamp":   {strconv.FormatUint(timestamp, 10)},
+			"captchagid":     {gid},
+			"captcha_text":   {text},
+			"remember_login": {"true"},
+			"donotcache":     {strconv.FormatInt(time.Now().UnixMilli(), 10)},
+		}, &response)
+		if err != nil {
+			return err
+		}
+
+		if !response.CaptchaNeeded {
+			return nil
+		}
+
+		if attempt == maxLoginCaptchas {
+			return ErrCaptchaRejected
+		}
+
+		gid = response.CaptchaGID.String()
+		session.log().Warnf("login of %s needs captcha %s, attempt %d/%d", accountName, gid, attempt+1, maxLoginCaptchas)
+
+		ctx, cancel := context.WithTimeout(context.Background(), loginCaptchaTimeout)
+		text, err = session.solveCaptcha(ctx, session.CommunityBaseURL()+communityCaptchaRenderURL, gid)
+		cancel()
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (session *Session) postCommunityLogin(values url.Values, v interface{}) error {
+	resp, err := session.client.PostForm(session.CommunityBaseURL()+communityDoLoginURL, values)
+	if resp != nil {
+		defer func(Body io.ReadCloser) {
+			err := Body.Close()
+			if err != nil {
+				return
+			}
+		}(resp.Body)
+	}
+
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("http error: %d", resp.StatusCode)
+	}
+
+	return session.readJSON(resp, v)
+}
@@ -0,0 +1,177 @@
+package steam
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+const (
+	apiGetCommunityBadgeProgress = "/IPlayerService/GetCommunityBadgeProgress/v1/?"
+
+	badgesPageURL = "/profiles/%d/badges/?p=%d"
+
+	// maxBadgesPages stops the walk through the badges pages should the
+	// pagination never end.
+	maxBadgesPages = 100
+)
+
+var (
+	gameCardsLinkExp = regexp.MustCompile(`/gamecards/(\d+)`)
+	cardDropsExp     = regexp.MustCompile(`(\d+)\s+card drops? remaining`)
+	hoursOnRecordExp = regexp.MustCompile(`([\d,.]+)\s+hrs on record`)
+)
+
+// BadgeQuest is one task of a community badge, see GetBadgeProgress.
+type BadgeQuest struct {
+	QuestID   uint32 `json:"questid"`
+	Completed bool   `json:"completed"`
+}
+
+// CardDrops is what the badges page tells about the trading cards of a
+// game the account played.
+type CardDrops struct {
+	AppID     uint32
+	Name      string
+	Remaining int     // card drops left
+	Hours     float64 // playtime on record
+}
+
+// GetBadgeProgress returns the quests of the community badge badgeID of
+// sid, e.g. 2 for the Steam community badge.
+func (session *Session) GetBadgeProgress(sid SteamID, badgeID uint32) ([]*BadgeQuest, error) {
+	resp, err := session.client.Get(session.APIBaseURL() + apiGetCommunityBadgeProgress + session.authorize(url.Values{
+		"steamid": {sid.ToString()},
+		"badgeid": {strconv.FormatUint(uint64(badgeID), 10)},
+	}).Encode())
+	if resp != nil {
+		defer resp.Body.Close()
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	type Response struct {
+		Inner *struct {
+			Quests []*BadgeQuest `json:"quests"`
+		} `json:"response"`
+	}
+
+	var response Response
+	if err = session.readJSON(resp, &response); err != nil {
+		return nil, err
+	}
+
+	if response.Inner == nil {
+		return nil, ErrInvalidResponse
+	}
+
+	return response.Inner.Quests, nil
+}
+
+// GetCardDrops walks the badges pages of the account and returns every
+// game with its remaining card drops, those without any left included.
+func (session *Session) GetCardDrops() ([]*CardDrops, error) {
+	var drops []*CardDrops
+	for n := 1; n <= maxBadgesPages; n++ {
+		body, err := session.getPage(session.CommunityBaseURL() + fmt.Sprintf(badgesPageURL, session.oauth.SteamID, n))
+		if err != nil {
+			return nil, err
+		}
+
+		page, last, err := parseBadgesPage(body)
+		if err != nil {
+			return nil, err
+		}
+
+		drops = append(drops, page...)
+		if last <= n {
+			break
+		}
+	}
+
+	return drops, nil
+}
+
+// parseBadgesPage reads the game badges of one badges page and the number
+// of the last page.
+func parseBadgesPage(body []byte) ([]*CardDrops, int, error) {
+	p, err := parsePage("badges", body)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var drops []*CardDrops
+	p.doc.Find(".badge_row").Each(func(_ int, s *goquery.Selection) {
+		href, _ := s.Find(".badge_row_overlay").Attr("href")
+		m := gameCardsLinkExp.FindStringSubmatch(href)
+		if m == nil {
+			return // not a game badge
+		}
+
+		appID, err := strconv.ParseUint(m[1], 10, 32)
+		if err != nil {
+			return
+		}
+
+		d := &CardDrops{AppID: uint32(appID)}
+
+		title := s.Find(".badge_title").First().Clone()
+		title.Find("span").Remove()
+		d.Name = strings.TrimSpace(strings.ReplaceAll(title.Text(), "\u00a0", " "))
+
+		if m = cardDropsExp.FindStringSubmatch(s.Find(".progress_info_bold").Text()); m != nil {
+			d.Remaining, _ = strconv.Atoi(m[1])
+		}
+
+		if m = hoursOnRecordExp.FindStringSubmatch(s.Find(".badge_title_stats_playtime").Text()); m != nil {
+			d.Hours, _ = strconv.ParseFloat(strings.ReplaceAll(m[1], ",", ""), 64)
+		}
+
+		drops = append(drops, d)
+	})
+
+	last := 1
+	p.doc.Find(".pagelink").Each(func(_ int, s *goquery.Selection) {
+		if n, err := strconv.Atoi(strings.TrimSpace(s.Text())); err == nil && n > last {
+			last = n
+		}
+	})
+
+	return drops, last, nil
+}
+
+// FarmableApps returns the appids that still drop cards, those with the
+// most drops left first.  When owned is given (see GetOwnedGames) games no
+// longer owned are left out.
+func FarmableApps(drops []*CardDrops, owned *OwnedGamesResponse) []uint32 {
+	var have map[uint32]bool
+	if owned != nil {
+		have = make(map[uint32]bool, len(owned.Games))
+		for _, game := range owned.Games {
+			have[game.AppID] = true
+		}
+	}
+
+	var farmable []*CardDrops
+	for _, d := range drops {
+		if d.Remaining > 0 && (have == nil || have[d.AppID]) {
+			farmable = append(farmable, d)
+		}
+	}
+
+	sort.SliceStable(farmable, func(i, j int) bool { return farmable[i].Remaining > farmable[j].Remaining })
+
+	apps := make([]uint32, len(farmable))
+	for i, d := range farmable {
+		apps[i] = d.AppID
+	}
+
+	return apps
+}
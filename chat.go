@@ -1,7 +1,6 @@
 package steam
 
 import (
-	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
@@ -39,10 +38,10 @@ const (
 )
 
 const (
-	apiUserPresenceLogin   = APIBaseUrl + "/ISteamWebUserPresenceOAuth/Logon/v1"
-	apiUserPresenceLogoff  = APIBaseUrl + "/ISteamWebUserPresenceOAuth/Logoff/v1"
-	apiUserPresencePoll    = APIBaseUrl + "/ISteamWebUserPresenceOAuth/Poll/v1"
-	apiUserPresenceMessage = APIBaseUrl + "/ISteamWebUserPresenceOAuth/Message/v1"
+	apiUserPresenceLogin   = "/ISteamWebUserPresenceOAuth/Logon/v1"
+	apiUserPresenceLogoff  = "/ISteamWebUserPresenceOAuth/Logoff/v1"
+	apiUserPresencePoll    = "/ISteamWebUserPresenceOAuth/Poll/v1"
+	apiUserPresenceMessage = "/ISteamWebUserPresenceOAuth/Message/v1"
 )
 
 type ChatMessage struct {
@@ -90,7 +89,7 @@ type ChatFriendResponse struct {
 }
 
 func (session *Session) ChatLogin(uiMode string) error {
-	resp, err := session.client.PostForm(apiUserPresenceLogin, url.Values{
+	resp, err := session.client.PostForm(session.APIBaseURL()+apiUserPresenceLogin, url.Values{
 		"ui_mode":      {uiMode},
 		"access_token": {session.oauth.Token},
 	})
@@ -107,7 +106,7 @@ func (session *Session) ChatLogin(uiMode string) error {
 	}
 
 	var response ChatResponse
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+	if err := session.readJSON(resp, &response); err != nil {
 		return err
 	}
 
@@ -121,7 +120,7 @@ func (session *Session) ChatLogin(uiMode string) error {
 }
 
 func (session *Session) ChatLogoff() error {
-	resp, err := session.client.PostForm(apiUserPresenceLogoff, url.Values{
+	resp, err := session.client.PostForm(session.APIBaseURL()+apiUserPresenceLogoff, url.Values{
 		"access_token": {session.oauth.Token},
 		"umqid":        {session.umqID},
 	})
@@ -141,7 +140,11 @@ func (session *Session) ChatLogoff() error {
 }
 
 func (session *Session) ChatSendMessage(sid SteamID, message, messageType string) error {
-	resp, err := session.client.PostForm(apiUserPresenceMessage, url.Values{
+	if err := session.checkWritable(); err != nil {
+		return err
+	}
+
+	resp, err := session.client.PostForm(session.APIBaseURL()+apiUserPresenceMessage, url.Values{
 		"access_token": {session.oauth.Token},
 		"steamid_dst":  {sid.ToString()},
 		"text":         {message},
@@ -157,7 +160,7 @@ func (session *Session) ChatSendMessage(sid SteamID, message, messageType string
 	}
 
 	var response ChatResponse
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+	if err := session.readJSON(resp, &response); err != nil {
 		return err
 	}
 
@@ -169,7 +172,7 @@ func (session *Session) ChatSendMessage(sid SteamID, message, messageType string
 }
 
 func (session *Session) ChatPoll(timeoutSeconds string) (*ChatResponse, error) {
-	resp, err := session.client.PostForm(apiUserPresencePoll, url.Values{
+	resp, err := session.client.PostForm(session.APIBaseURL()+apiUserPresencePoll, url.Values{
 		"umqid":          {session.umqID},
 		"access_token":   {session.oauth.Token},
 		"message":        {strconv.FormatUint(uint64(session.chatMessage), 10)},
@@ -191,7 +194,7 @@ func (session *Session) ChatPoll(timeoutSeconds string) (*ChatResponse, error) {
 	}
 
 	response := &ChatResponse{}
-	if err := json.NewDecoder(resp.Body).Decode(response); err != nil {
+	if err := session.readJSON(resp, response); err != nil {
 		return nil, err
 	}
 
@@ -199,7 +202,7 @@ func (session *Session) ChatPoll(timeoutSeconds string) (*ChatResponse, error) {
 }
 
 func (session *Session) ChatFriendState(sid SteamID) (*ChatFriendResponse, error) {
-	resp, err := session.client.Get("https://steamcommunity.com/chat/friendstate/" + strconv.FormatUint(uint64(sid.GetAccountID()), 10))
+	resp, err := session.client.Get(session.CommunityBaseURL() + "/chat/friendstate/" + strconv.FormatUint(uint64(sid.GetAccountID()), 10))
 	if resp != nil {
 		defer resp.Body.Close()
 	}
@@ -213,7 +216,7 @@ func (session *Session) ChatFriendState(sid SteamID) (*ChatFriendResponse, error
 	}
 
 	response := &ChatFriendResponse{}
-	if err := json.NewDecoder(resp.Body).Decode(response); err != nil {
+	if err := session.readJSON(resp, response); err != nil {
 		return nil, err
 	}
 
@@ -221,7 +224,7 @@ func (session *Session) ChatFriendState(sid SteamID) (*ChatFriendResponse, error
 }
 
 func (session *Session) ChatLog(partner uint32) ([]*ChatLogMessage, error) {
-	resp, err := session.client.PostForm(fmt.Sprintf("https://steamcommunity.com/chat/chatlog/%d", partner), url.Values{
+	resp, err := session.client.PostForm(session.CommunityBaseURL()+fmt.Sprintf("/chat/chatlog/%d", partner), url.Values{
 		"sessionid": {session.sessionID},
 	})
 	if resp != nil {
@@ -233,7 +236,7 @@ func (session *Session) ChatLog(partner uint32) ([]*ChatLogMessage, error) {
 	}
 
 	log := []*ChatLogMessage{}
-	if err = json.NewDecoder(resp.Body).Decode(&log); err != nil {
+	if err = session.readJSON(resp, &log); err != nil {
 		return nil, err
 	}
 
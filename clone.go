@@ -0,0 +1,76 @@
+package steam
+
+import (
+	"maps"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"time"
+)
+
+// cookieHosts are the hosts whose cookies Clone carries over.
+var cookieHosts = []string{
+	"https://steamcommunity.com",
+	"https://store.steampowered.com",
+	"https://help.steampowered.com",
+	"https://login.steampowered.com",
+}
+
+// Clone returns a session logged into the same account, sharing the API key,
+// device ID and settings, but with its own client and cookie jar (seeded
+// with a copy of the current cookies), its own rate limiter and its own
+// statistics.  The transport, and with it circuit breakers, latency and key
+// quota tracking, is shared.  Clones can be used in parallel, e.g. one
+// polling the market and another trades, without their cookies or client
+// state interfering.
+func (session *Session) Clone() (*Session, error) {
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if session.client.Jar != nil {
+		hosts := cookieHosts
+		if len(session.communityBaseURL) != 0 {
+			hosts = append(hosts[:len(hosts):len(hosts)], session.communityBaseURL)
+		}
+
+		for _, host := range hosts {
+			u, _ := url.Parse(host)
+			cookies := session.client.Jar.Cookies(u)
+			for _, cookie := range cookies {
+				cookie.Path = "/"
+				cookie.Secure = u.Scheme == "https"
+			}
+
+			jar.SetCookies(u, cookies)
+		}
+	}
+
+	session.tokenMu.Lock()
+	session.regionMu.Lock()
+	clone := &Session{sessionState: session.sessionState}
+	session.regionMu.Unlock()
+	session.tokenMu.Unlock()
+
+	// Everything else is shared, or copied as it is.
+	clone.client = &http.Client{
+		Transport:     session.client.Transport,
+		CheckRedirect: session.client.CheckRedirect,
+		Jar:           jar,
+		Timeout:       session.client.Timeout,
+	}
+	clone.stats = newSessionStats()
+	clone.apiVersions = maps.Clone(session.apiVersions)
+	clone.umqID, clone.chatMessage = "", 0
+	clone.tokenRenewalErr, clone.tokenRenewalRetry, clone.tokenRenewalBackoff = nil, time.Time{}, 0
+
+	// The limiter of session stays in the shared transport, the one of the
+	// clone goes on top and the requests passing it skip the other.
+	if session.limiter != nil {
+		clone.limiter = &rateLimitTransport{next: clone.client.Transport, interval: session.limiter.getInterval()}
+		clone.client.Transport = clone.limiter
+	}
+
+	return clone, nil
+}
@@ -0,0 +1,83 @@
+package steam
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestClone(t *testing.T) {
+	session := newTestSession(t, answer(`{}`))
+	session.SetRateLimit(time.Hour)
+	session.SetLanguage("german")
+	session.stats.add(statOffersSent)
+
+	clone, err := session.Clone()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if clone.apiKey != session.apiKey || clone.language != "german" || clone.GetSteamID() != testSteamID {
+		t.Errorf("clone lost the settings of the session")
+	}
+
+	if clone.client == session.client || clone.client.Jar == session.client.Jar {
+		t.Error("clone shares the client or cookie jar")
+	}
+
+	if clone.stats == session.stats || clone.Stats().OffersSent != 0 {
+		t.Error("clone shares the statistics")
+	}
+
+	if clone.limiter == nil || clone.limiter == session.limiter {
+		t.Fatal("clone shares the rate limiter")
+	}
+
+	// One request each: the session takes its turn, the clone has its own
+	// and does not wait an hour behind it.
+	get := func(s *Session) error {
+		resp, err := s.client.Get("https://steamcommunity.com/market/")
+		if err != nil {
+			return err
+		}
+		return resp.Body.Close()
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		if err := get(session); err != nil {
+			done <- err
+			return
+		}
+		done <- get(clone)
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("the clone waited for the rate limit of the session")
+	}
+}
+
+func TestSetRateLimit(t *testing.T) {
+	session := newTestSession(t, http.NotFoundHandler())
+	session.SetRateLimit(time.Minute)
+
+	now := time.Now()
+	if wait := session.limiter.reserve(now); wait != 0 {
+		t.Errorf("first request waits %v", wait)
+	}
+	if wait := session.limiter.reserve(now); wait != time.Minute {
+		t.Errorf("second request waits %v, want a minute", wait)
+	}
+
+	// Lifting the limit keeps the transport, the turns are free.
+	session.SetRateLimit(0)
+	session.limiter.reserve(now)
+	if wait := session.limiter.reserve(now.Add(2 * time.Minute)); wait != 0 {
+		t.Errorf("request waits %v without a limit", wait)
+	}
+}
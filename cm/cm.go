@@ -0,0 +1,441 @@
+// Package cm connects to the Steam client network (the CM servers) over
+// WebSocket, so a bot is told about new trade offers, messages and
+// friends going online instead of polling for them:
+//
+//	client, err := cm.Connect(ctx, "")
+//	...
+//	err = client.LogOn(&cm.LogOnDetails{
+//		AccountName:  name,
+//		SteamID:      uint64(session.GetSteamID()),
+//		RefreshToken: session.GetRefreshToken(),
+//	})
+//	for event := range client.Events() {
+//		switch e := event.(type) {
+//		case *cm.TradeOffersEvent:
+//			...
+//		}
+//	}
+//
+// Steam only accepts refresh tokens issued for an audience including the
+// client network; logons with a token it refuses end with a LoggedOnEvent
+// carrying EResult 5 (InvalidPassword) or 15 (AccessDenied).
+package cm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/hiship/go-steam/pb"
+	"golang.org/x/net/websocket"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	serverListURL = "https://api.steampowered.com/ISteamDirectory/GetCMListForConnect/v1/?cmtype=websockets&cellid="
+
+	// Persona states for SetPersonaState.
+	PersonaStateOffline = 0
+	PersonaStateOnline  = 1
+	PersonaStateBusy    = 2
+	PersonaStateAway    = 3
+
+	userNotificationTradeOffers = 1
+
+	defaultOSType = 16 // Windows 10
+)
+
+var (
+	ErrNoServers = errors.New("no CM server available")
+	ErrClosed    = errors.New("connection closed")
+)
+
+// LogOnDetails authenticates the logon with the refresh token of a web
+// login, see steam.Session.GetRefreshToken.
+type LogOnDetails struct {
+	AccountName  string
+	SteamID      uint64
+	RefreshToken string
+	CellID       uint32
+	Language     string // defaults to "english"
+	OSType       int32  // EOSType, defaults to Windows 10
+}
+
+func (details *LogOnDetails) language() string {
+	if len(details.Language) == 0 {
+		return "english"
+	}
+
+	return details.Language
+}
+
+func (details *LogOnDetails) osType() int32 {
+	if details.OSType == 0 {
+		return defaultOSType
+	}
+
+	return details.OSType
+}
+
+// Event is one of the *Event types of this package.
+type Event interface{}
+
+// LoggedOnEvent is the answer to LogOn, EResult 1 on success.
+type LoggedOnEvent struct {
+	EResult   int32
+	SteamID   uint64
+	CellID    uint32
+	Heartbeat time.Duration
+}
+
+// LoggedOffEvent is sent when Steam ends the session, e.g. because the
+// account logged on elsewhere (EResult 6, LoggedInElsewhere).
+type LoggedOffEvent struct {
+	EResult int32
+}
+
+// DisconnectedEvent is the last event, Err is why the connection ended.
+type DisconnectedEvent struct {
+	Err error
+}
+
+// FriendMessageEvent is a chat message from a friend.  Typing
+// notifications and such come with an EntryType other than 1.
+type FriendMessageEvent struct {
+	From      uint64
+	EntryType int32
+	Message   string
+	Timestamp time.Time
+}
+
+// PersonaStateEvent is a friend's profile or status update.
+type PersonaStateEvent struct {
+	SteamID      uint64
+	PersonaState uint32
+	Name         string
+	GameAppID    uint32
+	GameName     string
+}
+
+// TradeOffersEvent reports the number of pending incoming trade offers,
+// sent when it changes.
+type TradeOffersEvent struct {
+	Count uint32
+}
+
+// NewItemsEvent reports the number of new inventory items.
+type NewItemsEvent struct {
+	Count uint32
+}
+
+// Client is a connection to a CM server, its methods are safe for
+// concurrent use.
+type Client struct {
+	conn   *websocket.Conn
+	events chan Event
+
+	mu        sync.Mutex
+	steamID   uint64
+	sessionID int32
+	closed    bool
+	done      chan struct{}
+}
+
+// ServerList returns the WebSocket CM servers Steam currently recommends,
+// as host:port.
+func ServerList(ctx context.Context, cellID uint32) ([]string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, serverListURL+fmt.Sprint(cellID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("http error: %d", resp.StatusCode)
+	}
+
+	var response struct {
+		Inner struct {
+			Servers []struct {
+				Endpoint string `json:"endpoint"`
+				Type     string `json:"type"`
+			} `json:"serverlist"`
+		} `json:"response"`
+	}
+	if err = json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, err
+	}
+
+	var servers []string
+	for _, server := range response.Inner.Servers {
+		if server.Type == "websockets" {
+			servers = append(servers, server.Endpoint)
+		}
+	}
+
+	if len(servers) == 0 {
+		return nil, ErrNoServers
+	}
+
+	return servers, nil
+}
+
+// Connect opens a connection to server (host:port).  When server is empty
+// the servers of ServerList are tried in order until one accepts the
+// connection.
+func Connect(ctx context.Context, server string) (*Client, error) {
+	if len(server) != 0 {
+		return dial(ctx, server)
+	}
+
+	servers, err := ServerList(ctx, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, server = range servers {
+		var c *Client
+		if c, err = dial(ctx, server); err == nil {
+			return c, nil
+		}
+
+		// The caller gave up, another server would not help.
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	return nil, err
+}
+
+func dial(ctx context.Context, server string) (*Client, error) {
+	config, err := websocket.NewConfig("wss://"+server+"/cmsocket/", "https://steamcommunity.com")
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := config.DialContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	conn.PayloadType = websocket.BinaryFrame
+
+	c := &Client{
+		conn:   conn,
+		events: make(chan Event, 64),
+		done:   make(chan struct{}),
+	}
+	go c.readLoop()
+
+	return c, nil
+}
+
+// Events delivers what Steam pushes, it is closed after the
+// DisconnectedEvent.  It has to be drained, reading stops while it is
+// full.
+func (c *Client) Events() <-chan Event {
+	return c.events
+}
+
+func (c *Client) send(emsg uint32, body []byte) error {
+	c.mu.Lock()
+	header := &pb.CMsgProtoBufHeader{
+		Steamid:         proto.Uint64(c.steamID),
+		ClientSessionid: proto.Int32(c.sessionID),
+	}
+	closed := c.closed
+	c.mu.Unlock()
+
+	if closed {
+		return ErrClosed
+	}
+
+	b, err := (&packet{emsg: emsg, header: header, body: body}).marshal()
+	if err != nil {
+		return err
+	}
+
+	return websocket.Message.Send(c.conn, b)
+}
+
+// LogOn logs on with a refresh token, the outcome arrives as a
+// LoggedOnEvent.
+func (c *Client) LogOn(details *LogOnDetails) error {
+	c.mu.Lock()
+	c.steamID = details.SteamID
+	c.mu.Unlock()
+
+	return c.send(emsgClientLogon, clientLogon(details))
+}
+
+// SetPersonaState changes the friends list status, bots have to go online
+// to receive friend messages and persona updates.
+func (c *Client) SetPersonaState(state uint32) error {
+	return c.send(emsgClientChangeStatus, appendVarint(nil, 1, uint64(state)))
+}
+
+// LogOff ends the Steam session, the connection is closed by the server
+// afterwards.
+func (c *Client) LogOff() error {
+	return c.send(emsgClientLogOff, nil)
+}
+
+// Close closes the connection.
+func (c *Client) Close() error {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return nil
+	}
+	c.closed = true
+	close(c.done)
+	c.mu.Unlock()
+
+	return c.conn.Close()
+}
+
+func (c *Client) heartbeat(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.done:
+			return
+		case <-ticker.C:
+			if err := c.send(emsgClientHeartBeat, nil); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func (c *Client) readLoop() {
+	var err error
+	for {
+		var b []byte
+		if err = websocket.Message.Receive(c.conn, &b); err != nil {
+			break
+		}
+
+		if err = c.handle(b); err != nil {
+			break
+		}
+	}
+
+	c.mu.Lock()
+	if c.closed {
+		err = ErrClosed
+	}
+	c.mu.Unlock()
+
+	c.Close()
+	c.events <- &DisconnectedEvent{Err: err}
+	close(c.events)
+}
+
+func (c *Client) handle(b []byte) error {
+	p, err := parsePacket(b)
+	if err != nil {
+		return err
+	}
+
+	if p.emsg == emsgMulti {
+		packets, err := splitMulti(p.body)
+		if err != nil {
+			return err
+		}
+
+		for _, b := range packets {
+			if err = c.handle(b); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	f, err := decodeFields(p.body)
+	if err != nil {
+		return err
+	}
+
+	switch p.emsg {
+	case emsgClientLogOnResponse:
+		event := &LoggedOnEvent{
+			EResult:   int32(f.uint(1)),
+			SteamID:   p.header.GetSteamid(),
+			CellID:    uint32(f.uint(7)),
+			Heartbeat: time.Duration(f.uint(3)) * time.Second,
+		}
+
+		if event.EResult == 1 {
+			c.mu.Lock()
+			c.steamID = p.header.GetSteamid()
+			c.sessionID = p.header.GetClientSessionid()
+			c.mu.Unlock()
+
+			if event.Heartbeat > 0 {
+				go c.heartbeat(event.Heartbeat)
+			}
+		}
+
+		c.events <- event
+	case emsgClientLoggedOff:
+		c.events <- &LoggedOffEvent{EResult: int32(f.uint(1))}
+	case emsgClientPersonaState:
+		for _, friend := range f.messages(2) {
+			c.events <- &PersonaStateEvent{
+				SteamID:      friend.uint(1),
+				PersonaState: uint32(friend.uint(2)),
+				GameAppID:    uint32(friend.uint(3)),
+				Name:         friend.string(15),
+				GameName:     friend.string(55),
+			}
+		}
+	case emsgClientFriendMsgIncoming:
+		c.events <- &FriendMessageEvent{
+			From:      f.uint(1),
+			EntryType: int32(f.uint(2)),
+			Message:   string(bytesTrimNull(f.bytes(4))),
+			Timestamp: time.Unix(int64(f.uint(5)), 0),
+		}
+	case emsgServiceMethod:
+		if p.header.GetTargetJobName() == "FriendMessagesClient.IncomingMessage#1" {
+			c.events <- &FriendMessageEvent{
+				From:      f.uint(1),
+				EntryType: int32(f.uint(2)),
+				Message:   f.string(4),
+				Timestamp: time.Unix(int64(f.uint(5)), 0),
+			}
+		}
+	case emsgClientUserNotifications:
+		for _, n := range f.messages(1) {
+			if n.uint(1) == userNotificationTradeOffers {
+				c.events <- &TradeOffersEvent{Count: uint32(n.uint(2))}
+			}
+		}
+	case emsgClientItemAnnouncements:
+		c.events <- &NewItemsEvent{Count: uint32(f.uint(1))}
+	}
+
+	return nil
+}
+
+// bytesTrimNull drops the terminating NUL of the legacy chat messages.
+func bytesTrimNull(b []byte) []byte {
+	for len(b) != 0 && b[len(b)-1] == 0 {
+		b = b[:len(b)-1]
+	}
+
+	return b
+}
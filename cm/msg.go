@@ -0,0 +1,229 @@
+package cm
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"io"
+
+	"github.com/hiship/go-steam/pb"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+)
+
+// EMsg values of the messages the client sends or understands.
+const (
+	emsgMulti                   = 1
+	emsgServiceMethod           = 146
+	emsgClientHeartBeat         = 703
+	emsgClientLogOff            = 706
+	emsgClientChangeStatus      = 716
+	emsgClientLogOnResponse     = 751
+	emsgClientLoggedOff         = 757
+	emsgClientPersonaState      = 766
+	emsgClientFriendMsgIncoming = 5427
+	emsgClientLogon             = 5514
+	emsgClientItemAnnouncements = 5576
+	emsgClientUserNotifications = 5599
+
+	protoMask = 0x80000000
+
+	protocolVersion = 65580
+)
+
+var ErrInvalidPacket = errors.New("invalid packet")
+
+/* The messages below are encoded and decoded by hand with protowire, only
+ * the fields the client uses are listed.  Field numbers are those of the
+ * steammessages_clientserver*.proto definitions.  */
+
+// packet is one message of the CM protocol.
+type packet struct {
+	emsg   uint32
+	header *pb.CMsgProtoBufHeader
+	body   []byte
+}
+
+func (p *packet) marshal() ([]byte, error) {
+	header, err := proto.Marshal(p.header)
+	if err != nil {
+		return nil, err
+	}
+
+	b := binary.LittleEndian.AppendUint32(nil, p.emsg|protoMask)
+	b = binary.LittleEndian.AppendUint32(b, uint32(len(header)))
+	b = append(b, header...)
+	return append(b, p.body...), nil
+}
+
+func parsePacket(b []byte) (*packet, error) {
+	if len(b) < 8 {
+		return nil, ErrInvalidPacket
+	}
+
+	emsg := binary.LittleEndian.Uint32(b)
+	if emsg&protoMask == 0 {
+		// Only the legacy extended header messages are not protobuf,
+		// none of which the client handles.
+		return &packet{emsg: emsg, header: &pb.CMsgProtoBufHeader{}}, nil
+	}
+
+	size := binary.LittleEndian.Uint32(b[4:])
+	if uint64(size) > uint64(len(b)-8) {
+		return nil, ErrInvalidPacket
+	}
+
+	header := &pb.CMsgProtoBufHeader{}
+	if err := proto.Unmarshal(b[8:8+size], header); err != nil {
+		return nil, err
+	}
+
+	return &packet{emsg: emsg &^ protoMask, header: header, body: b[8+size:]}, nil
+}
+
+// splitMulti unpacks a CMsgMulti into its packets.
+func splitMulti(body []byte) ([][]byte, error) {
+	multi := &pb.CMsgMulti{}
+	if err := proto.Unmarshal(body, multi); err != nil {
+		return nil, err
+	}
+
+	data := multi.MessageBody
+	if multi.GetSizeUnzipped() != 0 {
+		r, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+
+		if data, err = io.ReadAll(io.LimitReader(r, int64(multi.GetSizeUnzipped())+1)); err != nil {
+			return nil, err
+		}
+	}
+
+	var packets [][]byte
+	for len(data) != 0 {
+		if len(data) < 4 {
+			return nil, ErrInvalidPacket
+		}
+
+		size := binary.LittleEndian.Uint32(data)
+		if uint64(size) > uint64(len(data)-4) {
+			return nil, ErrInvalidPacket
+		}
+
+		packets = append(packets, data[4:4+size])
+		data = data[4+size:]
+	}
+
+	return packets, nil
+}
+
+// fields is a decoded message, the values of every occurrence of each
+// field number.  Varints and fixed integers are uint64, length delimited
+// fields []byte.
+type fields map[protowire.Number][]interface{}
+
+func decodeFields(b []byte) (fields, error) {
+	f := make(fields)
+	for len(b) != 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return nil, protowire.ParseError(n)
+		}
+		b = b[n:]
+
+		var v interface{}
+		switch typ {
+		case protowire.VarintType:
+			v, n = protowire.ConsumeVarint(b)
+		case protowire.Fixed32Type:
+			var u uint32
+			u, n = protowire.ConsumeFixed32(b)
+			v = uint64(u)
+		case protowire.Fixed64Type:
+			v, n = protowire.ConsumeFixed64(b)
+		case protowire.BytesType:
+			v, n = protowire.ConsumeBytes(b)
+		default:
+			n = protowire.ConsumeFieldValue(num, typ, b)
+		}
+		if n < 0 {
+			return nil, protowire.ParseError(n)
+		}
+		b = b[n:]
+
+		if v != nil {
+			f[num] = append(f[num], v)
+		}
+	}
+
+	return f, nil
+}
+
+func (f fields) uint(num protowire.Number) uint64 {
+	if values := f[num]; len(values) != 0 {
+		if v, ok := values[len(values)-1].(uint64); ok {
+			return v
+		}
+	}
+
+	return 0
+}
+
+func (f fields) bytes(num protowire.Number) []byte {
+	if values := f[num]; len(values) != 0 {
+		if v, ok := values[len(values)-1].([]byte); ok {
+			return v
+		}
+	}
+
+	return nil
+}
+
+func (f fields) string(num protowire.Number) string {
+	return string(f.bytes(num))
+}
+
+func (f fields) messages(num protowire.Number) []fields {
+	var messages []fields
+	for _, v := range f[num] {
+		if b, ok := v.([]byte); ok {
+			if m, err := decodeFields(b); err == nil {
+				messages = append(messages, m)
+			}
+		}
+	}
+
+	return messages
+}
+
+func appendVarint(b []byte, num protowire.Number, v uint64) []byte {
+	b = protowire.AppendTag(b, num, protowire.VarintType)
+	return protowire.AppendVarint(b, v)
+}
+
+func appendFixed64(b []byte, num protowire.Number, v uint64) []byte {
+	b = protowire.AppendTag(b, num, protowire.Fixed64Type)
+	return protowire.AppendFixed64(b, v)
+}
+
+func appendString(b []byte, num protowire.Number, s string) []byte {
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendString(b, s)
+}
+
+// clientLogon is a CMsgClientLogon authenticated with a refresh token.
+func clientLogon(details *LogOnDetails) []byte {
+	var b []byte
+	b = appendVarint(b, 1, protocolVersion)           // protocol_version
+	b = appendVarint(b, 3, uint64(details.CellID))    // cell_id
+	b = appendString(b, 6, details.language())        // client_language
+	b = appendVarint(b, 7, uint64(details.osType()))  // client_os_type
+	b = appendVarint(b, 8, 1)                         // should_remember_password
+	b = appendFixed64(b, 22, uint64(details.SteamID)) // client_supplied_steam_id
+	b = appendVarint(b, 33, 2)                        // chat_mode, new chat
+	b = appendString(b, 50, details.AccountName)      // account_name
+	b = appendVarint(b, 102, 1)                       // supports_rate_limit_response
+	return appendString(b, 108, details.RefreshToken) // access_token
+}
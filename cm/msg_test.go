@@ -0,0 +1,235 @@
+package cm
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/hiship/go-steam/pb"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+)
+
+// The frames below are laid out byte for byte as the CM sends them, with
+// made-up values.
+const (
+	// ClientLogOnResponse, steamid 76561197960287930, session 1234,
+	// eresult OK, heartbeat 9s, cell 42.
+	logOnResponseFrame = "ef020080" + "0c000000" + "09ba56000001001001" + "10d209" + "08011809382a"
+
+	// ClientLoggedOff with LoggedInElsewhere and ClientItemAnnouncements
+	// with 3 new items, each prefixed with its size.
+	multiPackets = "0a000000" + "f5020080000000000806" + "0a000000" + "c8150080000000000803"
+)
+
+func decodeHex(t *testing.T, s string) []byte {
+	t.Helper()
+
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return b
+}
+
+func TestParsePacket(t *testing.T) {
+	tests := []struct {
+		name      string
+		frame     string
+		emsg      uint32
+		steamID   uint64
+		sessionID int32
+		body      string
+		err       error
+	}{
+		{"protobuf", logOnResponseFrame, emsgClientLogOnResponse, 76561197960287930, 1234, "08011809382a", nil},
+		{"legacy header", "ef02000000000000", emsgClientLogOnResponse, 0, 0, "", nil},
+		{"empty header", "c81500800000000008" + "03", emsgClientItemAnnouncements, 0, 0, "0803", nil},
+		{"too short", "ef020080", 0, 0, 0, "", ErrInvalidPacket},
+		{"header past the end", "ef0200800c00000009ba56", 0, 0, 0, "", ErrInvalidPacket},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := parsePacket(decodeHex(t, tt.frame))
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("got %v, want %v", err, tt.err)
+			}
+			if err != nil {
+				return
+			}
+
+			if p.emsg != tt.emsg || p.header.GetSteamid() != tt.steamID || p.header.GetClientSessionid() != tt.sessionID {
+				t.Errorf("got emsg %d, steamid %d, session %d", p.emsg, p.header.GetSteamid(), p.header.GetClientSessionid())
+			}
+			if got := hex.EncodeToString(p.body); tt.body != "" && got != tt.body {
+				t.Errorf("body %s, want %s", got, tt.body)
+			}
+		})
+	}
+}
+
+func TestPacketRoundTrip(t *testing.T) {
+	frame := decodeHex(t, logOnResponseFrame)
+	p, err := parsePacket(frame)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := p.marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(b, frame) {
+		t.Errorf("marshaled %x, want %x", b, frame)
+	}
+}
+
+func multiBody(t *testing.T, data []byte, zipped bool) []byte {
+	t.Helper()
+
+	multi := &pb.CMsgMulti{MessageBody: data}
+	if zipped {
+		var buf bytes.Buffer
+		w := gzip.NewWriter(&buf)
+		w.Write(data)
+		w.Close()
+
+		multi.MessageBody = buf.Bytes()
+		multi.SizeUnzipped = proto.Uint32(uint32(len(data)))
+	}
+
+	b, err := proto.Marshal(multi)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return b
+}
+
+func TestSplitMulti(t *testing.T) {
+	packets := decodeHex(t, multiPackets)
+
+	tests := []struct {
+		name  string
+		body  []byte
+		emsgs []uint32
+		err   error
+	}{
+		{"plain", decodeHex(t, "121c"+multiPackets), []uint32{emsgClientLoggedOff, emsgClientItemAnnouncements}, nil},
+		{"gzipped", multiBody(t, packets, true), []uint32{emsgClientLoggedOff, emsgClientItemAnnouncements}, nil},
+		{"empty", multiBody(t, nil, false), nil, nil},
+		{"truncated size", multiBody(t, packets[:2], false), nil, ErrInvalidPacket},
+		{"packet past the end", multiBody(t, packets[:12], false), nil, ErrInvalidPacket},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := splitMulti(tt.body)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("got %v, want %v", err, tt.err)
+			}
+
+			if len(got) != len(tt.emsgs) {
+				t.Fatalf("got %d packets, want %d", len(got), len(tt.emsgs))
+			}
+			for i, b := range got {
+				p, err := parsePacket(b)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if p.emsg != tt.emsgs[i] {
+					t.Errorf("packet %d is emsg %d, want %d", i, p.emsg, tt.emsgs[i])
+				}
+			}
+		})
+	}
+}
+
+func TestDecodeFields(t *testing.T) {
+	tests := []struct {
+		name  string
+		msg   string
+		check func(t *testing.T, f fields)
+		err   bool
+	}{
+		{"varints", "08011809382a", func(t *testing.T, f fields) {
+			if f.uint(1) != 1 || f.uint(3) != 9 || f.uint(7) != 42 || f.uint(2) != 0 {
+				t.Errorf("got %v", f)
+			}
+		}, false},
+		{"fixed", "09ba560000010010011d2a000000", func(t *testing.T, f fields) {
+			if f.uint(1) != 76561197960287930 || f.uint(3) != 42 {
+				t.Errorf("got %v", f)
+			}
+		}, false},
+		{"string", "2205" + hex.EncodeToString([]byte("hello")), func(t *testing.T, f fields) {
+			if f.string(4) != "hello" || f.bytes(5) != nil {
+				t.Errorf("got %v", f)
+			}
+		}, false},
+		{"last occurrence wins", "08010802", func(t *testing.T, f fields) {
+			if f.uint(1) != 2 || len(f[1]) != 2 {
+				t.Errorf("got %v", f)
+			}
+		}, false},
+		// CMsgClientPersonaState with two friends.
+		{"nested", "120b09ba56000001001001" + "1001" + "121109ba56000001001001" + "1001" + "7a04" + hex.EncodeToString([]byte("name")), func(t *testing.T, f fields) {
+			friends := f.messages(2)
+			if len(friends) != 2 || friends[0].uint(1) != 76561197960287930 || friends[0].uint(2) != 1 {
+				t.Errorf("got %v", friends)
+			}
+		}, false},
+		{"wrong type read", "0801", func(t *testing.T, f fields) {
+			if f.string(1) != "" {
+				t.Errorf("a varint read as a string")
+			}
+		}, false},
+		{"truncated varint", "0880", nil, true},
+		{"truncated bytes", "2205686568", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := decodeFields(decodeHex(t, tt.msg))
+			if (err != nil) != tt.err {
+				t.Fatalf("got error %v", err)
+			}
+			if tt.check != nil {
+				tt.check(t, f)
+			}
+		})
+	}
+}
+
+func TestClientLogon(t *testing.T) {
+	got := clientLogon(&LogOnDetails{
+		AccountName:  "bot",
+		SteamID:      76561197960287930,
+		RefreshToken: "eyJ",
+		CellID:       4,
+	})
+
+	// protocol_version, cell_id, client_language, client_os_type,
+	// should_remember_password, client_supplied_steam_id, chat_mode,
+	// account_name, supports_rate_limit_response (102) and access_token
+	// (108) as steammessages_clientserver_login.proto numbers them.
+	want := "08ac8004" + "1804" + "3207656e676c697368" + "3810" + "4001" + "b101ba56000001001001" + "880202" + "920303626f74" + "b00601" + "e2060365794a"
+	if hex.EncodeToString(got) != want {
+		t.Errorf("got  %x\nwant %s", got, want)
+	}
+
+	f, err := decodeFields(got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := f[protowire.Number(105)]; ok {
+		t.Error("the logon sets embedded_client_secret")
+	}
+	if f.string(108) != "eyJ" {
+		t.Errorf("access_token %q", f.string(108))
+	}
+}
@@ -0,0 +1,142 @@
+package steam
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+const (
+	commentProfileURL = "/comment/Profile/%s/%d/-1/"
+)
+
+var ErrCannotLoadComments = errors.New("unable to load comments")
+
+type Comment struct {
+	ID        uint64
+	Author    SteamID
+	Text      string
+	Timestamp int64
+}
+
+type commentResponse struct {
+	Success      bool   `json:"success"`
+	Error        string `json:"error"`
+	Start        int    `json:"start"`
+	TotalCount   int    `json:"total_count"`
+	CommentsHTML string `json:"comments_html"`
+}
+
+func (session *Session) execCommentRequest(action string, sid SteamID, values url.Values) (*commentResponse, error) {
+	values.Set("sessionid", session.sessionID)
+	values.Set("feature2", "-1")
+
+	resp, err := session.client.PostForm(session.CommunityBaseURL()+fmt.Sprintf(commentProfileURL, action, sid), values)
+	if resp != nil {
+		defer func(Body io.ReadCloser) {
+			err := Body.Close()
+			if err != nil {
+				return
+			}
+		}(resp.Body)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("http error: %d", resp.StatusCode)
+	}
+
+	var response commentResponse
+	if err = session.readJSON(resp, &response); err != nil {
+		return nil, err
+	}
+
+	if !response.Success {
+		if len(response.Error) != 0 {
+			return nil, errors.New(response.Error)
+		}
+
+		return nil, ErrCannotLoadComments
+	}
+
+	return &response, nil
+}
+
+func (session *Session) PostComment(sid SteamID, text string) error {
+	if err := session.checkWritable(); err != nil {
+		return err
+	}
+
+	_, err := session.execCommentRequest("post", sid, url.Values{
+		"comment": {text},
+		"count":   {"6"},
+	})
+	return err
+}
+
+func (session *Session) DeleteComment(sid SteamID, commentID uint64) error {
+	if err := session.checkWritable(); err != nil {
+		return err
+	}
+
+	_, err := session.execCommentRequest("delete", sid, url.Values{
+		"gidcomment": {strconv.FormatUint(commentID, 10)},
+		"start":      {"0"},
+		"count":      {"6"},
+	})
+	return err
+}
+
+// GetComments returns count comments of the profile thread starting at
+// start (newest first) together with the total amount of comments.
+func (session *Session) GetComments(sid SteamID, start, count int) ([]*Comment, int, error) {
+	response, err := session.execCommentRequest("render", sid, url.Values{
+		"start": {strconv.Itoa(start)},
+		"count": {strconv.Itoa(count)},
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(response.CommentsHTML))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	comments := []*Comment{}
+	doc.Find(".commentthread_comment").Each(func(_ int, s *goquery.Selection) {
+		id, _ := s.Attr("id")
+		commentID, err := strconv.ParseUint(strings.TrimPrefix(id, "comment_"), 10, 64)
+		if err != nil {
+			return
+		}
+
+		comment := &Comment{
+			ID:   commentID,
+			Text: strings.TrimSpace(s.Find(".commentthread_comment_text").Text()),
+		}
+
+		if accountID, ok := s.Find(".commentthread_author_link").Attr("data-miniprofile"); ok {
+			if v, err := strconv.ParseUint(accountID, 10, 32); err == nil {
+				comment.Author.ParseDefaults(uint32(v))
+			}
+		}
+
+		if ts, ok := s.Find(".commentthread_comment_timestamp").Attr("data-timestamp"); ok {
+			comment.Timestamp, _ = strconv.ParseInt(ts, 10, 64)
+		}
+
+		comments = append(comments, comment)
+	})
+
+	return comments, response.TotalCount, nil
+}
@@ -3,40 +3,116 @@ package steam
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"net/url"
 	"strconv"
+	"strings"
+	"time"
 )
 
+type ConfirmationType uint8
+
+const (
+	ConfirmationTypeGeneric           ConfirmationType = 1
+	ConfirmationTypeTrade             ConfirmationType = 2
+	ConfirmationTypeMarketListing     ConfirmationType = 3
+	ConfirmationTypeFeatureOptOut     ConfirmationType = 4
+	ConfirmationTypePhoneNumberChange ConfirmationType = 5
+	ConfirmationTypeAccountRecovery   ConfirmationType = 6
+	ConfirmationTypeAPIKeyCreation    ConfirmationType = 9
+	ConfirmationTypeLogin             ConfirmationType = 12 // new device login approval
+)
+
+func (t ConfirmationType) String() string {
+	switch t {
+	case ConfirmationTypeGeneric:
+		return "Generic"
+	case ConfirmationTypeTrade:
+		return "Trade"
+	case ConfirmationTypeMarketListing:
+		return "MarketListing"
+	case ConfirmationTypeFeatureOptOut:
+		return "FeatureOptOut"
+	case ConfirmationTypePhoneNumberChange:
+		return "PhoneNumberChange"
+	case ConfirmationTypeAccountRecovery:
+		return "AccountRecovery"
+	case ConfirmationTypeAPIKeyCreation:
+		return "APIKeyCreation"
+	case ConfirmationTypeLogin:
+		return "Login"
+	}
+
+	return "Unknown(" + strconv.FormatUint(uint64(t), 10) + ")"
+}
+
 type ConfirmationResponse struct {
 	Success       bool            `json:"success"`
 	Confirmations []*Confirmation `json:"conf"`
 }
 
 type Confirmation struct {
-	ID           string `json:"id"`
-	Type         uint8  `json:"type"`
-	Creator      string `json:"creator_id"`
-	Nonce        string `json:"nonce"`
-	CreationTime uint64 `json:"creation"`
+	ID           string           `json:"id"`
+	Type         ConfirmationType `json:"type"`
+	Creator      string           `json:"creator_id"`
+	Nonce        string           `json:"nonce"`
+	CreationTime uint64           `json:"creation"`
+	TypeName     string           `json:"type_name"`
+	Icon         string           `json:"icon"`
+	Multi        bool             `json:"multi"`
+	Headline     string           `json:"headline"` // item name for market listings, partner for trades
+	Summary      []string         `json:"summary"`  // listing price, traded items or account change details
 
-	// TypeName string      `json:"type_name"`
 	// Cancel   string      `json:"cancel"`
 	// Accept   string      `json:"accept"`
-	// Icon     string      `json:"icon"`
-	// Multi    bool        `json:"multi"`
-	// Headline string      `json:"headline"`
-	// Summary  []string    `json:"summary"`
 	// Warn     interface{} `json:"warn"`
 }
 
+// Description joins the summary lines, for account confirmations (phone
+// number change, recovery, API key...) this is what Steam shows to the user.
+func (confirmation *Confirmation) Description() string {
+	return strings.Join(confirmation.Summary, "\n")
+}
+
+// MarketListing returns the item name and the listing price line of a
+// market listing confirmation.
+func (confirmation *Confirmation) MarketListing() (itemName, price string, ok bool) {
+	if confirmation.Type != ConfirmationTypeMarketListing {
+		return "", "", false
+	}
+
+	if len(confirmation.Summary) != 0 {
+		price = confirmation.Summary[0]
+	}
+
+	return confirmation.Headline, price, true
+}
+
+// FilterConfirmations returns the confirmations of the given types, e.g. to
+// auto-accept trades while leaving everything else for manual review.
+func FilterConfirmations(confirmations []*Confirmation, types ...ConfirmationType) []*Confirmation {
+	filtered := []*Confirmation{}
+	for _, confirmation := range confirmations {
+		for _, t := range types {
+			if confirmation.Type == t {
+				filtered = append(filtered, confirmation)
+				break
+			}
+		}
+	}
+
+	return filtered
+}
+
 var (
 	//ErrConfirmationsUnknownError = errors.New("unknown error occurred finding confirmation")
 	ErrCannotFindConfirmations   = errors.New("unable to find confirmation")
 	ErrCannotFindDescriptions    = errors.New("unable to find confirmation descriptions")
 	ErrConfirmationsDescMismatch = errors.New("cannot match confirmation with their respective descriptions")
 	ErrWGTokenExpired            = errors.New("WGToken expired")
+	ErrLoginApprovalDisabled     = errors.New("login approval is not enabled for this session")
 )
 
 func (session *Session) execConfirmationRequest(request, key, tag string, current int64, values map[string]string) (*http.Response, error) {
@@ -53,7 +129,7 @@ func (session *Session) execConfirmationRequest(request, key, tag string, curren
 		params.Add(k, v)
 	}
 
-	return session.client.Get("https://steamcommunity.com/mobileconf/" + request + params.Encode())
+	return session.client.Get(session.CommunityBaseURL() + "/mobileconf/" + request + params.Encode())
 }
 
 func (session *Session) GetConfirmations(identitySecret string, current int64) ([]*Confirmation, error) {
@@ -71,19 +147,40 @@ func (session *Session) GetConfirmations(identitySecret string, current int64) (
 		return nil, err
 	}
 
-	var confirmationResponse ConfirmationResponse
+	b, err := io.ReadAll(session.limitBody(resp))
+	if err != nil {
+		return nil, err
+	}
 
-	b, _ := io.ReadAll(resp.Body)
+	return parseConfirmations(b)
+}
 
-	//d := json.NewDecoder(resp.Body)
-	if err = json.Unmarshal(b, &confirmationResponse); err != nil || !confirmationResponse.Success {
+func parseConfirmations(b []byte) ([]*Confirmation, error) {
+	var confirmationResponse ConfirmationResponse
+	if err := json.Unmarshal(b, &confirmationResponse); err != nil {
 		return nil, err
 	}
 
+	if !confirmationResponse.Success {
+		return nil, ErrCannotFindConfirmations
+	}
+
 	return confirmationResponse.Confirmations, nil
 }
 
 func (session *Session) AnswerConfirmation(confirmation *Confirmation, identitySecret, answer string, current int64) error {
+	if err := session.checkWritable(); err != nil {
+		return err
+	}
+
+	if err := session.checkConfirmationGivePolicy(confirmation, answer); err != nil {
+		return err
+	}
+
+	if err := session.captureEvidence(confirmation, identitySecret, answer, current); err != nil {
+		return fmt.Errorf("evidence capture: %w", err)
+	}
+
 	key, err := GenerateConfirmationCode(identitySecret, answer, current)
 	if err != nil {
 		return err
@@ -101,6 +198,7 @@ func (session *Session) AnswerConfirmation(confirmation *Confirmation, identityS
 	}
 
 	if err != nil {
+		session.metricsHook().ObserveConfirmation(confirmation.Type, false)
 		return err
 	}
 
@@ -110,17 +208,133 @@ func (session *Session) AnswerConfirmation(confirmation *Confirmation, identityS
 	}
 
 	var response Response
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+	if err := session.readJSON(resp, &response); err != nil {
+		session.metricsHook().ObserveConfirmation(confirmation.Type, false)
 		return err
 	}
+	session.metricsHook().ObserveConfirmation(confirmation.Type, response.Success)
+
+	if session.storage != nil {
+		err = session.storage.SaveConfirmation(&ConfirmationRecord{
+			ID:        confirmation.ID,
+			Type:      confirmation.Type,
+			Creator:   confirmation.Creator,
+			Answer:    answer,
+			Success:   response.Success,
+			Message:   response.Message,
+			Timestamp: time.Now(),
+		})
+		if err != nil && response.Success {
+			return fmt.Errorf("confirmation answered but not stored: %w", err)
+		}
+	}
 
 	if !response.Success {
 		return errors.New(response.Message)
 	}
 
+	if confirmation.Type == ConfirmationTypeTrade || confirmation.Type == ConfirmationTypeMarketListing {
+		session.InvalidateInventory(session.oauth.SteamID)
+	}
+
+	session.stats.addConfirmation(time.Since(time.Unix(int64(confirmation.CreationTime), 0)))
 	return nil
 }
 
 func (confirmation *Confirmation) Answer(session *Session, key, answer string, current int64) error {
 	return session.AnswerConfirmation(confirmation, key, answer, current)
 }
+
+// IsLoginRequest reports whether the confirmation approves a login from a
+// new device.
+func (confirmation *Confirmation) IsLoginRequest() bool {
+	return confirmation.Type == ConfirmationTypeLogin ||
+		strings.Contains(strings.ToLower(confirmation.TypeName), "login")
+}
+
+// SetLoginApproval allows ApproveLoginRequests to answer login
+// confirmations, it is off by default.
+func (session *Session) SetLoginApproval(enabled bool) {
+	session.loginApproval = enabled
+}
+
+// ApproveLoginRequests allows every pending login confirmation and returns
+// the ones that were approved.
+func (session *Session) ApproveLoginRequests(identitySecret string, current int64) ([]*Confirmation, error) {
+	if !session.loginApproval {
+		return nil, ErrLoginApprovalDisabled
+	}
+
+	confirmations, err := session.GetConfirmations(identitySecret, current)
+	if err != nil {
+		return nil, err
+	}
+
+	approved := []*Confirmation{}
+	for _, confirmation := range confirmations {
+		if !confirmation.IsLoginRequest() {
+			continue
+		}
+
+		if err = session.AnswerConfirmation(confirmation, identitySecret, "allow", current); err != nil {
+			return approved, err
+		}
+
+		approved = append(approved, confirmation)
+	}
+
+	return approved, nil
+}
+
+// captureEvidence saves what Steam shows about confirmation before it is
+// answered, see SetEvidenceCapture.
+func (session *Session) captureEvidence(confirmation *Confirmation, identitySecret, answer string, current int64) error {
+	if session.evidenceCapture == nil || !session.evidenceCapture(confirmation) {
+		return nil
+	}
+
+	store, ok := session.storage.(EvidenceStorage)
+	if !ok {
+		return ErrNoEvidenceStorage
+	}
+
+	key, err := GenerateConfirmationCode(identitySecret, "details", current)
+	if err != nil {
+		return err
+	}
+
+	resp, err := session.execConfirmationRequest("detailspage/"+confirmation.ID+"?", key, "details", current, nil)
+	if resp != nil {
+		defer resp.Body.Close()
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("http error: %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(session.limitBody(resp))
+	if err != nil {
+		return err
+	}
+
+	evidence := &ConfirmationEvidence{
+		Confirmation: confirmation,
+		DetailsHTML:  string(body),
+		Answer:       answer,
+		CapturedAt:   time.Now(),
+	}
+
+	if confirmation.Type == ConfirmationTypeTrade {
+		if offerID, err := strconv.ParseUint(confirmation.Creator, 10, 64); err == nil {
+			if evidence.Offer, err = session.GetTradeOffer(offerID); err != nil {
+				return err
+			}
+		}
+	}
+
+	return store.SaveEvidence(evidence)
+}
@@ -0,0 +1,119 @@
+package currency
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Amount is a price in cents, the unit every market and wallet endpoint
+// uses.  Integer cents keep sums and fees exact where float64 would not.
+type Amount int64
+
+// Add returns a+b.
+func (a Amount) Add(b Amount) Amount {
+	return a + b
+}
+
+// Sub returns a-b.
+func (a Amount) Sub(b Amount) Amount {
+	return a - b
+}
+
+// Mul returns a times n, e.g. the price of n items.
+func (a Amount) Mul(n int64) Amount {
+	return a * Amount(n)
+}
+
+// MulDiv returns a*num/den rounded down, e.g. a.MulDiv(5, 100) for 5% of
+// a.  It panics when den is zero.
+func (a Amount) MulDiv(num, den int64) Amount {
+	return Amount(int64(a) * num / den)
+}
+
+// Split divides a into n parts that differ by at most a cent and add up
+// to a, the larger ones first.
+func (a Amount) Split(n int) []Amount {
+	if n <= 0 {
+		return nil
+	}
+
+	parts := make([]Amount, n)
+	each, rest := a/Amount(n), a%Amount(n)
+	for i := range parts {
+		parts[i] = each
+		if Amount(i) < rest {
+			parts[i]++
+		}
+	}
+
+	return parts
+}
+
+// String formats a without any currency, e.g. "12.34" or "-0.05".
+func (a Amount) String() string {
+	sign := ""
+	if a < 0 {
+		sign, a = "-", -a
+	}
+
+	return sign + strconv.FormatInt(int64(a/100), 10) + "." + twoDigits(int64(a%100))
+}
+
+// Format writes a the way Steam writes prices in id, e.g. "$1.23",
+// "12,34€" or "¥ 1,234".  An unknown id is formatted as String does.
+func (a Amount) Format(id ID) string {
+	info, ok := infos[id]
+	if !ok {
+		return a.String()
+	}
+
+	sign := ""
+	if a < 0 {
+		sign, a = "-", -a
+	}
+
+	number := groupThousands(strconv.FormatInt(int64(a/100), 10), info.Thousands)
+	if !info.WholeUnits {
+		number += info.Decimal + twoDigits(int64(a%100))
+	}
+
+	space := ""
+	if info.SymbolSpace {
+		space = " "
+	}
+
+	if info.SymbolAfter {
+		return sign + number + space + info.Symbol
+	}
+
+	return sign + info.Symbol + space + number
+}
+
+func twoDigits(n int64) string {
+	if n < 10 {
+		return "0" + strconv.FormatInt(n, 10)
+	}
+
+	return strconv.FormatInt(n, 10)
+}
+
+func groupThousands(digits, sep string) string {
+	if len(digits) <= 3 {
+		return digits
+	}
+
+	var b strings.Builder
+	first := len(digits) % 3
+	if first != 0 {
+		b.WriteString(digits[:first])
+	}
+
+	for i := first; i < len(digits); i += 3 {
+		if b.Len() != 0 {
+			b.WriteString(sep)
+		}
+		b.WriteString(digits[i : i+3])
+	}
+
+	return b.String()
+}
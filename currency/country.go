@@ -0,0 +1,28 @@
+package currency
+
+import "strings"
+
+// countries maps a country code to the wallet currency Steam uses there.
+var countries = map[string]ID{
+	"US": USD, "GB": GBP, "CH": CHF, "RU": RUB,
+	"PL": PLN, "BR": BRL, "JP": JPY, "NO": NOK,
+	"ID": IDR, "MY": MYR, "PH": PHP, "SG": SGD,
+	"TH": THB, "VN": VND, "KR": KRW, "UA": UAH,
+	"MX": MXN, "CA": CAD, "AU": AUD, "NZ": NZD,
+	"CN": CNY, "IN": INR, "CL": CLP, "PE": PEN,
+	"CO": COP, "ZA": ZAR, "HK": HKD, "TW": TWD,
+	"SA": SAR, "AE": AED, "IL": ILS, "KZ": KZT,
+	"KW": KWD, "QA": QAR, "CR": CRC, "UY": UYU,
+	"AT": EUR, "BE": EUR, "CY": EUR, "DE": EUR,
+	"EE": EUR, "ES": EUR, "FI": EUR, "FR": EUR,
+	"GR": EUR, "HR": EUR, "IE": EUR, "IT": EUR,
+	"LT": EUR, "LU": EUR, "LV": EUR, "MT": EUR,
+	"NL": EUR, "PT": EUR, "SI": EUR, "SK": EUR,
+}
+
+// ForCountry returns the wallet currency of accounts in the country with
+// the ISO code, false for the countries not known to have their own.
+func ForCountry(code string) (ID, bool) {
+	id, ok := countries[strings.ToUpper(code)]
+	return id, ok
+}
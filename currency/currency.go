@@ -0,0 +1,315 @@
+// Package currency knows the currencies of the Steam wallet and market:
+// their IDs, how Steam writes prices in each of them and how to read those
+// prices back as integer cents:
+//
+//	a, id, err := currency.Parse("12,34€") // 1234, currency.EUR
+//	fmt.Println(a.Add(100).Format(id))     // 13,34€
+//
+// Amounts are always in cents, also for the currencies Steam shows in whole
+// units only.
+package currency
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ID is a Steam currency ID, the wallet_currency of a wallet and the
+// currency parameter of the market endpoints.
+type ID uint32
+
+const (
+	USD ID = 1
+	GBP ID = 2
+	EUR ID = 3
+	CHF ID = 4
+	RUB ID = 5
+	PLN ID = 6
+	BRL ID = 7
+	JPY ID = 8
+	NOK ID = 9
+	IDR ID = 10
+	MYR ID = 11
+	PHP ID = 12
+	SGD ID = 13
+	THB ID = 14
+	VND ID = 15
+	KRW ID = 16
+	TRY ID = 17
+	UAH ID = 18
+	MXN ID = 19
+	CAD ID = 20
+	AUD ID = 21
+	NZD ID = 22
+	CNY ID = 23
+	INR ID = 24
+	CLP ID = 25
+	PEN ID = 26
+	COP ID = 27
+	ZAR ID = 28
+	HKD ID = 29
+	TWD ID = 30
+	SAR ID = 31
+	AED ID = 32
+	ARS ID = 34
+	ILS ID = 35
+	BYN ID = 36
+	KZT ID = 37
+	KWD ID = 38
+	QAR ID = 39
+	CRC ID = 40
+	UYU ID = 41
+	RMB ID = 9000
+)
+
+var (
+	ErrUnknownCurrency = errors.New("unknown currency")
+	ErrInvalidPrice    = errors.New("invalid price")
+)
+
+// Info is how Steam writes prices in a currency.
+type Info struct {
+	ID          ID
+	Code        string // ISO 4217
+	Symbol      string
+	SymbolAfter bool   // "1,23€" rather than "$1.23"
+	SymbolSpace bool   // a space between the symbol and the number
+	Decimal     string // decimal separator
+	Thousands   string // thousands separator
+	WholeUnits  bool   // shown without cents
+}
+
+var infos = map[ID]*Info{
+	USD: {USD, "USD", "$", false, false, ".", ",", false},
+	GBP: {GBP, "GBP", "£", false, false, ".", ",", false},
+	EUR: {EUR, "EUR", "€", true, false, ",", " ", false},
+	CHF: {CHF, "CHF", "CHF", false, true, ".", " ", false},
+	RUB: {RUB, "RUB", "pуб.", true, true, ",", " ", false},
+	PLN: {PLN, "PLN", "zł", true, false, ",", " ", false},
+	BRL: {BRL, "BRL", "R$", false, true, ",", ".", false},
+	JPY: {JPY, "JPY", "¥", false, true, ".", ",", true},
+	NOK: {NOK, "NOK", "kr", true, true, ",", ".", false},
+	IDR: {IDR, "IDR", "Rp", false, true, ".", " ", true},
+	MYR: {MYR, "MYR", "RM", false, false, ".", ",", false},
+	PHP: {PHP, "PHP", "P", false, false, ".", ",", false},
+	SGD: {SGD, "SGD", "S$", false, false, ".", ",", false},
+	THB: {THB, "THB", "฿", false, false, ".", ",", false},
+	VND: {VND, "VND", "₫", true, false, ",", ".", true},
+	KRW: {KRW, "KRW", "₩", false, true, ".", ",", true},
+	TRY: {TRY, "TRY", "TL", true, true, ",", ".", false},
+	UAH: {UAH, "UAH", "₴", true, false, ",", " ", true},
+	MXN: {MXN, "MXN", "Mex$", false, true, ".", ",", false},
+	CAD: {CAD, "CAD", "CDN$", false, true, ".", ",", false},
+	AUD: {AUD, "AUD", "A$", false, true, ".", ",", false},
+	NZD: {NZD, "NZD", "NZ$", false, true, ".", ",", false},
+	CNY: {CNY, "CNY", "¥", false, true, ".", ",", false},
+	INR: {INR, "INR", "₹", false, true, ".", ",", true},
+	CLP: {CLP, "CLP", "CLP$", false, true, ",", ".", true},
+	PEN: {PEN, "PEN", "S/.", false, false, ".", ",", false},
+	COP: {COP, "COP", "COL$", false, true, ",", ".", true},
+	ZAR: {ZAR, "ZAR", "R", false, true, ".", " ", false},
+	HKD: {HKD, "HKD", "HK$", false, true, ".", ",", false},
+	TWD: {TWD, "TWD", "NT$", false, true, ".", ",", true},
+	SAR: {SAR, "SAR", "SR", true, true, ".", ",", false},
+	AED: {AED, "AED", "AED", true, true, ".", ",", false},
+	ARS: {ARS, "ARS", "ARS$", false, true, ",", ".", false},
+	ILS: {ILS, "ILS", "₪", false, false, ".", ",", false},
+	BYN: {BYN, "BYN", "Br", false, false, ".", ",", false},
+	KZT: {KZT, "KZT", "₸", true, false, ",", " ", true},
+	KWD: {KWD, "KWD", "KD", true, true, ".", ",", false},
+	QAR: {QAR, "QAR", "QR", true, true, ".", ",", false},
+	CRC: {CRC, "CRC", "₡", false, false, ",", ".", true},
+	UYU: {UYU, "UYU", "$U", false, false, ",", ".", true},
+	RMB: {RMB, "CNY", "¥", false, true, ".", ",", false},
+}
+
+// symbols are the symbols and codes Parse recognizes, longest first so
+// "CDN$" wins over "$".  An ambiguous symbol goes to the currency with the
+// lowest ID, e.g. "$" to USD and "¥" to JPY.
+var symbols []symbol
+
+type symbol struct {
+	text string
+	id   ID
+}
+
+func init() {
+	seen := make(map[string]bool)
+	ids := make([]ID, 0, len(infos))
+	for id := range infos {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	for _, id := range ids {
+		for _, text := range []string{infos[id].Code, infos[id].Symbol} {
+			if !seen[text] {
+				seen[text] = true
+				symbols = append(symbols, symbol{text, id})
+			}
+		}
+	}
+
+	sort.SliceStable(symbols, func(i, j int) bool { return len(symbols[i].text) > len(symbols[j].text) })
+}
+
+// Lookup returns how Steam writes prices in id.
+func Lookup(id ID) (*Info, bool) {
+	info, ok := infos[id]
+	return info, ok
+}
+
+// ParseID reads a currency given by its ID, e.g. "3" as the market
+// endpoints take it, or by its ISO code.
+func ParseID(s string) (ID, error) {
+	if n, err := strconv.ParseUint(s, 10, 32); err == nil {
+		if _, ok := infos[ID(n)]; ok {
+			return ID(n), nil
+		}
+
+		return 0, ErrUnknownCurrency
+	}
+
+	s = strings.ToUpper(strings.TrimSpace(s))
+	for id, info := range infos {
+		if info.Code == s && id != RMB {
+			return id, nil
+		}
+	}
+
+	return 0, ErrUnknownCurrency
+}
+
+// String returns the ISO code of the currency.
+func (id ID) String() string {
+	if info, ok := infos[id]; ok {
+		return info.Code
+	}
+
+	return "Unknown(" + strconv.FormatUint(uint64(id), 10) + ")"
+}
+
+// Param returns the ID as the market endpoints take it, see the
+// steam.Currency* constants.
+func (id ID) Param() string {
+	return strconv.FormatUint(uint64(id), 10)
+}
+
+// Detect returns the currency a price string is written in, judging by
+// its symbol or code.
+func Detect(text string) (ID, bool) {
+	for _, s := range symbols {
+		if strings.Contains(text, s.text) {
+			return s.id, true
+		}
+	}
+
+	return 0, false
+}
+
+// Parse reads a price as Steam writes it, e.g. "12,34€", "$1.23 USD" or
+// "¥ 1,234", and returns it in cents along with the currency it is in,
+// zero when the string names none.
+func Parse(text string) (Amount, ID, error) {
+	id, ok := Detect(text)
+	if !ok {
+		a, err := parseNumber(text, "")
+		return a, 0, err
+	}
+
+	a, err := ParseIn(text, id)
+	return a, id, err
+}
+
+// ParseIn reads a price known to be in id, which settles whether a "," or
+// a "." is the decimal separator.
+func ParseIn(text string, id ID) (Amount, error) {
+	info, ok := infos[id]
+	if !ok {
+		return 0, ErrUnknownCurrency
+	}
+
+	return parseNumber(text, info.Decimal)
+}
+
+// parseNumber reads the first number of text in cents, rounded to the
+// nearest cent.  Without a decimal separator the last "," or "." is taken
+// for one unless three digits follow it.
+func parseNumber(text, decimal string) (Amount, error) {
+	number, negative := numberRun(text)
+	if len(number) == 0 {
+		return 0, ErrInvalidPrice
+	}
+
+	whole, frac := number, ""
+	if decimal == "" {
+		if i := strings.LastIndexAny(number, ".,"); i >= 0 && len(number)-i-1 <= 2 {
+			whole, frac = number[:i], number[i+1:]
+		}
+	} else if i := strings.LastIndex(number, decimal); i >= 0 {
+		whole, frac = number[:i], number[i+1:]
+	}
+
+	whole = strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, whole)
+
+	var a int64
+	if len(whole) != 0 {
+		n, err := strconv.ParseInt(whole, 10, 64)
+		if err != nil {
+			return 0, ErrInvalidPrice
+		}
+		a = n * 100
+	}
+
+	switch len(frac) {
+	case 0:
+	case 1:
+		a += int64(frac[0]-'0') * 10
+	default:
+		a += int64(frac[0]-'0')*10 + int64(frac[1]-'0')
+
+		// Rounded half up to the cent, fractions of a cent only show up
+		// in computed prices.
+		if len(frac) > 2 && frac[2] >= '5' {
+			a++
+		}
+	}
+
+	if negative {
+		a = -a
+	}
+
+	return Amount(a), nil
+}
+
+// numberRun returns the first run of digits and separators of text, the
+// separators being ",", ".", "'" and spaces between digits, and whether a
+// minus sign came before it.
+func numberRun(text string) (string, bool) {
+	start := strings.IndexFunc(text, func(r rune) bool { return r >= '0' && r <= '9' })
+	if start < 0 {
+		return "", false
+	}
+
+	negative := strings.Contains(text[:start], "-")
+
+	end := start
+	for i, r := range text[start:] {
+		switch {
+		case r >= '0' && r <= '9':
+			end = start + i + 1
+		case r == '.' || r == ',' || r == '\'' || r == ' ' || r == '\u00a0':
+		default:
+			return text[start:end], negative
+		}
+	}
+
+	return text[start:end], negative
+}
@@ -0,0 +1,136 @@
+package currency
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		text   string
+		amount Amount
+		id     ID
+		err    error
+	}{
+		{"$1.23", 123, USD, nil},
+		{"$1.23 USD", 123, USD, nil},
+		{"12,34€", 1234, EUR, nil},
+		{"1 234,56€", 123456, EUR, nil},
+		{"1 234,56 pуб.", 123456, RUB, nil},
+		{"R$ 1.234,56", 123456, BRL, nil},
+		{"CDN$ 1,234.50", 123450, CAD, nil},
+		{"CHF 1'234.50", 123450, CHF, nil},
+		{"¥ 1,234", 123400, JPY, nil},
+		{"-$0.05", -5, USD, nil},
+		{"-12,34€", -1234, EUR, nil},
+		{"0,005€", 1, EUR, nil},
+		{"$1.234", 123, USD, nil},
+		{"1.5", 150, 0, nil},
+		{"1.234", 123400, 0, nil},
+		{"free", 0, 0, ErrInvalidPrice},
+	}
+
+	for _, tt := range tests {
+		amount, id, err := Parse(tt.text)
+		if amount != tt.amount || id != tt.id || !errors.Is(err, tt.err) {
+			t.Errorf("Parse(%q) = %d, %v, %v, want %d, %v, %v", tt.text, amount, id, err, tt.amount, tt.id, tt.err)
+		}
+	}
+}
+
+func TestParseIn(t *testing.T) {
+	tests := []struct {
+		text   string
+		id     ID
+		amount Amount
+	}{
+		// The currency settles what the separator is.
+		{"1.234", USD, 123},
+		{"1.234", BRL, 123400},
+		{"1,234", USD, 123400},
+		{"1,234", EUR, 123},
+		{"1.235", USD, 124},
+		{"-1.235", USD, -124},
+	}
+
+	for _, tt := range tests {
+		if amount, err := ParseIn(tt.text, tt.id); amount != tt.amount || err != nil {
+			t.Errorf("ParseIn(%q, %v) = %d, %v, want %d", tt.text, tt.id, amount, err, tt.amount)
+		}
+	}
+
+	if _, err := ParseIn("1.00", ID(999)); !errors.Is(err, ErrUnknownCurrency) {
+		t.Errorf("unknown currency got %v", err)
+	}
+}
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		amount Amount
+		id     ID
+		want   string
+	}{
+		{123, USD, "$1.23"},
+		{-5, USD, "-$0.05"},
+		{123456, EUR, "1 234,56€"},
+		{123456, RUB, "1 234,56 pуб."},
+		{123400, JPY, "¥ 1,234"},
+		{123450, CHF, "CHF 1 234.50"},
+		{123456, ID(999), "1234.56"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.amount.Format(tt.id); got != tt.want {
+			t.Errorf("%d in %v = %q, want %q", tt.amount, tt.id, got, tt.want)
+		}
+	}
+}
+
+func TestFormatParseRoundTrip(t *testing.T) {
+	for id, info := range infos {
+		for _, amount := range []Amount{0, 5, 99, 123456, -98765, 100000000} {
+			if info.WholeUnits {
+				amount *= 100
+			}
+
+			text := amount.Format(id)
+			got, err := ParseIn(text, id)
+			if err != nil || got != amount {
+				t.Errorf("%v: %d formatted as %q parsed back as %d, %v", id, amount, text, got, err)
+			}
+		}
+	}
+}
+
+func TestAmount(t *testing.T) {
+	if got := Amount(1999).MulDiv(5, 100); got != 99 {
+		t.Errorf("5%% of 19.99 = %d, want 99 rounded down", got)
+	}
+
+	if got := Amount(100).Split(3); !reflect.DeepEqual(got, []Amount{34, 33, 33}) {
+		t.Errorf("1.00 split in 3 = %v", got)
+	}
+
+	if got := Amount(-5).String(); got != "-0.05" {
+		t.Errorf("String() = %q, want -0.05", got)
+	}
+}
+
+func TestForCountry(t *testing.T) {
+	tests := []struct {
+		code string
+		id   ID
+		ok   bool
+	}{
+		{"US", USD, true},
+		{"de", EUR, true},
+		{"XX", 0, false},
+	}
+
+	for _, tt := range tests {
+		if id, ok := ForCountry(tt.code); id != tt.id || ok != tt.ok {
+			t.Errorf("ForCountry(%q) = %v, %v, want %v, %v", tt.code, id, ok, tt.id, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package steam
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrDuplicateOffer is returned by SendTradeOffer, with deduplication on,
+// for offers an active sent offer already covers, see
+// SetOfferDeduplication.
+var ErrDuplicateOffer = errors.New("an identical offer is already active")
+
+// assetSetKey identifies a set of assets regardless of order.
+func assetSetKey(items []*EconItem) string {
+	keys := make([]string, 0, len(items))
+	for _, item := range items {
+		amount := item.Amount
+		if len(amount) == 0 {
+			amount = "1"
+		}
+
+		keys = append(keys, strconv.FormatUint(uint64(item.AppID), 10)+"_"+item.ContextID+"_"+item.AssetID+"_"+amount)
+	}
+	sort.Strings(keys)
+
+	return strings.Join(keys, ";")
+}
+
+// FindExistingOffer returns the active sent offer to partner that gives
+// and asks for exactly the given assets, nil when there is none.
+func (session *Session) FindExistingOffer(partner SteamID, sendAssets, recvAssets []*EconItem) (*TradeOffer, error) {
+	response, err := session.GetTradeOffers(TradeFilterSentOffers|TradeFilterActiveOnly, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	send, recv := assetSetKey(sendAssets), assetSetKey(recvAssets)
+	for _, offer := range response.SentOffers {
+		if offer.Partner != partner.GetAccountID() {
+			continue
+		}
+
+		if offer.State != TradeStateActive && offer.State != TradeStateCreatedNeedsConfirmation {
+			continue
+		}
+
+		if assetSetKey(offer.SendItems) == send && assetSetKey(offer.RecvItems) == recv {
+			return offer, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// SetOfferDeduplication makes SendTradeOffer look for an identical active
+// offer first, in which case it fails with ErrDuplicateOffer and sets the
+// offer ID to the one of the existing offer.  Resending after a timeout
+// or a crash is then safe.
+func (session *Session) SetOfferDeduplication(enabled bool) {
+	session.offerDedup = enabled
+}
@@ -0,0 +1,124 @@
+package steam
+
+import (
+	"container/list"
+	"strconv"
+	"sync"
+)
+
+// DefaultDescriptionCacheSize is how many descriptions NewDescriptionCache
+// keeps, a few megabytes.
+const DefaultDescriptionCacheSize = 10000
+
+// DescriptionCache keeps item descriptions by classid/instanceid so items
+// returned without descriptions can still be resolved.  Once full the
+// least recently used description is evicted.
+type DescriptionCache struct {
+	mu    sync.Mutex
+	size  int
+	descs map[string]*list.Element // of *descriptionEntry
+	lru   list.List                // most recently used first
+}
+
+type descriptionEntry struct {
+	key  string
+	desc *EconItemDesc
+}
+
+func NewDescriptionCache() *DescriptionCache {
+	return NewDescriptionCacheSize(DefaultDescriptionCacheSize)
+}
+
+// NewDescriptionCacheSize returns a cache keeping up to size descriptions,
+// DefaultDescriptionCacheSize when size is not positive.
+func NewDescriptionCacheSize(size int) *DescriptionCache {
+	if size <= 0 {
+		size = DefaultDescriptionCacheSize
+	}
+
+	return &DescriptionCache{size: size, descs: make(map[string]*list.Element)}
+}
+
+func (c *DescriptionCache) Add(descs []*EconItemDesc) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, desc := range descs {
+		key := descriptionKey(desc.ClassID, desc.InstanceID)
+		if e, ok := c.descs[key]; ok {
+			e.Value.(*descriptionEntry).desc = desc
+			c.lru.MoveToFront(e)
+			continue
+		}
+
+		c.descs[key] = c.lru.PushFront(&descriptionEntry{key: key, desc: desc})
+		if c.lru.Len() > c.size {
+			oldest := c.lru.Back()
+			c.lru.Remove(oldest)
+			delete(c.descs, oldest.Value.(*descriptionEntry).key)
+		}
+	}
+}
+
+func (c *DescriptionCache) Get(classID, instanceID uint64) (*EconItemDesc, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.descs[descriptionKey(classID, instanceID)]
+	if !ok {
+		return nil, false
+	}
+
+	c.lru.MoveToFront(e)
+	return e.Value.(*descriptionEntry).desc, true
+}
+
+// Lookup resolves an item as found in a trade offer.
+func (c *DescriptionCache) Lookup(item *EconItem) (*EconItemDesc, bool) {
+	classID, err := strconv.ParseUint(item.ClassID, 10, 64)
+	if err != nil {
+		return nil, false
+	}
+
+	instanceID, _ := strconv.ParseUint(item.InstanceID, 10, 64)
+	return c.Get(classID, instanceID)
+}
+
+func (c *DescriptionCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.descs)
+}
+
+func (c *DescriptionCache) Clear() {
+	c.mu.Lock()
+	c.descs = make(map[string]*list.Element)
+	c.lru.Init()
+	c.mu.Unlock()
+}
+
+// Descriptions returns the cache shared by every GetTradeOffers call of the
+// session.
+func (session *Session) Descriptions() *DescriptionCache {
+	return session.descriptions
+}
+
+// DescriptionFor returns the description of item, looking at the session
+// cache when the response did not carry it, nil when it is unknown.
+func (response *TradeOfferResponse) DescriptionFor(item *EconItem) *EconItemDesc {
+	if response.cache != nil {
+		if desc, ok := response.cache.Lookup(item); ok {
+			return desc
+		}
+	}
+
+	classID, _ := strconv.ParseUint(item.ClassID, 10, 64)
+	instanceID, _ := strconv.ParseUint(item.InstanceID, 10, 64)
+	for _, desc := range response.Descriptions {
+		if desc.ClassID == classID && desc.InstanceID == instanceID {
+			return desc
+		}
+	}
+
+	return nil
+}
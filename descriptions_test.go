@@ -0,0 +1,39 @@
+package steam
+
+import "testing"
+
+func TestDescriptionCacheEvicts(t *testing.T) {
+	cache := NewDescriptionCacheSize(2)
+	cache.Add([]*EconItemDesc{{ClassID: 1}, {ClassID: 2}})
+
+	// Using 1 makes 2 the least recently used.
+	if _, ok := cache.Get(1, 0); !ok {
+		t.Fatal("description 1 missing")
+	}
+
+	cache.Add([]*EconItemDesc{{ClassID: 3}})
+
+	if cache.Len() != 2 {
+		t.Errorf("got %d descriptions, want 2", cache.Len())
+	}
+
+	for _, tt := range []struct {
+		classID uint64
+		kept    bool
+	}{{1, true}, {2, false}, {3, true}} {
+		if _, ok := cache.Get(tt.classID, 0); ok != tt.kept {
+			t.Errorf("description %d kept %v, want %v", tt.classID, ok, tt.kept)
+		}
+	}
+
+	// Adding a known description again refreshes it rather than growing.
+	cache.Add([]*EconItemDesc{{ClassID: 1, Name: "new"}})
+	if desc, _ := cache.Get(1, 0); cache.Len() != 2 || desc.Name != "new" {
+		t.Errorf("got %d descriptions and %+v, want 2 and the new one", cache.Len(), desc)
+	}
+
+	cache.Clear()
+	if _, ok := cache.Get(1, 0); ok || cache.Len() != 0 {
+		t.Error("Clear kept descriptions")
+	}
+}
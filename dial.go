@@ -0,0 +1,81 @@
+package steam
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"time"
+)
+
+type dialConfig struct {
+	resolver      *net.Resolver
+	hosts         map[string]string
+	fallbackDelay time.Duration
+	timeout       time.Duration
+}
+
+// DialOption configures the transport built by NewTransport.
+type DialOption func(*dialConfig)
+
+// WithResolver resolves hosts with r instead of the system resolver.
+func WithResolver(r *net.Resolver) DialOption {
+	return func(c *dialConfig) {
+		c.resolver = r
+	}
+}
+
+// WithHostOverrides pins hosts to fixed addresses, much like /etc/hosts,
+// e.g. {"steamcommunity.com": "23.50.0.1"}.  Ports are kept as requested.
+func WithHostOverrides(hosts map[string]string) DialOption {
+	return func(c *dialConfig) {
+		c.hosts = hosts
+	}
+}
+
+// WithFallbackDelay tunes the happy-eyeballs delay before falling back from
+// IPv6 to IPv4, a negative value disables the fallback.
+func WithFallbackDelay(d time.Duration) DialOption {
+	return func(c *dialConfig) {
+		c.fallbackDelay = d
+	}
+}
+
+// WithDialTimeout bounds how long a single connection attempt may take.
+func WithDialTimeout(d time.Duration) DialOption {
+	return func(c *dialConfig) {
+		c.timeout = d
+	}
+}
+
+// NewTransport returns a copy of http.DefaultTransport dialing according to
+// opts, to be used as:
+//
+//	session := steam.NewSession(&http.Client{Transport: steam.NewTransport(...)}, "")
+func NewTransport(opts ...DialOption) *http.Transport {
+	config := &dialConfig{timeout: 30 * time.Second}
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	dialer := &net.Dialer{
+		Timeout:       config.timeout,
+		KeepAlive:     30 * time.Second,
+		Resolver:      config.resolver,
+		FallbackDelay: config.fallbackDelay,
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		if len(config.hosts) != 0 {
+			if host, port, err := net.SplitHostPort(addr); err == nil {
+				if ip, ok := config.hosts[host]; ok {
+					addr = net.JoinHostPort(ip, port)
+				}
+			}
+		}
+
+		return dialer.DialContext(ctx, network, addr)
+	}
+
+	return transport
+}
@@ -0,0 +1,187 @@
+package steam
+
+import (
+	"encoding/json"
+	"net/url"
+	"sort"
+	"strconv"
+)
+
+const (
+	apiGetAssetPrices    = "/ISteamEconomy/GetAssetPrices/v1/?"
+	apiGetAssetClassInfo = "/ISteamEconomy/GetAssetClassInfo/v1/?"
+)
+
+// AssetPrice is a store item of an app with in-game store, prices are in
+// cents keyed by currency code ("USD", "EUR"...).
+type AssetPrice struct {
+	Name           string            `json:"name"`
+	ClassID        uint64            `json:"classid,string"`
+	Date           string            `json:"date"`
+	Prices         map[string]uint64 `json:"prices"`
+	OriginalPrices map[string]uint64 `json:"original_prices"`
+	Class          []*EconDesc       `json:"class"`
+}
+
+// AssetClass identifies the description to look up with
+// GetAssetClassInfo, InstanceID may be 0.
+type AssetClass struct {
+	ClassID    uint64
+	InstanceID uint64
+}
+
+/* GetAssetClassInfo returns numbers as strings and lists as objects keyed
+ * by index, unlike the inventory and trade offer endpoints.  */
+type assetClassInfo struct {
+	ClassID                     string                 `json:"classid"`
+	InstanceID                  string                 `json:"instanceid"`
+	BackgroundColor             string                 `json:"background_color"`
+	IconURL                     string                 `json:"icon_url"`
+	IconLargeURL                string                 `json:"icon_url_large"`
+	Tradable                    string                 `json:"tradable"`
+	Name                        string                 `json:"name"`
+	NameColor                   string                 `json:"name_color"`
+	Type                        string                 `json:"type"`
+	MarketName                  string                 `json:"market_name"`
+	MarketHashName              string                 `json:"market_hash_name"`
+	Commodity                   string                 `json:"commodity"`
+	MarketTradableRestriction   string                 `json:"market_tradable_restriction"`
+	MarketMarketableRestriction string                 `json:"market_marketable_restriction"`
+	Marketable                  string                 `json:"marketable"`
+	Actions                     map[string]*EconAction `json:"actions"`
+	Tags                        map[string]*EconTag    `json:"tags"`
+	Descriptions                map[string]*EconDesc   `json:"descriptions"`
+}
+
+func atoi(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
+
+// indexed returns the values of an index keyed object in index order.
+func indexed[T any](m map[string]T) []T {
+	keys := make([]int, 0, len(m))
+	for k := range m {
+		if i, err := strconv.Atoi(k); err == nil {
+			keys = append(keys, i)
+		}
+	}
+	sort.Ints(keys)
+
+	values := make([]T, 0, len(keys))
+	for _, k := range keys {
+		values = append(values, m[strconv.Itoa(k)])
+	}
+
+	return values
+}
+
+func (info *assetClassInfo) desc() *EconItemDesc {
+	classID, _ := strconv.ParseUint(info.ClassID, 10, 64)
+	instanceID, _ := strconv.ParseUint(info.InstanceID, 10, 64)
+
+	return &EconItemDesc{
+		ClassID:                     classID,
+		InstanceID:                  instanceID,
+		BackgroundColor:             info.BackgroundColor,
+		IconURL:                     info.IconURL,
+		IconLargeURL:                info.IconLargeURL,
+		Tradable:                    atoi(info.Tradable),
+		Name:                        info.Name,
+		NameColor:                   info.NameColor,
+		Type:                        info.Type,
+		MarketName:                  info.MarketName,
+		MarketHashName:              info.MarketHashName,
+		Commodity:                   atoi(info.Commodity),
+		MarketTradableRestriction:   atoi(info.MarketTradableRestriction),
+		MarketMarketableRestriction: atoi(info.MarketMarketableRestriction),
+		Marketable:                  atoi(info.Marketable),
+		Actions:                     indexed(info.Actions),
+		Tags:                        indexed(info.Tags),
+		Descriptions:                indexed(info.Descriptions),
+	}
+}
+
+// GetAssetPrices returns the in-game store prices of appID, currency is an
+// ISO code ("USD") or empty for every currency.
+func (session *Session) GetAssetPrices(appID uint32, currency string) ([]*AssetPrice, error) {
+	params := session.authorize(url.Values{
+		"appid": {strconv.FormatUint(uint64(appID), 10)},
+	})
+	if len(currency) != 0 {
+		params.Set("currency", currency)
+	}
+
+	var response struct {
+		Result *struct {
+			Success bool          `json:"success"`
+			Assets  []*AssetPrice `json:"assets"`
+		} `json:"result"`
+	}
+	if err := session.getAPI(session.APIBaseURL()+apiGetAssetPrices, params, &response); err != nil {
+		return nil, err
+	}
+
+	if response.Result == nil || !response.Result.Success {
+		return nil, ErrInvalidResponse
+	}
+
+	return response.Result.Assets, nil
+}
+
+// GetAssetClassInfo resolves descriptions of arbitrary classes, keyed like
+// the description cache (see DescriptionCache).  The descriptions are also
+// added to the session description cache.
+func (session *Session) GetAssetClassInfo(appID uint32, classes []AssetClass) (map[string]*EconItemDesc, error) {
+	params := session.authorize(url.Values{
+		"appid":       {strconv.FormatUint(uint64(appID), 10)},
+		"class_count": {strconv.Itoa(len(classes))},
+	})
+	if len(session.language) != 0 {
+		params.Set("language", session.language)
+	}
+
+	for i, class := range classes {
+		params.Set("classid"+strconv.Itoa(i), strconv.FormatUint(class.ClassID, 10))
+		if class.InstanceID != 0 {
+			params.Set("instanceid"+strconv.Itoa(i), strconv.FormatUint(class.InstanceID, 10))
+		}
+	}
+
+	var response struct {
+		Result map[string]json.RawMessage `json:"result"`
+	}
+	if err := session.getAPI(session.APIBaseURL()+apiGetAssetClassInfo, params, &response); err != nil {
+		return nil, err
+	}
+
+	var success bool
+	if raw, ok := response.Result["success"]; !ok || json.Unmarshal(raw, &success) != nil || !success {
+		return nil, ErrInvalidResponse
+	}
+
+	descs := make(map[string]*EconItemDesc, len(classes))
+	for key, raw := range response.Result {
+		if key == "success" {
+			continue
+		}
+
+		var info assetClassInfo
+		if err := json.Unmarshal(raw, &info); err != nil {
+			return nil, err
+		}
+
+		desc := info.desc()
+		descs[descriptionKey(desc.ClassID, desc.InstanceID)] = desc
+	}
+
+	if session.descriptions != nil {
+		list := make([]*EconItemDesc, 0, len(descs))
+		for _, desc := range descs {
+			list = append(list, desc)
+		}
+		session.descriptions.Add(list)
+	}
+
+	return descs, nil
+}
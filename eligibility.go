@@ -0,0 +1,95 @@
+package steam
+
+import (
+	"net/url"
+	"strconv"
+)
+
+// TradeEligibility is what CheckTradeEligibility found out about a
+// partner, see Reasons for why an offer would fail.
+type TradeEligibility struct {
+	Partner *PartnerSnapshot
+	Escrow  *EscrowSteamGuardInfo // ErrorMsg is the error_msg of tradeoffer/new
+
+	TradeBanned    bool // economy ban or probation
+	ProfilePrivate bool
+
+	// InventoryPrivate is set when the offer page lists no inventory of
+	// the partner, which an inventory without any tradable app looks
+	// like too.
+	InventoryPrivate bool
+}
+
+// Reasons lists why an offer to the partner would fail, empty when none
+// was found.
+func (e *TradeEligibility) Reasons() []string {
+	var reasons []string
+	if len(e.Escrow.ErrorMsg) != 0 {
+		reasons = append(reasons, e.Escrow.ErrorMsg)
+	}
+	if e.TradeBanned {
+		reasons = append(reasons, "partner is trade banned: "+e.Partner.Bans.EconomyBan)
+	}
+	if e.InventoryPrivate {
+		reasons = append(reasons, "partner inventory is private")
+	}
+
+	return reasons
+}
+
+// Eligible tells whether nothing was found that prevents the offer,
+// escrow holds do not.
+func (e *TradeEligibility) Eligible() bool {
+	return len(e.Reasons()) == 0
+}
+
+// CheckTradeEligibility gathers escrow days, bans, profile and inventory
+// privacy of partner before building an offer.  token is the partner trade
+// token, empty for friends.
+func (session *Session) CheckTradeEligibility(partner SteamID, token string) (*TradeEligibility, error) {
+	params := url.Values{
+		"partner": {strconv.FormatUint(uint64(partner.GetAccountID()), 10)},
+	}
+	if len(token) != 0 {
+		params.Set("token", token)
+	}
+
+	body, err := session.getPage(session.CommunityBaseURL() + "/tradeoffer/new/?" + params.Encode())
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := parsePage("tradeoffer/new", body)
+	if err != nil {
+		return nil, err
+	}
+
+	escrow, err := p.escrow()
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot, err := session.GetPartnerSnapshot(partner)
+	if err != nil {
+		return nil, err
+	}
+
+	eligibility := &TradeEligibility{
+		Partner:        snapshot,
+		Escrow:         escrow,
+		ProfilePrivate: snapshot.Summary.VisibilityState != PrivacyStatePublic,
+	}
+
+	if bans := snapshot.Bans; bans != nil && len(bans.EconomyBan) != 0 && bans.EconomyBan != "none" {
+		eligibility.TradeBanned = true
+	}
+
+	/* The error page has no inventories at all, there is nothing to say
+	 * about the inventory then.  */
+	if len(escrow.ErrorMsg) == 0 {
+		data, ok := p.scriptVar("g_rgPartnerAppContextData")
+		eligibility.InventoryPrivate = !ok || data == "[]" || data == "{}"
+	}
+
+	return eligibility, nil
+}
@@ -0,0 +1,92 @@
+package steam
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+const defaultEmailCodeTimeout = 5 * time.Minute
+
+// EmailCodeProvider reads the mails Steam sends to email guarded accounts,
+// see offermail.GuardMailbox for an IMAP implementation.  Both methods
+// wait for a mail received after since, until ctx is done.  accountName is
+// empty when the session does not know it (API key registration).
+type EmailCodeProvider interface {
+	GuardCode(ctx context.Context, accountName string, since time.Time) (string, error)
+	ConfirmationLink(ctx context.Context, accountName string, since time.Time) (string, error)
+}
+
+// SetEmailCodeProvider makes Login answer email Steam Guard challenges and
+// RegisterWebAPIKey follow the confirmation link by itself, instead of
+// returning a SteamGuardRequiredError or ErrKeyNotFound.  timeout bounds
+// the wait for a mail, 0 means 5 minutes.
+func (session *Session) SetEmailCodeProvider(provider EmailCodeProvider, timeout time.Duration) {
+	if timeout == 0 {
+		timeout = defaultEmailCodeTimeout
+	}
+
+	session.emailCodes = provider
+	session.emailTimeout = timeout
+}
+
+func (session *Session) emailGuardCode(accountName string, since time.Time) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), session.emailTimeout)
+	defer cancel()
+
+	code, err := session.emailCodes.GuardCode(ctx, accountName, since)
+	if err != nil {
+		return "", fmt.Errorf("email guard code: %w", err)
+	}
+
+	return code, nil
+}
+
+// confirmByEmail opens the confirmation link mailed after since.
+func (session *Session) confirmByEmail(accountName string, since time.Time) error {
+	ctx, cancel := context.WithTimeout(context.Background(), session.emailTimeout)
+	defer cancel()
+
+	link, err := session.emailCodes.ConfirmationLink(ctx, accountName, since)
+	if err != nil {
+		return fmt.Errorf("email confirmation link: %w", err)
+	}
+
+	resp, err := session.client.Get(link)
+	if resp != nil {
+		defer func(Body io.ReadCloser) {
+			err := Body.Close()
+			if err != nil {
+				return
+			}
+		}(resp.Body)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("http error: %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// registerKeyByEmail finishes a key registration Steam wants confirmed by
+// mail.
+func (session *Session) registerKeyByEmail(since time.Time) (string, error) {
+	if err := session.confirmByEmail("", since); err != nil {
+		return "", err
+	}
+
+	key, err := session.GetWebAPIKey()
+	if errors.Is(err, ErrKeyNotFound) {
+		return "", ErrCannotRegisterKey
+	}
+
+	return key, err
+}
@@ -0,0 +1,109 @@
+package steam
+
+import (
+	"bufio"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// acceptEncoding is what decodingTransport can undo, brotli is not.
+const acceptEncoding = "gzip, deflate"
+
+// decodingTransport decodes compressed responses of requests that set
+// Accept-Encoding themselves, which http.Transport leaves compressed, so
+// the page parsers always read plain bytes.  It also reports the
+// maintenance and error pages of Steam, see ErrSteamMaintenance.
+type decodingTransport struct {
+	next http.RoundTripper
+}
+
+type decodedBody struct {
+	io.Reader
+	closers []io.Closer
+}
+
+func (b *decodedBody) Close() error {
+	var err error
+	for _, c := range b.closers {
+		if cerr := c.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}
+
+	return err
+}
+
+// deflateReader reads a deflate body, which servers send either zlib
+// wrapped as the RFC says or raw.
+func deflateReader(r io.Reader) (io.ReadCloser, error) {
+	br := bufio.NewReader(r)
+	header, err := br.Peek(2)
+	if err != nil {
+		return nil, err
+	}
+
+	if header[0]&0x0f == 8 && (uint16(header[0])<<8|uint16(header[1]))%31 == 0 {
+		return zlib.NewReader(br)
+	}
+
+	return flate.NewReader(br), nil
+}
+
+func (t *decodingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if len(req.Header.Get("Accept-Encoding")) != 0 {
+		req = req.Clone(req.Context())
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+
+	var r io.ReadCloser
+	switch strings.ToLower(strings.TrimSpace(resp.Header.Get("Content-Encoding"))) {
+	case "gzip", "x-gzip":
+		r, err = gzip.NewReader(resp.Body)
+	case "deflate":
+		r, err = deflateReader(resp.Body)
+	default:
+		return checkUnavailable(resp)
+	}
+
+	if err == io.EOF {
+		// Empty body, nothing to decode.
+		return checkUnavailable(resp)
+	}
+	if err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+
+	resp.Body = &decodedBody{Reader: r, closers: []io.Closer{r, resp.Body}}
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Del("Content-Length")
+	resp.ContentLength = -1
+	resp.Uncompressed = true
+	return checkUnavailable(resp)
+}
+
+// withDecoding returns a copy of client with decodingTransport put under
+// its transport, the client of the caller is left as it is.
+func withDecoding(client *http.Client) *http.Client {
+	if _, ok := client.Transport.(*decodingTransport); ok {
+		return client
+	}
+
+	next := client.Transport
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	decoding := *client
+	decoding.Transport = &decodingTransport{next: next}
+	return &decoding
+}
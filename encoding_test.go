@@ -0,0 +1,44 @@
+package steam
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"testing"
+)
+
+func TestDecodingTransport(t *testing.T) {
+	session := newTestSession(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Accept-Encoding"); got != acceptEncoding {
+			t.Errorf("Accept-Encoding %q, want %q", got, acceptEncoding)
+		}
+
+		w.Header().Set("Content-Encoding", "gzip")
+		gz := gzip.NewWriter(w)
+		gz.Write([]byte("<html>escrow</html>"))
+		gz.Close()
+	}))
+
+	req, _ := http.NewRequest(http.MethodGet, "https://steamcommunity.com/tradeoffer/new/", nil)
+	req.Header.Set("Accept-Encoding", "gzip, deflate, br")
+	resp, err := session.client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil || string(body) != "<html>escrow</html>" {
+		t.Errorf("got %q, %v, want the decoded page", body, err)
+	}
+}
+
+func TestNewSessionKeepsClient(t *testing.T) {
+	transport := &http.Transport{}
+	client := &http.Client{Transport: transport}
+	NewSession(client, "key")
+
+	if client.Transport != transport {
+		t.Errorf("NewSession replaced the transport of the caller's client")
+	}
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"time"
+
+	"github.com/hiship/go-steam"
+)
+
+func runConfirmer(args []string) error {
+	flags := flag.NewFlagSet("confirmer", flag.ExitOnError)
+	interval := flags.Duration("interval", time.Minute, "how often to check confirmations")
+	market := flags.Bool("market", false, "confirm market listings too")
+	once := flags.Bool("once", false, "check a single time and exit")
+	flags.Parse(args)
+
+	acc, err := login()
+	if err != nil {
+		return err
+	}
+
+	if err = acc.requireIdentitySecret(); err != nil {
+		return err
+	}
+
+	types := []steam.ConfirmationType{steam.ConfirmationTypeTrade}
+	if *market {
+		types = append(types, steam.ConfirmationTypeMarketListing)
+	}
+
+	for {
+		confirmations, err := acc.session.GetConfirmations(acc.identitySecret, acc.now())
+		if err != nil {
+			log.Printf("cannot get confirmations: %v", err)
+		}
+
+		for _, c := range steam.FilterConfirmations(confirmations, types...) {
+			log.Printf("Confirmation %s: %s %s (%s)", c.ID, c.Type, c.Headline, c.Description())
+
+			if err = acc.session.AnswerConfirmation(c, acc.identitySecret, "allow", acc.now()); err != nil {
+				log.Printf("Confirmation %s: %v", c.ID, err)
+				continue
+			}
+
+			log.Printf("Accepted %s", c.ID)
+		}
+
+		if *once {
+			return nil
+		}
+
+		time.Sleep(*interval)
+	}
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/hiship/go-steam"
+)
+
+// output is where the inventory is written.
+var output io.Writer = os.Stdout
+
+type exportedItem struct {
+	AssetID        uint64 `json:"assetid,string"`
+	ClassID        uint64 `json:"classid,string"`
+	InstanceID     uint64 `json:"instanceid,string"`
+	Amount         string `json:"amount"`
+	Name           string `json:"name"`
+	MarketHashName string `json:"market_hash_name"`
+	Tradable       bool   `json:"tradable"`
+	Marketable     bool   `json:"marketable"`
+	Image          string `json:"image"`
+}
+
+func runInventoryExport(args []string) error {
+	flags := flag.NewFlagSet("inventory-export", flag.ExitOnError)
+	owner := flags.Uint64("steamid", 0, "inventory owner, defaults to the logged in account")
+	appID := flags.Uint64("app", 730, "app ID of the inventory")
+	contextID := flags.Uint64("context", 2, "context ID of the inventory")
+	format := flags.String("format", "csv", "csv or json")
+	flags.Parse(args)
+
+	acc, err := login()
+	if err != nil {
+		return err
+	}
+
+	sid := steam.SteamID(*owner)
+	if sid == 0 {
+		sid = acc.session.GetSteamID()
+	}
+
+	inventory, err := acc.session.GetInventoryContents(sid, *appID, *contextID, nil)
+	if err != nil {
+		return err
+	}
+
+	items := make([]*exportedItem, 0, len(inventory.Items))
+	for _, item := range inventory.Items {
+		exported := &exportedItem{
+			AssetID:    item.AssetID,
+			ClassID:    item.ClassID,
+			InstanceID: item.InstanceID,
+			Amount:     item.Amount,
+		}
+
+		if item.Desc != nil {
+			exported.Name = item.Desc.Name
+			exported.MarketHashName = item.Desc.MarketHashName
+			exported.Tradable = item.Desc.Tradable != 0
+			exported.Marketable = item.Desc.Marketable != 0
+			exported.Image = item.Desc.ImageURL(steam.ImageSizeMedium)
+		}
+
+		items = append(items, exported)
+	}
+
+	switch *format {
+	case "json":
+		enc := json.NewEncoder(output)
+		enc.SetIndent("", "\t")
+		return enc.Encode(items)
+	case "csv":
+		w := csv.NewWriter(output)
+		w.Write([]string{"assetid", "classid", "instanceid", "amount", "name", "market_hash_name", "tradable", "marketable", "image"})
+		for _, item := range items {
+			w.Write([]string{
+				strconv.FormatUint(item.AssetID, 10),
+				strconv.FormatUint(item.ClassID, 10),
+				strconv.FormatUint(item.InstanceID, 10),
+				item.Amount,
+				item.Name,
+				item.MarketHashName,
+				strconv.FormatBool(item.Tradable),
+				strconv.FormatBool(item.Marketable),
+				item.Image,
+			})
+		}
+		w.Flush()
+		return w.Error()
+	}
+
+	return fmt.Errorf("unknown format %q", *format)
+}
@@ -0,0 +1,145 @@
+// Command steambot gathers the examples that need a logged in account as
+// subcommands:
+//
+//	steambot trade-bot        accept incoming offers that only give us items
+//	steambot market-seller    list marketable items at the lowest sell order
+//	steambot confirmer        confirm pending trades and market listings
+//	steambot inventory-export dump an inventory as CSV or JSON
+//
+// Credentials come from an encrypted bundle (steamBundle and
+// steamBundlePassphrase) when set, otherwise from the steamAccount,
+// steamPassword, steamSharedSecret and steamIdentitySecret environment
+// variables.
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"log/slog"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/hiship/go-steam"
+	"github.com/hiship/go-steam/bundle"
+)
+
+type command struct {
+	name  string
+	usage string
+	run   func(args []string) error
+}
+
+var commands = []*command{
+	{"trade-bot", "accept incoming offers that only give us items", runTradeBot},
+	{"market-seller", "list marketable items at the lowest sell order", runMarketSeller},
+	{"confirmer", "confirm pending trades and market listings", runConfirmer},
+	{"inventory-export", "dump an inventory as CSV or JSON", runInventoryExport},
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "usage: %s <command> [flags]\n\ncommands:\n", os.Args[0])
+	for _, cmd := range commands {
+		fmt.Fprintf(os.Stderr, "  %-18s %s\n", cmd.name, cmd.usage)
+	}
+	os.Exit(2)
+}
+
+func main() {
+	log.SetFlags(log.LstdFlags | log.Lshortfile)
+
+	if len(os.Args) < 2 {
+		usage()
+	}
+
+	for _, cmd := range commands {
+		if cmd.name == os.Args[1] {
+			if err := cmd.run(os.Args[2:]); err != nil {
+				log.Fatal(err)
+			}
+			return
+		}
+	}
+
+	usage()
+}
+
+// transport is what the sessions talk through, nil for the default one.
+// The dry run test points it at a fake Steam.
+var transport http.RoundTripper
+
+// account is a logged in session together with what the subcommands need
+// to generate codes.
+type account struct {
+	session        *steam.Session
+	identitySecret string
+	timeDiff       time.Duration
+}
+
+func (acc *account) now() int64 {
+	return time.Now().Add(acc.timeDiff).Unix()
+}
+
+func login() (*account, error) {
+	timeTip, err := steam.GetTimeTip()
+	if err != nil {
+		return nil, err
+	}
+
+	acc := &account{
+		session:  steam.NewSession(&http.Client{Transport: transport}, ""),
+		timeDiff: time.Duration(timeTip.Time-time.Now().Unix()) * time.Second,
+	}
+	acc.session.SetLogger(steam.NewSlogLogger(slog.Default()))
+
+	if path := os.Getenv("steamBundle"); len(path) != 0 {
+		return acc, loginBundle(acc, path, os.Getenv("steamBundlePassphrase"))
+	}
+
+	acc.identitySecret = os.Getenv("steamIdentitySecret")
+	err = acc.session.Login(os.Getenv("steamAccount"), os.Getenv("steamPassword"), os.Getenv("steamSharedSecret"), acc.timeDiff)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Print("Login successful")
+	return acc, nil
+}
+
+// loginBundle resumes the session stored in the bundle and only logs in
+// again when it expired, saving the new session back.
+func loginBundle(acc *account, path, passphrase string) error {
+	b, err := bundle.Open(path, passphrase)
+	if err != nil {
+		return err
+	}
+
+	acc.identitySecret = b.IdentitySecret
+	if b.Session != nil {
+		if err = acc.session.Restore(b.Session); err != nil {
+			return err
+		}
+
+		if acc.session.IsLogged() {
+			log.Print("Resumed session from bundle")
+			return nil
+		}
+	}
+
+	if err = acc.session.Login(b.AccountName, b.Password, b.SharedSecret, acc.timeDiff); err != nil {
+		return err
+	}
+
+	log.Print("Login successful")
+	b.Session = acc.session.State()
+	return b.Save(path, passphrase)
+}
+
+func (acc *account) requireIdentitySecret() error {
+	if len(acc.identitySecret) == 0 {
+		return errors.New("an identity secret is needed to answer confirmations")
+	}
+
+	return nil
+}
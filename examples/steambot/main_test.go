@@ -0,0 +1,89 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hiship/go-steam"
+	"github.com/hiship/go-steam/steamtest"
+)
+
+const sid steam.SteamID = 76561197960287930
+
+// secret is a made-up shared and identity secret.
+var secret = base64.StdEncoding.EncodeToString([]byte("steamtest secret 20b"))
+
+// newFake runs the subcommands against a fake Steam the account logs into.
+func newFake(t *testing.T) *steamtest.Server {
+	t.Helper()
+
+	fake := steamtest.NewServer()
+	t.Cleanup(fake.Close)
+
+	if err := fake.SetLogin(sid); err != nil {
+		t.Fatal(err)
+	}
+	fake.HandleJSON(steamtest.APIHost, "/ITwoFactorService/QueryTime/v1/", map[string]interface{}{
+		"response": map[string]string{"server_time": strconv.FormatInt(time.Now().Unix(), 10)},
+	})
+	t.Cleanup(fake.InstallDefault())
+
+	transport = fake.Transport()
+	t.Cleanup(func() { transport = nil })
+
+	t.Setenv("steamBundle", "")
+	t.Setenv("steamAccount", "user")
+	t.Setenv("steamPassword", "password")
+	t.Setenv("steamSharedSecret", secret)
+	t.Setenv("steamIdentitySecret", secret)
+	return fake
+}
+
+func TestConfirmer(t *testing.T) {
+	fake := newFake(t)
+	fake.SetConfirmations([]*steam.Confirmation{
+		{ID: "100", Type: steam.ConfirmationTypeTrade, Creator: "1", Nonce: "n1", Headline: "partner"},
+		{ID: "101", Type: steam.ConfirmationTypeMarketListing, Creator: "2", Nonce: "n2", Headline: "AK-47 | Redline"},
+	})
+
+	if err := runConfirmer([]string{"-once"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Market listings are left alone without -market.
+	var allowed []string
+	for _, r := range fake.Requests() {
+		if r.URL.Path == "/mobileconf/ajaxop" && r.URL.Query().Get("op") == "allow" {
+			allowed = append(allowed, r.URL.Query().Get("cid"))
+		}
+	}
+
+	if strings.Join(allowed, ",") != "100" {
+		t.Errorf("allowed %q, want the trade only", allowed)
+	}
+}
+
+func TestInventoryExport(t *testing.T) {
+	fake := newFake(t)
+	fake.SetInventory(sid, 730, 2, []steam.InventoryItem{
+		{AppID: 730, ContextID: 2, AssetID: 10, ClassID: 20, Amount: "1", Desc: &steam.EconItemDesc{ClassID: 20, Name: "AK-47 | Redline", Tradable: 1}},
+	})
+
+	var b bytes.Buffer
+	output = &b
+	t.Cleanup(func() { output = os.Stdout })
+
+	if err := runInventoryExport([]string{"-format", "csv"}); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(b.String()), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[1], "10,20,0,1,AK-47 | Redline,,true,false,") {
+		t.Errorf("got %q, want the header and the fixture item", lines)
+	}
+}
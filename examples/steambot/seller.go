@@ -0,0 +1,92 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"time"
+
+	"github.com/hiship/go-steam"
+)
+
+func runMarketSeller(args []string) error {
+	flags := flag.NewFlagSet("market-seller", flag.ExitOnError)
+	appID := flags.Uint64("app", 730, "app ID of the inventory")
+	contextID := flags.Uint64("context", 2, "context ID of the inventory")
+	currency := flags.String("currency", steam.CurrencyUSD, "wallet currency ID")
+	undercut := flags.Uint64("undercut", 1, "cents below the lowest sell order")
+	minPrice := flags.Uint64("min-price", 3, "never list below this many cents")
+	dryRun := flags.Bool("dry-run", true, "only print what would be listed")
+	flags.Parse(args)
+
+	acc, err := login()
+	if err != nil {
+		return err
+	}
+
+	/* Stay away from the market for the first day of a major sale.  */
+	acc.session.SetMarketGuard(&steam.MarketGuard{PauseFor: 24 * time.Hour})
+
+	wallet, err := acc.session.GetWalletBalance()
+	if err != nil {
+		return err
+	}
+	log.Printf("Wallet: %d cents (currency %s)", wallet.Balance, wallet.Currency)
+
+	inventory, err := acc.session.GetInventoryContents(acc.session.GetSteamID(), *appID, *contextID, []steam.Filter{
+		steam.IsTradable(1),
+		steam.IsMarketable(1),
+	})
+	if err != nil {
+		return err
+	}
+
+	nameIDs := make(map[string]uint64)
+	for i := range inventory.Items {
+		item := &inventory.Items[i]
+		if item.Desc == nil {
+			continue
+		}
+
+		name := item.Desc.MarketHashName
+		nameID, ok := nameIDs[name]
+		if !ok {
+			if nameID, err = acc.session.GetItemNameID(*appID, name); err != nil {
+				log.Printf("%s: %v", name, err)
+				continue
+			}
+			nameIDs[name] = nameID
+		}
+
+		histogram, err := acc.session.GetItemOrdersHistogram(nameID, *currency)
+		if err != nil {
+			log.Printf("%s: %v", name, err)
+			continue
+		}
+
+		/* The lowest sell order is what the buyer pays, SellItem wants what
+		 * we receive, roughly 15% less after fees.  */
+		price := histogram.LowestSellOrder
+		if price > *undercut {
+			price -= *undercut
+		}
+		price = price * 100 / 115
+		if price < *minPrice {
+			log.Printf("%s: %d cents is below the minimum, skipping", name, price)
+			continue
+		}
+
+		if *dryRun {
+			log.Printf("%s (%d): would list for %d cents", name, item.AssetID, price)
+			continue
+		}
+
+		resp, err := acc.session.SellItem(item, 1, price)
+		if err != nil {
+			return err
+		}
+
+		log.Printf("%s (%d): listed for %d cents, confirmation needed: %v", name, item.AssetID, price, resp.RequiresConfirmation != 0)
+	}
+
+	return nil
+}
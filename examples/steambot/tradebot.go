@@ -0,0 +1,83 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"time"
+
+	"github.com/hiship/go-steam"
+)
+
+func runTradeBot(args []string) error {
+	flags := flag.NewFlagSet("trade-bot", flag.ExitOnError)
+	interval := flags.Duration("interval", 30*time.Second, "how often to poll offers")
+	minLevel := flags.Uint("min-level", 0, "ignore partners below this Steam level")
+	once := flags.Bool("once", false, "poll a single time and exit")
+	flags.Parse(args)
+
+	acc, err := login()
+	if err != nil {
+		return err
+	}
+
+	if _, err = acc.session.GetWebAPIKey(); err != nil {
+		return err
+	}
+
+	/* Never give anything away, whatever the offer looks like.  */
+	acc.session.SetGivePolicy(&steam.GivePolicy{})
+	acc.session.SetPartnerCacheTTL(time.Hour)
+
+	for {
+		resp, err := acc.session.GetTradeOffers(steam.TradeFilterRecvOffers|steam.TradeFilterActiveOnly, time.Now())
+		if err != nil {
+			log.Printf("cannot get offers: %v", err)
+		} else {
+			for _, offer := range resp.ReceivedOffers {
+				handleOffer(acc, offer, uint32(*minLevel))
+			}
+		}
+
+		if *once {
+			return nil
+		}
+
+		time.Sleep(*interval)
+	}
+}
+
+func handleOffer(acc *account, offer *steam.TradeOffer, minLevel uint32) {
+	if offer.State != steam.TradeStateActive {
+		return
+	}
+
+	event, err := acc.session.NewOfferEvent(offer, minLevel != 0)
+	if err != nil {
+		log.Printf("offer %d: cannot look up partner: %v", offer.ID, err)
+		return
+	}
+
+	if event.Partner != nil && event.Partner.Level < minLevel {
+		log.Printf("offer %d: partner level %d too low, declining", offer.ID, event.Partner.Level)
+		if err = acc.session.DeclineTradeOffer(offer.ID); err != nil {
+			log.Printf("offer %d: %v", offer.ID, err)
+		}
+		return
+	}
+
+	if len(offer.SendItems) != 0 {
+		log.Printf("offer %d: asks for %d of our items, ignoring", offer.ID, len(offer.SendItems))
+		return
+	}
+
+	accepted, err := acc.session.AcceptTradeOfferWithRetry(offer.ID, 3, 5*time.Second)
+	if err != nil {
+		log.Printf("offer %d: cannot accept: %v", offer.ID, err)
+		return
+	}
+
+	log.Printf("offer %d: accepted %d items, trade %d", offer.ID, len(offer.RecvItems), accepted.TradeID)
+	if accepted.MobileConfirmationRequired {
+		log.Printf("offer %d: needs a mobile confirmation, run the confirmer", offer.ID)
+	}
+}
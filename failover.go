@@ -0,0 +1,214 @@
+package steam
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultAPIHost is the public WebAPI host, APIBaseUrl points to it.
+	DefaultAPIHost = "api.steampowered.com"
+
+	// PartnerAPIHost serves the same WebAPI to publisher keys, it is a
+	// useful fallback for sessions using one.
+	PartnerAPIHost = "partner.steam-api.com"
+)
+
+// APIFailoverConfig configures the WebAPI host failover.  Requests to the
+// WebAPI host of the session, DefaultAPIHost unless SetAPIBaseURL points
+// elsewhere, are sent to the first host of Hosts that answers, a host
+// failing with a transport error or timeout is skipped until RetryPrimary
+// has passed.
+//
+// Resolve, when set, looks the hosts up instead, e.g. in a service
+// directory.  It is called on the first request and again whenever every
+// host failed or RetryPrimary passed, a failing lookup keeps the hosts
+// known so far.  The CM servers of the cm package are resolved through
+// ISteamDirectory the same way by cm.Connect.
+type APIFailoverConfig struct {
+	Hosts        []string      // in order of preference, the session WebAPI host when empty
+	Timeout      time.Duration // per attempt, none when 0
+	RetryPrimary time.Duration // how long to stay on an alternate, 5 minutes when 0
+	Resolve      func(ctx context.Context) ([]string, error)
+}
+
+type failoverTransport struct {
+	session *Session
+	config  APIFailoverConfig
+	next    http.RoundTripper
+
+	mu       sync.Mutex
+	hosts    []string
+	current  int
+	switched time.Time
+	resolved time.Time
+}
+
+// cancelBody cancels the attempt context once the response is read.
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
+// resolve refreshes the hosts with config.Resolve when they are stale.
+// The lookup runs outside of the lock, concurrent requests keep using the
+// previous hosts meanwhile.
+func (t *failoverTransport) resolve(ctx context.Context, now time.Time, force bool) {
+	t.mu.Lock()
+	lookup := t.config.Resolve
+	stale := force || t.resolved.IsZero() || now.Sub(t.resolved) > t.config.RetryPrimary
+	if stale {
+		// Claimed, so that concurrent requests do not look up too.
+		t.resolved = now
+	}
+	t.mu.Unlock()
+
+	if lookup == nil || !stale {
+		return
+	}
+
+	hosts, err := lookup(ctx)
+	if err != nil || len(hosts) == 0 {
+		return
+	}
+
+	t.mu.Lock()
+	t.hosts, t.current = hosts, 0
+	t.mu.Unlock()
+}
+
+// start returns the hosts, the index of the one to try first and the
+// timeout of an attempt.
+func (t *failoverTransport) start(now time.Time) ([]string, int, time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.current != 0 && now.Sub(t.switched) > t.config.RetryPrimary {
+		t.current = 0
+	}
+
+	return t.hosts, t.current, t.config.Timeout
+}
+
+func (t *failoverTransport) fail(hosts []string, host int, now time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	// Concurrent requests may have moved on already, or the hosts may
+	// have been resolved again.
+	if t.current == host && len(t.hosts) == len(hosts) && t.hosts[host] == hosts[host] {
+		t.current = (host + 1) % len(t.hosts)
+		t.switched = now
+	}
+}
+
+func (t *failoverTransport) attempt(req *http.Request, host string, timeout time.Duration) (*http.Response, error) {
+	attempt := req.Clone(req.Context())
+	attempt.URL.Host = host
+	attempt.Host = host
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		attempt.Body = body
+	}
+
+	if timeout == 0 {
+		return t.next.RoundTrip(attempt)
+	}
+
+	ctx, cancel := context.WithTimeout(req.Context(), timeout)
+	resp, err := t.next.RoundTrip(attempt.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	resp.Body = &cancelBody{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+func (t *failoverTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.Host != t.session.apiHost() {
+		return t.next.RoundTrip(req)
+	}
+
+	t.resolve(req.Context(), time.Now(), false)
+	hosts, first, timeout := t.start(time.Now())
+
+	// Bodies that cannot be replayed only get one attempt.
+	attempts := len(hosts)
+	if req.Body != nil && req.GetBody == nil {
+		attempts = 1
+	}
+
+	var err error
+	for i := 0; i < attempts; i++ {
+		host := (first + i) % len(hosts)
+
+		var resp *http.Response
+		resp, err = t.attempt(req, hosts[host], timeout)
+		if err == nil {
+			return resp, nil
+		}
+
+		// The caller gave up, another host would not help.
+		if req.Context().Err() != nil {
+			return nil, err
+		}
+
+		t.fail(hosts, host, time.Now())
+	}
+
+	// Every host failed, they may have moved.
+	t.resolve(req.Context(), time.Now(), true)
+	return nil, err
+}
+
+// EnableAPIFailover sends the WebAPI requests of the session to the next
+// host of config.Hosts when one times out or cannot be reached, for
+// deployments where the primary host is unreliable.  HTTP errors do not
+// trigger a failover, the host answered.  Enabling it again replaces the
+// config.
+func (session *Session) EnableAPIFailover(config APIFailoverConfig) error {
+	if len(config.Hosts) == 0 {
+		config.Hosts = []string{session.apiHost()}
+	}
+	if config.RetryPrimary == 0 {
+		config.RetryPrimary = 5 * time.Minute
+	}
+
+	for _, host := range config.Hosts {
+		if len(host) == 0 {
+			return errors.New("empty API host")
+		}
+	}
+
+	// Installed once, enabling again swaps the config in place.
+	if t := session.failover; t != nil {
+		t.mu.Lock()
+		t.config, t.hosts, t.current, t.resolved = config, config.Hosts, 0, time.Time{}
+		t.mu.Unlock()
+		return nil
+	}
+
+	next := session.client.Transport
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	session.failover = &failoverTransport{session: session, config: config, next: next, hosts: config.Hosts}
+	session.client.Transport = session.failover
+	return nil
+}
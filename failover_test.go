@@ -0,0 +1,69 @@
+package steam
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+)
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestAPIFailover(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		hosts []string
+	)
+	session := NewSession(&http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		mu.Lock()
+		hosts = append(hosts, req.URL.Host)
+		mu.Unlock()
+
+		if req.URL.Host == "down.example" {
+			return nil, errors.New("unreachable")
+		}
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader("{}")), Header: http.Header{}}, nil
+	})}, "key")
+
+	var lookups int
+	err := session.EnableAPIFailover(APIFailoverConfig{
+		Resolve: func(ctx context.Context) ([]string, error) {
+			lookups++
+			return []string{"down.example", "up.example"}, nil
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 2; i++ {
+		resp, err := session.client.Get(APIBaseUrl + "/ISteamWebAPIUtil/GetServerInfo/v1/")
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+
+	if got := strings.Join(hosts, " "); got != "down.example up.example up.example" {
+		t.Errorf("requests went to %s", got)
+	}
+
+	if lookups != 1 {
+		t.Errorf("resolved %d times, want once", lookups)
+	}
+
+	installed := session.client.Transport
+	if err = session.EnableAPIFailover(APIFailoverConfig{Hosts: []string{"up.example"}}); err != nil {
+		t.Fatal(err)
+	}
+	if session.client.Transport != installed {
+		t.Error("enabling again wrapped the transport again")
+	}
+}
@@ -7,14 +7,49 @@ type Filter func(*InventoryItem) bool
 // IsTradable return Filter for item.Tradable option
 func IsTradable(cond int) Filter {
 	return func(item *InventoryItem) bool {
-		return item.Desc.Tradable == cond
+		return item.Desc != nil && item.Desc.Tradable == cond
 
 	}
 }
 
+// IsMarketable return Filter for item.Marketable option
+func IsMarketable(cond int) Filter {
+	return func(item *InventoryItem) bool {
+		return item.Desc != nil && item.Desc.Marketable == cond
+	}
+}
+
+// HasTag filters items carrying the tag category/internalName
+func HasTag(category, internalName string) Filter {
+	return func(item *InventoryItem) bool {
+		if item.Desc == nil {
+			return false
+		}
+
+		for _, tag := range item.Desc.Tags {
+			if tag.Category == category && tag.InternalName == internalName {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+// HasMarketHashName filters items by their exact market hash name
+func HasMarketHashName(name string) Filter {
+	return func(item *InventoryItem) bool {
+		return item.Desc != nil && item.Desc.MarketHashName == name
+	}
+}
+
 // IsSouvenir filters souvenir items
 func IsSouvenir(cond bool) Filter {
 	return func(item *InventoryItem) bool {
+		if item.Desc == nil {
+			return !cond
+		}
+
 		for _, tag := range item.Desc.Tags {
 			if tag.Category == "Quality" && tag.InternalName == "tournament" {
 				return cond
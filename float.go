@@ -0,0 +1,56 @@
+package steam
+
+import (
+	"errors"
+	"strconv"
+)
+
+const inspectLinkPrefix = "steam://rungame/730/76561202255233023/+csgo_econ_action_preview%20"
+
+var (
+	ErrNoFloatChecker = errors.New("no float checker set")
+	ErrNotInspectable = errors.New("item has no inspect link")
+)
+
+// ItemFloat is what float checking backends report about a CS2 item.
+type ItemFloat struct {
+	FloatValue float64
+	PaintSeed  uint32
+	PaintIndex uint32
+}
+
+// FloatChecker looks up the float of an item from its inspect parameters,
+// usually through a game coordinator backed service.
+type FloatChecker interface {
+	ItemFloat(params *InspectParams) (*ItemFloat, error)
+}
+
+// Link rebuilds the inspect link of the parameters.
+func (params *InspectParams) Link() string {
+	if params.Market != 0 {
+		return inspectLinkPrefix + "M" + strconv.FormatUint(params.Market, 10) +
+			"A" + strconv.FormatUint(params.Asset, 10) + "D" + strconv.FormatUint(params.D, 10)
+	}
+
+	return inspectLinkPrefix + "S" + params.Owner.ToString() +
+		"A" + strconv.FormatUint(params.Asset, 10) + "D" + strconv.FormatUint(params.D, 10)
+}
+
+// SetFloatChecker installs the backend GetItemFloat asks, nil removes it.
+func (session *Session) SetFloatChecker(checker FloatChecker) {
+	session.floatChecker = checker
+}
+
+// GetItemFloat asks the float checker about an inventory item of owner.
+func (session *Session) GetItemFloat(item *InventoryItem, owner SteamID) (*ItemFloat, error) {
+	if session.floatChecker == nil {
+		return nil, ErrNoFloatChecker
+	}
+
+	params, ok := ParseInspectLink(item.InspectLink(owner))
+	if !ok {
+		return nil, ErrNotInspectable
+	}
+
+	return session.floatChecker.ItemFloat(params)
+}
@@ -0,0 +1,98 @@
+package steam
+
+import (
+	"bytes"
+	"testing"
+)
+
+// The parsers fed with raw Steam responses must never panic, whatever the
+// response.  The seeds are the fixtures of testdata plus the corpus of
+// real responses under testdata/fuzz, which go test runs every time.  Fuzz
+// a target with e.g.:
+//
+//	go test -fuzz FuzzReceipt
+
+func addFixtures(f *testing.F, names ...string) {
+	f.Helper()
+
+	for _, name := range names {
+		f.Add(readFixture(f, name))
+	}
+}
+
+func FuzzReceipt(f *testing.F) {
+	addFixtures(f, "receipt.html", "receipt_empty.html", "receipt_broken.html")
+	f.Fuzz(func(t *testing.T, data []byte) {
+		items, err := parseReceipt(data)
+		if err != nil {
+			return
+		}
+
+		for _, item := range items {
+			if item == nil {
+				t.Fatal("nil receipt item")
+			}
+		}
+	})
+}
+
+func FuzzEscrow(f *testing.F) {
+	addFixtures(f, "tradeoffer.html", "tradeoffer_error.html", "tradeoffer_noescrow.html")
+	f.Fuzz(func(t *testing.T, data []byte) {
+		info, err := parseEscrow(data)
+		if err == nil && info == nil {
+			t.Fatal("nil escrow info without an error")
+		}
+	})
+}
+
+func FuzzTradeToken(f *testing.F) {
+	addFixtures(f, "privacy.html", "privacy_mangled.html", "privacy_missing.html")
+	f.Fuzz(func(t *testing.T, data []byte) {
+		token, err := parseTradeToken(data)
+		if err == nil && len(token) == 0 {
+			t.Fatal("empty token without an error")
+		}
+	})
+}
+
+func FuzzConfirmations(f *testing.F) {
+	addFixtures(f, "confirmations.json")
+	f.Fuzz(func(t *testing.T, data []byte) {
+		confirmations, err := parseConfirmations(data)
+		if err != nil {
+			return
+		}
+
+		for _, confirmation := range confirmations {
+			if confirmation == nil {
+				continue
+			}
+
+			_ = confirmation.Description()
+			_ = confirmation.Type.String()
+			confirmation.MarketListing()
+			confirmation.IsLoginRequest()
+		}
+	})
+}
+
+func FuzzInventory(f *testing.F) {
+	addFixtures(f, "inventory.json")
+	f.Fuzz(func(t *testing.T, data []byte) {
+		inventory := &Inventory{}
+		if _, _, err := decodeInventoryPage(bytes.NewReader(data), inventory, nil); err != nil {
+			return
+		}
+
+		for i := range inventory.Items {
+			item := &inventory.Items[i]
+			IsTradable(1)(item)
+			IsMarketable(1)(item)
+			HasTag("Type", "")(item)
+			if item.Desc != nil {
+				item.Desc.ImageURL("")
+			}
+		}
+	})
+}
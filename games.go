@@ -0,0 +1,153 @@
+package steam
+
+import (
+	"html"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// GameContext is the app and context the tradable items of a game live in.
+type GameContext struct {
+	AppID     uint64
+	ContextID uint64
+}
+
+var (
+	GameCS2            = GameContext{730, 2}
+	GameTF2            = GameContext{440, 2}
+	GameDota2          = GameContext{570, 2}
+	GameRust           = GameContext{252490, 2}
+	GameSteamCommunity = GameContext{AppIDSteam, ContextIDCommunity}
+)
+
+// Tag categories the games above share.
+const (
+	TagCategoryType     = "Type"
+	TagCategoryQuality  = "Quality"
+	TagCategoryRarity   = "Rarity"
+	TagCategoryExterior = "Exterior" // CS2 wear
+)
+
+const unusualEffectPrefix = "★ Unusual Effect: "
+
+var (
+	stickerTitleExp = regexp.MustCompile(`title="(Sticker|Patch|Charm): ([^"]+)"`)
+	inspectExp      = regexp.MustCompile(`\+csgo_econ_action_preview(?:%20|\s)+([SM])(\d+)A(\d+)D(\d+)`)
+)
+
+func (session *Session) GetGameInventory(sid SteamID, game GameContext, filters []Filter) (*Inventory, error) {
+	return session.GetInventoryContents(sid, game.AppID, game.ContextID, filters)
+}
+
+// Tag returns the tag of the given category, nil when the item has none.
+func (desc *EconItemDesc) Tag(category string) *EconTag {
+	for _, tag := range desc.Tags {
+		if tag != nil && tag.Category == category {
+			return tag
+		}
+	}
+
+	return nil
+}
+
+// TagName is the localized name of the tag of the given category, e.g.
+// "Factory New" for TagCategoryExterior.
+func (desc *EconItemDesc) TagName(category string) string {
+	if tag := desc.Tag(category); tag != nil {
+		return tag.LocalizedTagName
+	}
+
+	return ""
+}
+
+// Stickers lists the stickers, patches and charms applied to a CS2 item,
+// prefixed with their kind ("Sticker: Natus Vincere | Katowice 2014").
+func (desc *EconItemDesc) Stickers() []string {
+	var stickers []string
+	for _, d := range desc.Descriptions {
+		if d == nil || !strings.Contains(d.Value, "sticker_info") && !strings.Contains(d.Value, "keychain_info") {
+			continue
+		}
+
+		for _, m := range stickerTitleExp.FindAllStringSubmatch(d.Value, -1) {
+			stickers = append(stickers, m[1]+": "+html.UnescapeString(m[2]))
+		}
+	}
+
+	return stickers
+}
+
+// UnusualEffect is the particle effect of a TF2 unusual, empty for other
+// items.
+func (desc *EconItemDesc) UnusualEffect() string {
+	for _, d := range desc.Descriptions {
+		if d != nil && strings.HasPrefix(d.Value, unusualEffectPrefix) {
+			return strings.TrimPrefix(d.Value, unusualEffectPrefix)
+		}
+	}
+
+	return ""
+}
+
+// InspectParams are the parameters of a CS2 inspect link, what float
+// lookup services take to report the wear, paint seed and such.  Exactly
+// one of Owner or Market is set.
+type InspectParams struct {
+	Owner  SteamID
+	Market uint64 // listing ID when inspected from the market
+	Asset  uint64
+	D      uint64
+}
+
+// InspectLink fills the "Inspect in Game..." action link for an asset of
+// owner, empty when the item cannot be inspected.
+func (desc *EconItemDesc) InspectLink(owner SteamID, assetID uint64) string {
+	for _, action := range desc.Actions {
+		if action == nil || !strings.Contains(action.Link, "csgo_econ_action_preview") {
+			continue
+		}
+
+		return strings.NewReplacer(
+			"%owner_steamid%", owner.ToString(),
+			"%assetid%", strconv.FormatUint(assetID, 10),
+		).Replace(action.Link)
+	}
+
+	return ""
+}
+
+// InspectLink is EconItemDesc.InspectLink for an inventory item of owner.
+func (item *InventoryItem) InspectLink(owner SteamID) string {
+	if item.Desc == nil {
+		return ""
+	}
+
+	return item.Desc.InspectLink(owner, item.AssetID)
+}
+
+// ParseInspectLink extracts the parameters of a filled inspect link.
+func ParseInspectLink(link string) (*InspectParams, bool) {
+	if unescaped, err := url.PathUnescape(link); err == nil {
+		link = unescaped
+	}
+
+	m := inspectExp.FindStringSubmatch(link)
+	if m == nil {
+		return nil, false
+	}
+
+	id, _ := strconv.ParseUint(m[2], 10, 64)
+	asset, _ := strconv.ParseUint(m[3], 10, 64)
+	d, _ := strconv.ParseUint(m[4], 10, 64)
+
+	params := &InspectParams{Asset: asset, D: d}
+	if m[1] == "S" {
+		params.Owner = SteamID(id)
+	} else {
+		params.Market = id
+	}
+
+	return params, true
+}
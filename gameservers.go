@@ -0,0 +1,128 @@
+package steam
+
+import (
+	"net/url"
+	"strconv"
+)
+
+const (
+	apiGetAccountList  = "/IGameServersService/GetAccountList/v1/?"
+	apiCreateAccount   = "/IGameServersService/CreateAccount/v1/"
+	apiSetMemo         = "/IGameServersService/SetMemo/v1/"
+	apiResetLoginToken = "/IGameServersService/ResetLoginToken/v1/"
+	apiDeleteAccount   = "/IGameServersService/DeleteAccount/v1/"
+)
+
+// GameServerAccount is a persistent game server account and its Game
+// Server Login Token (GSLT).
+type GameServerAccount struct {
+	SteamID    SteamID `json:"steamid,string"`
+	AppID      uint32  `json:"appid"`
+	LoginToken string  `json:"login_token"`
+	Memo       string  `json:"memo"`
+	IsDeleted  bool    `json:"is_deleted"`
+	IsExpired  bool    `json:"is_expired"`
+	LastLogon  int64   `json:"rt_last_logon"` // unix time
+}
+
+// GameServerAccounts is what GetGameServerAccounts returns.
+type GameServerAccounts struct {
+	Servers  []*GameServerAccount `json:"servers"`
+	IsBanned bool                 `json:"is_banned"`
+	Expires  int64                `json:"expires"` // unix time, when IsBanned
+	Actor    SteamID              `json:"actor,string"`
+}
+
+// GetGameServerAccounts lists the game server accounts owned by the
+// account of the API key.
+func (session *Session) GetGameServerAccounts() (*GameServerAccounts, error) {
+	var response struct {
+		Inner *GameServerAccounts `json:"response"`
+	}
+	if err := session.getAPI(session.APIBaseURL()+apiGetAccountList, session.authorize(url.Values{}), &response); err != nil {
+		return nil, err
+	}
+
+	if response.Inner == nil {
+		return nil, ErrInvalidResponse
+	}
+
+	return response.Inner, nil
+}
+
+// CreateGameServerAccount creates a game server account for appID (730
+// for CS2, 440 for TF2...) and returns it with its GSLT.
+func (session *Session) CreateGameServerAccount(appID uint32, memo string) (*GameServerAccount, error) {
+	if err := session.checkWritable(); err != nil {
+		return nil, err
+	}
+
+	var response struct {
+		Inner *GameServerAccount `json:"response"`
+	}
+	err := session.postAPI(session.APIBaseURL()+apiCreateAccount, session.authorize(url.Values{
+		"appid": {strconv.FormatUint(uint64(appID), 10)},
+		"memo":  {memo},
+	}), &response)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.Inner == nil || len(response.Inner.LoginToken) == 0 {
+		return nil, ErrInvalidResponse
+	}
+
+	response.Inner.AppID = appID
+	response.Inner.Memo = memo
+	return response.Inner, nil
+}
+
+// SetGameServerMemo changes the memo of a game server account.
+func (session *Session) SetGameServerMemo(sid SteamID, memo string) error {
+	if err := session.checkWritable(); err != nil {
+		return err
+	}
+
+	return session.postAPI(session.APIBaseURL()+apiSetMemo, session.authorize(url.Values{
+		"steamid": {sid.ToString()},
+		"memo":    {memo},
+	}), nil)
+}
+
+// ResetGameServerLoginToken generates a new GSLT for a game server
+// account, the previous one stops working.
+func (session *Session) ResetGameServerLoginToken(sid SteamID) (string, error) {
+	if err := session.checkWritable(); err != nil {
+		return "", err
+	}
+
+	var response struct {
+		Inner *struct {
+			LoginToken string `json:"login_token"`
+		} `json:"response"`
+	}
+	err := session.postAPI(session.APIBaseURL()+apiResetLoginToken, session.authorize(url.Values{
+		"steamid": {sid.ToString()},
+	}), &response)
+	if err != nil {
+		return "", err
+	}
+
+	if response.Inner == nil || len(response.Inner.LoginToken) == 0 {
+		return "", ErrInvalidResponse
+	}
+
+	return response.Inner.LoginToken, nil
+}
+
+// DeleteGameServerAccount deletes a game server account, servers using
+// its GSLT get disconnected.
+func (session *Session) DeleteGameServerAccount(sid SteamID) error {
+	if err := session.checkWritable(); err != nil {
+		return err
+	}
+
+	return session.postAPI(session.APIBaseURL()+apiDeleteAccount, session.authorize(url.Values{
+		"steamid": {sid.ToString()},
+	}), nil)
+}
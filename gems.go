@@ -0,0 +1,301 @@
+package steam
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+const (
+	profileAjaxURL      = "/profiles/%d/%s/"
+	createBoosterURL    = "/tradingcards/ajaxcreatebooster/"
+	gemsClassID         = 667924416 // the "Gems" item of the Steam inventory
+	gemsPerSack         = 1000
+	boosterSeries       = "1"
+	tradablePreferred   = "1"
+	untradablePreferred = "2"
+)
+
+var ErrGemOperationFailed = errors.New("gem operation failed")
+
+// CraftedBadge is the badge level CraftBadge produced.
+type CraftedBadge struct {
+	Level       int    `json:"level"`
+	XP          int    `json:"xp"`
+	Name        string `json:"strName"`
+	NextLevelXP int    `json:"next_level_xp"`
+}
+
+// BoosterPack is a pack made by CreateBoosterPack, still to be unpacked.
+type BoosterPack struct {
+	AppID           uint32 `json:"appid"`
+	CommunityItemID uint64 `json:"communityitemid,string"`
+	Name            string `json:"name"`
+	Tradable        bool   `json:"-"`
+	GooLeft         uint64 `json:"-"` // gems left, tradable and not
+}
+
+// UnpackedCard is one of the items found in an unpacked booster.
+type UnpackedCard struct {
+	Image  string `json:"image"`
+	Name   string `json:"name"`
+	Series int    `json:"series"`
+	Foil   bool   `json:"foil"`
+}
+
+// postProfileAjax POSTs one of the ajax endpoints under the profile of the
+// session and decodes the answer, failing unless its success is 1.
+func (session *Session) postProfileAjax(endpoint string, params url.Values, response interface{}) error {
+	if err := session.checkWritable(); err != nil {
+		return err
+	}
+
+	params.Set("sessionid", session.sessionID)
+	resp, err := session.client.PostForm(session.CommunityBaseURL()+fmt.Sprintf(profileAjaxURL, session.oauth.SteamID, endpoint), params)
+	if resp != nil {
+		defer func(Body io.ReadCloser) {
+			err := Body.Close()
+			if err != nil {
+				return
+			}
+		}(resp.Body)
+	}
+
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("http error: %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(session.limitBody(resp))
+	if err != nil {
+		return err
+	}
+
+	var status struct {
+		Success json.Number `json:"success"`
+		Message string      `json:"message"`
+	}
+	if err = json.Unmarshal(body, &status); err != nil {
+		return err
+	}
+	if status.Success != "1" {
+		if len(status.Message) != 0 {
+			return fmt.Errorf("%s: %s", endpoint, status.Message)
+		}
+
+		return fmt.Errorf("%s: %w (%s)", endpoint, ErrGemOperationFailed, status.Success)
+	}
+
+	if response == nil {
+		return nil
+	}
+
+	return json.Unmarshal(body, response)
+}
+
+// CraftBadge crafts the next level of the badge of appID from a set of its
+// cards, foil for the foil badge.
+func (session *Session) CraftBadge(appID uint32, foil bool) (*CraftedBadge, error) {
+	border := "0"
+	if foil {
+		border = "1"
+	}
+
+	var response struct {
+		Badge *CraftedBadge `json:"Badge"`
+	}
+	err := session.postProfileAjax("ajaxcraftbadge", url.Values{
+		"appid":        {strconv.FormatUint(uint64(appID), 10)},
+		"series":       {boosterSeries},
+		"border_color": {border},
+		"levels":       {"1"},
+	}, &response)
+	if err != nil {
+		return nil, err
+	}
+
+	session.InvalidateInventory(session.oauth.SteamID)
+	if response.Badge == nil {
+		return nil, ErrInvalidResponse
+	}
+
+	return response.Badge, nil
+}
+
+// GetGemValue returns how many gems grinding the community item assetID of
+// the game appID gives.
+func (session *Session) GetGemValue(appID uint32, assetID uint64) (uint64, error) {
+	resp, err := session.client.Get(session.CommunityBaseURL() + fmt.Sprintf(profileAjaxURL, session.oauth.SteamID, "ajaxgetgoovalue") + "?" + url.Values{
+		"sessionid": {session.sessionID},
+		"appid":     {strconv.FormatUint(uint64(appID), 10)},
+		"assetid":   {strconv.FormatUint(assetID, 10)},
+		"contextid": {strconv.Itoa(ContextIDCommunity)},
+	}.Encode())
+	if resp != nil {
+		defer func(Body io.ReadCloser) {
+			err := Body.Close()
+			if err != nil {
+				return
+			}
+		}(resp.Body)
+	}
+
+	if err != nil {
+		return 0, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("http error: %d", resp.StatusCode)
+	}
+
+	var response struct {
+		Success int         `json:"success"`
+		Value   json.Number `json:"goo_value"`
+	}
+	if err = session.readJSON(resp, &response); err != nil {
+		return 0, err
+	}
+	if response.Success != 1 {
+		return 0, ErrGemOperationFailed
+	}
+
+	return strconv.ParseUint(response.Value.String(), 10, 64)
+}
+
+// GrindIntoGems turns the community item assetID of the game appID into
+// gems, expected is the value GetGemValue returned and guards against a
+// price change in between.  It returns the gems received.
+func (session *Session) GrindIntoGems(appID uint32, assetID, expected uint64) (uint64, error) {
+	// Steam names the field with a trailing space, both spellings are
+	// accepted in case it is ever fixed.
+	var response struct {
+		Received      json.Number `json:"goo_value_received "`
+		ReceivedFixed json.Number `json:"goo_value_received"`
+	}
+	err := session.postProfileAjax("ajaxgrindintogoo", url.Values{
+		"appid":              {strconv.FormatUint(uint64(appID), 10)},
+		"assetid":            {strconv.FormatUint(assetID, 10)},
+		"contextid":          {strconv.Itoa(ContextIDCommunity)},
+		"goo_value_expected": {strconv.FormatUint(expected, 10)},
+	}, &response)
+	if err != nil {
+		return 0, err
+	}
+
+	session.InvalidateInventory(session.oauth.SteamID)
+	received := response.Received
+	if len(received) == 0 {
+		received = response.ReceivedFixed
+	}
+
+	return strconv.ParseUint(received.String(), 10, 64)
+}
+
+// exchangeGems converts between loose gems and sacks of gems, assetID is
+// the stack to take from.
+func (session *Session) exchangeGems(assetID uint64, denominationIn, amountIn, denominationOut, amountOut uint64) error {
+	err := session.postProfileAjax("ajaxexchangegoo", url.Values{
+		"appid":                {strconv.Itoa(AppIDSteam)},
+		"assetid":              {strconv.FormatUint(assetID, 10)},
+		"goo_denomination_in":  {strconv.FormatUint(denominationIn, 10)},
+		"goo_amount_in":        {strconv.FormatUint(amountIn, 10)},
+		"goo_denomination_out": {strconv.FormatUint(denominationOut, 10)},
+		"goo_amount_out":       {strconv.FormatUint(amountOut, 10)},
+	}, nil)
+	if err != nil {
+		return err
+	}
+
+	session.InvalidateInventory(session.oauth.SteamID)
+	return nil
+}
+
+// PackGems packs sacks of 1000 gems taken from the gem stack assetID.
+func (session *Session) PackGems(assetID uint64, sacks uint64) error {
+	return session.exchangeGems(assetID, 1, sacks*gemsPerSack, gemsPerSack, sacks)
+}
+
+// UnpackGems opens sacks sacks of gems taken from the sack stack assetID.
+func (session *Session) UnpackGems(assetID uint64, sacks uint64) error {
+	return session.exchangeGems(assetID, gemsPerSack, sacks, 1, sacks*gemsPerSack)
+}
+
+// IsGems reports whether the item is a stack of loose gems.
+func (item *InventoryItem) IsGems() bool {
+	return item.AppID == AppIDSteam && item.ContextID == ContextIDCommunity && item.ClassID == gemsClassID
+}
+
+// CreateBoosterPack spends gems on a booster pack of appID, see
+// GetBoosterEligibility for the price and availability.  Tradable gems
+// are used first when tradable is set, untradable ones otherwise.
+func (session *Session) CreateBoosterPack(appID uint32, tradable bool) (*BoosterPack, error) {
+	if err := session.checkWritable(); err != nil {
+		return nil, err
+	}
+
+	preference := untradablePreferred
+	if tradable {
+		preference = tradablePreferred
+	}
+
+	resp, err := session.client.PostForm(session.CommunityBaseURL()+createBoosterURL, url.Values{
+		"sessionid":              {session.sessionID},
+		"appid":                  {strconv.FormatUint(uint64(appID), 10)},
+		"series":                 {boosterSeries},
+		"tradability_preference": {preference},
+	})
+	if resp != nil {
+		defer func(Body io.ReadCloser) {
+			err := Body.Close()
+			if err != nil {
+				return
+			}
+		}(resp.Body)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("http error: %d", resp.StatusCode)
+	}
+
+	var response struct {
+		Result *BoosterPack `json:"purchase_result"`
+		Goo    json.Number  `json:"goo_amount"`
+	}
+	if err = session.readJSON(resp, &response); err != nil {
+		return nil, err
+	}
+
+	if response.Result == nil || response.Result.CommunityItemID == 0 {
+		return nil, ErrGemOperationFailed
+	}
+
+	session.InvalidateInventory(session.oauth.SteamID)
+	response.Result.Tradable = tradable
+	response.Result.GooLeft, _ = strconv.ParseUint(response.Goo.String(), 10, 64)
+	return response.Result, nil
+}
+
+// UnpackBooster opens the booster pack communityItemID of appID.
+func (session *Session) UnpackBooster(appID uint32, communityItemID uint64) ([]*UnpackedCard, error) {
+	var response struct {
+		Items []*UnpackedCard `json:"rgItems"`
+	}
+	err := session.postProfileAjax("ajaxunpackbooster", url.Values{
+		"appid":           {strconv.FormatUint(uint64(appID), 10)},
+		"communityitemid": {strconv.FormatUint(communityItemID, 10)},
+	}, &response)
+	if err != nil {
+		return nil, err
+	}
+
+	session.InvalidateInventory(session.oauth.SteamID)
+	return response.Items, nil
+}
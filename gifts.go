@@ -0,0 +1,69 @@
+package steam
+
+import (
+	"errors"
+	"strconv"
+)
+
+// Contexts of the Steam (753) inventory.  Gifts and coupons are not items
+// of any game, they can still only be handed to someone else through a
+// trade offer.
+const (
+	AppIDSteam         = 753
+	ContextIDGifts     = 1
+	ContextIDCoupons   = 3
+	ContextIDCommunity = 6 // trading cards, backgrounds, emoticons...
+)
+
+var ErrNotGiftOrCoupon = errors.New("item is neither a gift nor a coupon")
+
+// EconItem converts an inventory item to the form trade offers take.
+func (item *InventoryItem) EconItem() *EconItem {
+	amount := item.Amount
+	if len(amount) == 0 {
+		amount = "1"
+	}
+
+	return &EconItem{
+		AssetID:   strconv.FormatUint(item.AssetID, 10),
+		AppID:     item.AppID,
+		ContextID: strconv.FormatUint(item.ContextID, 10),
+		Amount:    amount,
+	}
+}
+
+// IsGift reports whether the item is an unredeemed game gift.
+func (item *InventoryItem) IsGift() bool {
+	return item.AppID == AppIDSteam && item.ContextID == ContextIDGifts
+}
+
+// IsCoupon reports whether the item is a store discount coupon.
+func (item *InventoryItem) IsCoupon() bool {
+	return item.AppID == AppIDSteam && item.ContextID == ContextIDCoupons
+}
+
+func (session *Session) GetGifts(sid SteamID) (*Inventory, error) {
+	return session.GetInventoryContents(sid, AppIDSteam, ContextIDGifts, nil)
+}
+
+// GetCoupons returns the store coupons of sid, they are applied
+// automatically at checkout so there is nothing to redeem programmatically.
+func (session *Session) GetCoupons(sid SteamID) (*Inventory, error) {
+	return session.GetInventoryContents(sid, AppIDSteam, ContextIDCoupons, nil)
+}
+
+// SendGift sends a gift or coupon to sid through a trade offer, token is
+// needed unless sid is a friend.
+func (session *Session) SendGift(item *InventoryItem, sid SteamID, token, message string) error {
+	if !item.IsGift() && !item.IsCoupon() {
+		return ErrNotGiftOrCoupon
+	}
+
+	offer := &TradeOffer{
+		SendItems: []*EconItem{item.EconItem()},
+		RecvItems: []*EconItem{},
+		Message:   message,
+	}
+
+	return session.SendTradeOffer(offer, sid, token)
+}
@@ -8,7 +8,9 @@ require github.com/PuerkitoBio/goquery v1.10.2
 
 require google.golang.org/protobuf v1.36.5
 
+require golang.org/x/crypto v0.33.0
+
 require (
 	github.com/andybalholm/cascadia v1.3.3 // indirect
-	golang.org/x/net v0.35.0 // indirect
+	golang.org/x/net v0.35.0
 )
@@ -0,0 +1,191 @@
+package steam
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+const (
+	groupURL            = "/gid/%d/"
+	groupMembersListURL = "/gid/%d/memberslistxml/?"
+	groupInviteURL      = "/actions/GroupInvite"
+	profileHomeURL      = "/profiles/%d/home_process"
+)
+
+var ErrCannotInviteToGroup = errors.New("unable to invite user to group")
+
+type GroupMembersPage struct {
+	GroupID      SteamID   `xml:"groupID64"`
+	MemberCount  uint32    `xml:"memberCount"`
+	TotalPages   uint32    `xml:"totalPages"`
+	CurrentPage  uint32    `xml:"currentPage"`
+	Members      []SteamID `xml:"members>steamID64"`
+	NextPageLink string    `xml:"nextPageLink"`
+}
+
+func (session *Session) postGroupForm(u string, values url.Values) (*http.Response, error) {
+	resp, err := session.client.PostForm(u, values)
+	if err != nil {
+		if resp != nil {
+			resp.Body.Close()
+		}
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("http error: %d", resp.StatusCode)
+	}
+
+	return resp, nil
+}
+
+func (session *Session) JoinGroup(gid SteamID) error {
+	if err := session.checkWritable(); err != nil {
+		return err
+	}
+
+	resp, err := session.postGroupForm(session.CommunityBaseURL()+fmt.Sprintf(groupURL, gid), url.Values{
+		"action":    {"join"},
+		"sessionID": {session.sessionID},
+	})
+	if err != nil {
+		return err
+	}
+
+	return resp.Body.Close()
+}
+
+func (session *Session) LeaveGroup(gid SteamID) error {
+	if err := session.checkWritable(); err != nil {
+		return err
+	}
+
+	resp, err := session.postGroupForm(session.CommunityBaseURL()+fmt.Sprintf(profileHomeURL, session.oauth.SteamID), url.Values{
+		"action":    {"leaveGroup"},
+		"groupId":   {gid.ToString()},
+		"sessionID": {session.sessionID},
+	})
+	if err != nil {
+		return err
+	}
+
+	return resp.Body.Close()
+}
+
+func (session *Session) InviteToGroup(gid, sid SteamID) error {
+	if err := session.checkWritable(); err != nil {
+		return err
+	}
+
+	resp, err := session.postGroupForm(session.CommunityBaseURL()+groupInviteURL, url.Values{
+		"json":      {"1"},
+		"type":      {"groupInvite"},
+		"group":     {gid.ToString()},
+		"invitee":   {sid.ToString()},
+		"sessionID": {session.sessionID},
+	})
+	if err != nil {
+		return err
+	}
+	defer func(Body io.ReadCloser) {
+		err := Body.Close()
+		if err != nil {
+			return
+		}
+	}(resp.Body)
+
+	type Response struct {
+		Results string `json:"results"`
+	}
+
+	var response Response
+	if err = session.readJSON(resp, &response); err != nil {
+		return err
+	}
+
+	if response.Results != "OK" {
+		if len(response.Results) != 0 {
+			return errors.New(response.Results)
+		}
+
+		return ErrCannotInviteToGroup
+	}
+
+	return nil
+}
+
+// GetGroupMembersPage fetches a single page (starting at 1) of the group
+// member list.
+func (session *Session) GetGroupMembersPage(gid SteamID, page uint32) (*GroupMembersPage, error) {
+	resp, err := session.client.Get(session.CommunityBaseURL() + fmt.Sprintf(groupMembersListURL, gid) + url.Values{
+		"xml": {"1"},
+		"p":   {strconv.FormatUint(uint64(page), 10)},
+	}.Encode())
+	if resp != nil {
+		defer func(Body io.ReadCloser) {
+			err := Body.Close()
+			if err != nil {
+				return
+			}
+		}(resp.Body)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("http error: %d", resp.StatusCode)
+	}
+
+	members := &GroupMembersPage{}
+	if err = xml.NewDecoder(resp.Body).Decode(members); err != nil {
+		return nil, err
+	}
+
+	return members, nil
+}
+
+// GetGroupMembers walks every page of the group member list.
+func (session *Session) GetGroupMembers(gid SteamID) ([]SteamID, error) {
+	var members []SteamID
+
+	for page := uint32(1); ; page++ {
+		m, err := session.GetGroupMembersPage(gid, page)
+		if err != nil {
+			return nil, err
+		}
+
+		members = append(members, m.Members...)
+		if m.CurrentPage >= m.TotalPages || len(m.NextPageLink) == 0 {
+			break
+		}
+	}
+
+	return members, nil
+}
+
+// PostGroupAnnouncement requires the session to be a moderator of the group.
+func (session *Session) PostGroupAnnouncement(gid SteamID, headline, body string) error {
+	if err := session.checkWritable(); err != nil {
+		return err
+	}
+
+	resp, err := session.postGroupForm(session.CommunityBaseURL()+fmt.Sprintf(groupURL, gid)+"announcements", url.Values{
+		"action":    {"post"},
+		"headline":  {headline},
+		"body":      {body},
+		"sessionID": {session.sessionID},
+	})
+	if err != nil {
+		return err
+	}
+
+	return resp.Body.Close()
+}
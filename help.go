@@ -0,0 +1,78 @@
+package steam
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+const (
+	helpBaseURL        = "https://help.steampowered.com"
+	helpLockAccountURL = helpBaseURL + "/en/wizard/AjaxLockAccount"
+)
+
+var ErrCannotLockAccount = errors.New("unable to lock account")
+
+// PrepareForHelpSite copies the community cookies over to the help site,
+// much like PrepareForSteamStore does for the store.
+func (session *Session) PrepareForHelpSite() {
+	community, _ := url.Parse(session.CommunityBaseURL())
+	help, _ := url.Parse(helpBaseURL)
+
+	session.client.Jar.SetCookies(help, session.client.Jar.Cookies(community))
+}
+
+// LockAccount runs the help site "my account was stolen" lock, which logs
+// out every session and blocks trading and market use until the account is
+// recovered through Steam Support.  It is meant to be called by monitoring
+// code as a last resort, there is no way to undo it programmatically.
+func (session *Session) LockAccount() error {
+	if err := session.checkWritable(); err != nil {
+		return err
+	}
+
+	session.PrepareForHelpSite()
+
+	resp, err := session.client.PostForm(helpLockAccountURL, url.Values{
+		"sessionid":   {session.sessionID},
+		"wizard_ajax": {"1"},
+	})
+	if resp != nil {
+		defer func(Body io.ReadCloser) {
+			err := Body.Close()
+			if err != nil {
+				return
+			}
+		}(resp.Body)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("http error: %d", resp.StatusCode)
+	}
+
+	type Response struct {
+		Success  bool   `json:"success"`
+		ErrorMsg string `json:"errorMsg"`
+	}
+
+	var response Response
+	if err = session.readJSON(resp, &response); err != nil {
+		return err
+	}
+
+	if !response.Success {
+		if len(response.ErrorMsg) != 0 {
+			return errors.New(response.ErrorMsg)
+		}
+
+		return ErrCannotLockAccount
+	}
+
+	return nil
+}
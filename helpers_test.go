@@ -0,0 +1,48 @@
+package steam
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+const testSteamID SteamID = 76561197960287930
+
+// hostTransport sends every request to a local test server, keeping the
+// Steam host in Request.Host so handlers can tell them apart.
+type hostTransport struct {
+	target *url.URL
+	next   http.RoundTripper
+}
+
+func (t *hostTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	r := req.Clone(req.Context())
+	r.Host = req.URL.Host
+	r.URL.Scheme = t.target.Scheme
+	r.URL.Host = t.target.Host
+	return t.next.RoundTrip(r)
+}
+
+// newTestSession returns a logged in looking session whose requests are
+// all answered by h.
+func newTestSession(t *testing.T, h http.Handler) *Session {
+	t.Helper()
+
+	srv := httptest.NewServer(h)
+	t.Cleanup(srv.Close)
+
+	target, _ := url.Parse(srv.URL)
+	session := NewSession(&http.Client{Transport: &hostTransport{target: target, next: srv.Client().Transport}}, "key")
+	session.sessionID = "sessionid"
+	session.oauth.SteamID = testSteamID
+	return session
+}
+
+// answer is a handler writing body as JSON.
+func answer(body string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(body))
+	})
+}
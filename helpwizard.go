@@ -0,0 +1,159 @@
+package steam
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+const (
+	apiGetTradeHistory = "IEconService/GetTradeHistory"
+
+	helpAccountStolenURL = helpBaseURL + "/en/wizard/HelpWithAccountStolen"
+	helpTradeURL         = helpBaseURL + "/en/wizard/HelpWithTrade?tradeid=%d"
+)
+
+var ErrManualStep = errors.New("help flow needs a manual step but no callback was given")
+
+type TradeHistoryAsset struct {
+	AppID        uint32 `json:"appid"`
+	ContextID    uint64 `json:"contextid,string"`
+	AssetID      uint64 `json:"assetid,string"`
+	Amount       string `json:"amount"`
+	ClassID      uint64 `json:"classid,string"`
+	InstanceID   uint64 `json:"instanceid,string"`
+	NewAssetID   uint64 `json:"new_assetid,string"`
+	NewContextID uint64 `json:"new_contextid,string"`
+}
+
+type TradeHistoryEntry struct {
+	TradeID        uint64               `json:"tradeid,string"`
+	Partner        SteamID              `json:"steamid_other,string"`
+	TimeInit       int64                `json:"time_init"`
+	Status         uint8                `json:"status"`
+	AssetsGiven    []*TradeHistoryAsset `json:"assets_given"`
+	AssetsReceived []*TradeHistoryAsset `json:"assets_received"`
+}
+
+// GetTradeHistory returns the most recent completed trades, newest first.
+func (session *Session) GetTradeHistory(maxTrades uint32) ([]*TradeHistoryEntry, error) {
+	resp, err := session.client.Get(session.apiURL(apiGetTradeHistory) + "?" + session.authorize(url.Values{
+		"max_trades":             {strconv.FormatUint(uint64(maxTrades), 10)},
+		"include_failed":         {"1"},
+		"get_descriptions":       {"0"},
+		"include_total":          {"0"},
+		"navigating_back":        {"0"},
+		"start_after_time":       {"0"},
+		"start_after_tradeid":    {"0"},
+		"get_descriptions_count": {"0"},
+	}).Encode())
+	if resp != nil {
+		defer func(Body io.ReadCloser) {
+			err := Body.Close()
+			if err != nil {
+				return
+			}
+		}(resp.Body)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	type Response struct {
+		Inner *struct {
+			Trades []*TradeHistoryEntry `json:"trades"`
+		} `json:"response"`
+	}
+
+	var response Response
+	if err = session.readJSON(resp, &response); err != nil {
+		return nil, err
+	}
+
+	if response.Inner == nil {
+		return nil, ErrInvalidResponse
+	}
+
+	return response.Inner.Trades, nil
+}
+
+// HelpStep is one step of a Steam Support flow.  Steps without Run have to
+// be done by a human in a browser logged into the account, at URL.
+type HelpStep struct {
+	Description string
+	URL         string
+	Run         func(*Session) error
+}
+
+// HelpFlow is the sequence of help site steps to get out of a situation,
+// see RunHelpFlow.
+type HelpFlow struct {
+	Name  string
+	Steps []*HelpStep
+}
+
+// ItemRecoveryFlow walks through reporting a trade that took items without
+// the owner's consent, so Support can roll it back.
+func ItemRecoveryFlow(trade *TradeHistoryEntry) *HelpFlow {
+	return &HelpFlow{
+		Name: fmt.Sprintf("recover items of trade %d", trade.TradeID),
+		Steps: []*HelpStep{
+			{
+				Description: fmt.Sprintf(
+					"Report trade %d with %s from %s, %d items given away",
+					trade.TradeID, trade.Partner.ToString(),
+					time.Unix(trade.TimeInit, 0).UTC().Format(time.RFC3339), len(trade.AssetsGiven),
+				),
+				URL: fmt.Sprintf(helpTradeURL, trade.TradeID),
+			},
+		},
+	}
+}
+
+// HijackReportFlow reports a compromised account, locking it first when
+// lock is set (see LockAccount, it cannot be undone).
+func HijackReportFlow(lock bool) *HelpFlow {
+	flow := &HelpFlow{Name: "report account hijack"}
+
+	if lock {
+		flow.Steps = append(flow.Steps, &HelpStep{
+			Description: "Lock the account",
+			Run:         (*Session).LockAccount,
+		})
+	}
+
+	flow.Steps = append(flow.Steps, &HelpStep{
+		Description: "Tell Support the account was stolen and follow the recovery",
+		URL:         helpAccountStolenURL,
+	})
+
+	return flow
+}
+
+// RunHelpFlow runs the automated steps of flow and hands the manual ones to
+// manual, stopping at the first error.
+func (session *Session) RunHelpFlow(flow *HelpFlow, manual func(*HelpStep) error) error {
+	session.PrepareForHelpSite()
+
+	for _, step := range flow.Steps {
+		var err error
+		switch {
+		case step.Run != nil:
+			err = step.Run(session)
+		case manual != nil:
+			err = manual(step)
+		default:
+			err = ErrManualStep
+		}
+
+		if err != nil {
+			return fmt.Errorf("%s: %s: %w", flow.Name, step.Description, err)
+		}
+	}
+
+	return nil
+}
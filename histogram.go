@@ -0,0 +1,146 @@
+package steam
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+)
+
+var (
+	itemNameIDExp = regexp.MustCompile(`Market_LoadOrderSpread\(\s*(\d+)\s*\)`)
+
+	ErrCannotFindItemNameID = errors.New("unable to find item_nameid on the listing page")
+)
+
+// OrderBookEntry is one price level of the order book, Quantity is
+// cumulative: the number of orders at Price or better.
+type OrderBookEntry struct {
+	Price    float64
+	Quantity uint64
+}
+
+// ItemOrdersHistogram prices are in cents of the requested currency.
+type ItemOrdersHistogram struct {
+	HighestBuyOrder uint64
+	LowestSellOrder uint64
+	BuyOrders       []OrderBookEntry // best (highest) price first
+	SellOrders      []OrderBookEntry // best (lowest) price first
+	PricePrefix     string
+	PriceSuffix     string
+}
+
+// GetItemNameID reads the item_nameid GetItemOrdersHistogram needs from
+// the market listing page of the item.
+func (session *Session) GetItemNameID(appID uint64, marketHashName string) (uint64, error) {
+	body, err := session.getPage(session.CommunityBaseURL() + fmt.Sprintf(
+		"/market/listings/%d/%s",
+		appID, url.PathEscape(marketHashName),
+	))
+	if err != nil {
+		return 0, err
+	}
+
+	m := itemNameIDExp.FindSubmatch(body)
+	if m == nil {
+		return 0, ErrCannotFindItemNameID
+	}
+
+	return strconv.ParseUint(string(m[1]), 10, 64)
+}
+
+func parseOrderGraph(graph [][]interface{}) []OrderBookEntry {
+	entries := make([]OrderBookEntry, 0, len(graph))
+	for _, point := range graph {
+		if len(point) < 2 {
+			continue
+		}
+
+		price, ok := point[0].(json.Number)
+		if !ok {
+			continue
+		}
+
+		quantity, ok := point[1].(json.Number)
+		if !ok {
+			continue
+		}
+
+		p, err := price.Float64()
+		if err != nil {
+			continue
+		}
+
+		q, err := strconv.ParseUint(quantity.String(), 10, 64)
+		if err != nil {
+			continue
+		}
+
+		entries = append(entries, OrderBookEntry{Price: p, Quantity: q})
+	}
+
+	return entries
+}
+
+// GetItemOrdersHistogram returns the order book of an item, currency is one
+// of the Currency* constants.
+func (session *Session) GetItemOrdersHistogram(itemNameID uint64, currency string) (*ItemOrdersHistogram, error) {
+	resp, err := session.client.Get(session.CommunityBaseURL() + "/market/itemordershistogram?" + url.Values{
+		"country":     {"US"},
+		"language":    {"english"},
+		"currency":    {currency},
+		"item_nameid": {strconv.FormatUint(itemNameID, 10)},
+		"two_factor":  {"0"},
+	}.Encode())
+	if resp != nil {
+		defer func(Body io.ReadCloser) {
+			err := Body.Close()
+			if err != nil {
+				return
+			}
+		}(resp.Body)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("http error: %d", resp.StatusCode)
+	}
+
+	type Response struct {
+		Success         int             `json:"success"`
+		HighestBuyOrder string          `json:"highest_buy_order"`
+		LowestSellOrder string          `json:"lowest_sell_order"`
+		BuyOrderGraph   [][]interface{} `json:"buy_order_graph"`
+		SellOrderGraph  [][]interface{} `json:"sell_order_graph"`
+		PricePrefix     string          `json:"price_prefix"`
+		PriceSuffix     string          `json:"price_suffix"`
+	}
+
+	var response Response
+	if err = decodeJSON(session.limitBody(resp), &response); err != nil {
+		return nil, err
+	}
+
+	if response.Success != 1 {
+		return nil, ErrInvalidResponse
+	}
+
+	/* Both are null when there are no orders on that side.  */
+	highest, _ := strconv.ParseUint(response.HighestBuyOrder, 10, 64)
+	lowest, _ := strconv.ParseUint(response.LowestSellOrder, 10, 64)
+
+	return &ItemOrdersHistogram{
+		HighestBuyOrder: highest,
+		LowestSellOrder: lowest,
+		BuyOrders:       parseOrderGraph(response.BuyOrderGraph),
+		SellOrders:      parseOrderGraph(response.SellOrderGraph),
+		PricePrefix:     response.PricePrefix,
+		PriceSuffix:     response.PriceSuffix,
+	}, nil
+}
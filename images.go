@@ -0,0 +1,203 @@
+package steam
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+)
+
+const (
+	economyImageURL = "https://community.akamai.steamstatic.com/economy/image/"
+	avatarImageURL  = "https://avatars.akamai.steamstatic.com/"
+	appImageURL     = "https://media.steampowered.com/steamcommunity/public/images/apps/"
+
+	// The all-zero hash Steam uses for accounts without an avatar.
+	defaultAvatarHash = "0000000000000000000000000000000000000000"
+)
+
+// Avatar sizes, 32, 64 and 184 pixels square.
+const (
+	AvatarSizeSmall  = ""
+	AvatarSizeMedium = "_medium"
+	AvatarSizeFull   = "_full"
+)
+
+// Common economy image sizes, any "<w>fx<h>f" (fixed) or "<w>x<h>" (fit)
+// value is accepted by the CDN.
+const (
+	ImageSizeSmall  = "96fx96f"
+	ImageSizeMedium = "256fx256f"
+	ImageSizeLarge  = "360fx360f"
+)
+
+// ImageURL builds the CDN URL of the item icon at the given size, an empty
+// size returns the original image.
+func (desc *EconItemDesc) ImageURL(size string) string {
+	if len(desc.IconURL) == 0 {
+		return ""
+	}
+
+	if len(size) == 0 {
+		return economyImageURL + desc.IconURL
+	}
+
+	return economyImageURL + desc.IconURL + "/" + size
+}
+
+// LargeImageURL is like ImageURL but prefers icon_url_large when the item
+// has one.
+func (desc *EconItemDesc) LargeImageURL(size string) string {
+	if len(desc.IconLargeURL) == 0 {
+		return desc.ImageURL(size)
+	}
+
+	large := *desc
+	large.IconURL = desc.IconLargeURL
+	return large.ImageURL(size)
+}
+
+// AvatarURL builds the CDN URL of the avatar with the given hash, an empty
+// hash is the default avatar.
+func AvatarURL(hash, size string) string {
+	if len(hash) == 0 {
+		hash = defaultAvatarHash
+	}
+
+	return avatarImageURL + hash + size + ".jpg"
+}
+
+// Avatar is the avatar of the player at the given size.
+func (summary *PlayerSummary) Avatar(size string) string {
+	return AvatarURL(summary.AvatarHash, size)
+}
+
+// IconImageURL is the icon of the game, empty unless the game came with
+// its app info.
+func (game *Game) IconImageURL() string {
+	if len(game.IconURL) == 0 {
+		return ""
+	}
+
+	return appImageURL + strconv.FormatUint(uint64(game.AppID), 10) + "/" + game.IconURL + ".jpg"
+}
+
+// GetImage downloads an image, e.g. one of the URLs above, see
+// ImageDownloader to keep them on disk.
+func (session *Session) GetImage(imageURL string) ([]byte, error) {
+	return session.getPage(imageURL)
+}
+
+type imageDownload struct {
+	wg   sync.WaitGroup
+	path string
+	err  error
+}
+
+// ImageDownloader stores downloaded images under Dir and serves them from
+// there afterwards, concurrent requests for the same URL share a single
+// download.
+type ImageDownloader struct {
+	Dir    string
+	Client *http.Client
+
+	mu       sync.Mutex
+	inflight map[string]*imageDownload
+}
+
+func NewImageDownloader(dir string, client *http.Client) *ImageDownloader {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	return &ImageDownloader{
+		Dir:      dir,
+		Client:   client,
+		inflight: make(map[string]*imageDownload),
+	}
+}
+
+// Path returns where url is (or would be) cached on disk.
+func (d *ImageDownloader) Path(url string) string {
+	sum := sha1.Sum([]byte(url))
+	return filepath.Join(d.Dir, hex.EncodeToString(sum[:]))
+}
+
+// Fetch returns the path of the cached copy of url, downloading it first
+// if needed.
+func (d *ImageDownloader) Fetch(url string) (string, error) {
+	path := d.Path(url)
+	if _, err := os.Stat(path); err == nil {
+		return path, nil
+	}
+
+	d.mu.Lock()
+	if dl, ok := d.inflight[url]; ok {
+		d.mu.Unlock()
+		dl.wg.Wait()
+		return dl.path, dl.err
+	}
+
+	dl := &imageDownload{path: path}
+	dl.wg.Add(1)
+	d.inflight[url] = dl
+	d.mu.Unlock()
+
+	dl.err = d.download(url, path)
+	dl.wg.Done()
+
+	d.mu.Lock()
+	delete(d.inflight, url)
+	d.mu.Unlock()
+
+	return dl.path, dl.err
+}
+
+func (d *ImageDownloader) download(url, path string) error {
+	resp, err := d.Client.Get(url)
+	if resp != nil {
+		defer func(Body io.ReadCloser) {
+			err := Body.Close()
+			if err != nil {
+				return
+			}
+		}(resp.Body)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("http error: %d", resp.StatusCode)
+	}
+
+	if err = os.MkdirAll(d.Dir, 0o755); err != nil {
+		return err
+	}
+
+	// Write to a temporary file first so a failed download never leaves a
+	// truncated image behind.
+	tmp, err := os.CreateTemp(d.Dir, ".download-*")
+	if err != nil {
+		return err
+	}
+
+	if _, err = io.Copy(tmp, resp.Body); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	if err = tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
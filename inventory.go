@@ -5,13 +5,16 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"net/http"
 	"net/url"
 	"regexp"
 	"strconv"
 )
 
 const (
-	InventoryEndpoint = "http://steamcommunity.com/inventory/%d/%d/%d?"
+	InventoryEndpoint = CommunityBaseUrl + inventoryPath
+
+	inventoryPath = "/inventory/%d/%d/%d?"
 )
 
 type ItemTag struct {
@@ -29,7 +32,7 @@ type InventoryItem struct {
 	AssetID    uint64        `json:"id,string,omitempty"`
 	ClassID    uint64        `json:"classid,string,omitempty"`
 	InstanceID uint64        `json:"instanceid,string,omitempty"`
-	Amount     string        `json:"amount,string"`
+	Amount     string        `json:"amount"`
 	Desc       *EconItemDesc `json:"-"` /* May be nil  */
 }
 
@@ -52,11 +55,80 @@ type InventoryAppStats struct {
 
 var inventoryContextRegexp = regexp.MustCompile("var g_rgAppContextData = (.*?);")
 
+// Inventory is a single app/context inventory with every asset joined to
+// its description.
+type Inventory struct {
+	SteamID    SteamID
+	AppID      uint64
+	ContextID  uint64
+	Items      []InventoryItem
+	TotalCount int // total_inventory_count as reported by Steam, ignores filters
+
+	// The validators Steam sent with an inventory fetched in one page,
+	// InventoryCache revalidates it with them once expired.
+	ETag         string
+	LastModified string
+}
+
+type inventoryAsset struct {
+	AppID      uint32 `json:"appid"`
+	ContextID  uint64 `json:"contextid,string"`
+	AssetID    uint64 `json:"assetid,string"`
+	ClassID    uint64 `json:"classid,string"`
+	InstanceID uint64 `json:"instanceid,string"`
+	Amount     string `json:"amount"`
+}
+
+func descriptionKey(classID, instanceID uint64) string {
+	return strconv.FormatUint(classID, 10) + "_" + strconv.FormatUint(instanceID, 10)
+}
+
+// joinDescriptions matches every asset with its description by
+// classid/instanceid and appends the ones passing filters to items.
+func joinDescriptions(assets []inventoryAsset, descs []*EconItemDesc, filters []Filter, items *[]InventoryItem) {
+	descriptions := make(map[string]*EconItemDesc, len(descs))
+	for _, desc := range descs {
+		descriptions[descriptionKey(desc.ClassID, desc.InstanceID)] = desc
+	}
+
+	for _, asset := range assets {
+		item := InventoryItem{
+			AppID:      asset.AppID,
+			ContextID:  asset.ContextID,
+			AssetID:    asset.AssetID,
+			ClassID:    asset.ClassID,
+			InstanceID: asset.InstanceID,
+			Amount:     asset.Amount,
+			Desc:       descriptions[descriptionKey(asset.ClassID, asset.InstanceID)],
+		}
+
+		if matchFilters(&item, filters) {
+			*items = append(*items, item)
+		}
+	}
+}
+
+func matchFilters(item *InventoryItem, filters []Filter) bool {
+	for _, filter := range filters {
+		if !filter(item) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// errNotModified is the 304 answer to a revalidation.
+var errNotModified = errors.New("inventory not modified")
+
+// fetchInventory reads a page of the inventory.  The first page is asked
+// for conditionally when cached has validators, errNotModified is returned
+// when it did not change.
 func (session *Session) fetchInventory(
-	sid SteamID,
-	appID, contextID, startAssetID uint64,
+	inventory *Inventory,
+	startAssetID uint64,
 	filters []Filter,
-	items *[]InventoryItem,
+	cached *Inventory,
 ) (hasMore bool, lastAssetID uint64, err error) {
 	params := url.Values{
 		"l": {session.language},
@@ -69,7 +141,21 @@ func (session *Session) fetchInventory(
 		params.Set("count", "250")
 	}
 
-	resp, err := session.client.Get(fmt.Sprintf(InventoryEndpoint, sid, appID, contextID) + params.Encode())
+	req, err := http.NewRequest(http.MethodGet, session.CommunityBaseURL()+fmt.Sprintf(inventoryPath, inventory.SteamID, inventory.AppID, inventory.ContextID)+params.Encode(), nil)
+	if err != nil {
+		return false, 0, err
+	}
+
+	if startAssetID == 0 && cached != nil {
+		if len(cached.ETag) != 0 {
+			req.Header.Set("If-None-Match", cached.ETag)
+		}
+		if len(cached.LastModified) != 0 {
+			req.Header.Set("If-Modified-Since", cached.LastModified)
+		}
+	}
+
+	resp, err := session.client.Do(req)
 	if resp != nil {
 		defer func(Body io.ReadCloser) {
 			err := Body.Close()
@@ -83,30 +169,37 @@ func (session *Session) fetchInventory(
 		return false, 0, err
 	}
 	if resp == nil {
-		return false, 0, errors.New("invalid response")
+		return false, 0, ErrInvalidResponse
 	}
-	type Asset struct {
-		AppID      uint32 `json:"appid"`
-		ContextID  uint64 `json:"contextid,string"`
-		AssetID    uint64 `json:"assetid,string"`
-		ClassID    uint64 `json:"classid,string"`
-		InstanceID uint64 `json:"instanceid,string"`
-		Amount     string `json:"amount"`
+
+	if resp.StatusCode == http.StatusNotModified {
+		return false, 0, errNotModified
 	}
 
+	if startAssetID == 0 {
+		inventory.ETag = resp.Header.Get("ETag")
+		inventory.LastModified = resp.Header.Get("Last-Modified")
+	}
+
+	return decodeInventoryPage(session.limitBody(resp), inventory, filters)
+}
+
+// decodeInventoryPage reads one page of the inventory endpoint into
+// inventory and returns where the next page starts.
+func decodeInventoryPage(r io.Reader, inventory *Inventory, filters []Filter) (hasMore bool, lastAssetID uint64, err error) {
 	type Response struct {
-		Assets              []Asset         `json:"assets"`
-		Descriptions        []*EconItemDesc `json:"descriptions"`
-		Success             int             `json:"success"`
-		Rwgrsn              int             `json:"rwgrsn"`
-		TotalInventoryCount int             `json:"total_inventory_count"`
-		HasMore             int             `json:"more_items,omitempty"`
-		LastAssetID         string          `json:"last_assetid,omitempty"`
-		ErrorMsg            string          `json:"error,omitempty"`
+		Assets              []inventoryAsset `json:"assets"`
+		Descriptions        []*EconItemDesc  `json:"descriptions"`
+		Success             int              `json:"success"`
+		Rwgrsn              int              `json:"rwgrsn"`
+		TotalInventoryCount int              `json:"total_inventory_count"`
+		HasMore             int              `json:"more_items,omitempty"`
+		LastAssetID         string           `json:"last_assetid,omitempty"`
+		ErrorMsg            string           `json:"error,omitempty"`
 	}
 
 	var response Response
-	if err = json.NewDecoder(resp.Body).Decode(&response); err != nil {
+	if err = json.NewDecoder(r).Decode(&response); err != nil {
 		return false, 0, err
 	}
 
@@ -117,49 +210,10 @@ func (session *Session) fetchInventory(
 
 		return false, 0, nil // empty inventory
 	}
-	// Fill in descriptions map, where key
-	// is "<CLASS_ID>_<INSTANCE_ID>" pattern, and
-	// value is position on asset description in
-	// response.Descriptions array
-	//
-	// We need it for fast asset's description
-	// searching in future
-	descriptions := make(map[string]int)
-	for i, desc := range response.Descriptions {
-		key := fmt.Sprintf("%s_%s", desc.ClassID, desc.InstanceID)
-		descriptions[key] = i
-	}
-
-	for _, asset := range response.Assets {
-		var desc *EconItemDesc
-
-		key := fmt.Sprintf("%s_%s", asset.ClassID, asset.InstanceID)
-		if d, ok := descriptions[key]; ok {
-			desc = response.Descriptions[d]
-		}
-
-		item := InventoryItem{
-			AppID:      asset.AppID,
-			ContextID:  asset.ContextID,
-			AssetID:    asset.AssetID,
-			ClassID:    asset.ClassID,
-			InstanceID: asset.InstanceID,
-			Amount:     asset.Amount,
-			Desc:       desc,
-		}
 
-		add := true
-		for _, filter := range filters {
-			add = filter(&item)
-			if !add {
-				break
-			}
-		}
+	inventory.TotalCount = response.TotalInventoryCount
+	joinDescriptions(response.Assets, response.Descriptions, filters, &inventory.Items)
 
-		if add {
-			*items = append(*items, item)
-		}
-	}
 	hasMore = response.HasMore != 0
 	if !hasMore {
 		return hasMore, 0, nil
@@ -182,11 +236,40 @@ func (session *Session) GetInventory(sid SteamID, appID, contextID uint64, trada
 }
 
 func (session *Session) GetFilterableInventory(sid SteamID, appID, contextID uint64, filters []Filter) ([]InventoryItem, error) {
-	var items []InventoryItem
+	inventory, err := session.GetInventoryContents(sid, appID, contextID, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	return inventory.Items, nil
+}
+
+// GetInventoryContents downloads every page of the inventory, only items
+// passing filters are kept.
+func (session *Session) GetInventoryContents(sid SteamID, appID, contextID uint64, filters []Filter) (*Inventory, error) {
+	if session.inventoryCache != nil {
+		return session.cachedInventory(sid, appID, contextID, filters)
+	}
+
+	return session.downloadInventory(sid, appID, contextID, filters, nil)
+}
+
+// downloadInventory fetches every page of the inventory.  When cached is
+// given the download is conditional and cached itself returned if Steam
+// answers that it did not change.
+func (session *Session) downloadInventory(sid SteamID, appID, contextID uint64, filters []Filter, cached *Inventory) (*Inventory, error) {
+	inventory := &Inventory{
+		SteamID:   sid,
+		AppID:     appID,
+		ContextID: contextID,
+	}
 	startAssetID := uint64(0)
 
 	for {
-		hasMore, lastAssetID, err := session.fetchInventory(sid, appID, contextID, startAssetID, filters, &items)
+		hasMore, lastAssetID, err := session.fetchInventory(inventory, startAssetID, filters, cached)
+		if err == errNotModified {
+			return cached, nil
+		}
 		if err != nil {
 			return nil, err
 		}
@@ -195,14 +278,42 @@ func (session *Session) GetFilterableInventory(sid SteamID, appID, contextID uin
 			break
 		}
 
+		// The validators only cover the first page.
+		inventory.ETag, inventory.LastModified = "", ""
+
 		startAssetID = lastAssetID
 	}
 
-	return items, nil
+	return inventory, nil
+}
+
+// Filter returns the items matching every filter.
+func (inventory *Inventory) Filter(filters ...Filter) []InventoryItem {
+	items := []InventoryItem{}
+	for i := range inventory.Items {
+		if matchFilters(&inventory.Items[i], filters) {
+			items = append(items, inventory.Items[i])
+		}
+	}
+
+	return items
+}
+
+// Count returns the amount of items matching every filter, with no filters
+// it is the amount of items held by the Inventory.
+func (inventory *Inventory) Count(filters ...Filter) int {
+	count := 0
+	for i := range inventory.Items {
+		if matchFilters(&inventory.Items[i], filters) {
+			count++
+		}
+	}
+
+	return count
 }
 
 func (session *Session) GetInventoryAppStats(sid SteamID) (map[string]InventoryAppStats, error) {
-	resp, err := session.client.Get("https://steamcommunity.com/profiles/" + sid.ToString() + "/inventory")
+	resp, err := session.client.Get(session.CommunityBaseURL() + "/profiles/" + sid.ToString() + "/inventory")
 	if resp != nil {
 		defer func(Body io.ReadCloser) {
 			err := Body.Close()
@@ -216,14 +327,14 @@ func (session *Session) GetInventoryAppStats(sid SteamID) (map[string]InventoryA
 		return nil, err
 	}
 
-	body, err := io.ReadAll(resp.Body)
+	body, err := io.ReadAll(session.limitBody(resp))
 	if err != nil {
 		return nil, err
 	}
 
 	m := inventoryContextRegexp.FindSubmatch(body)
 	if m == nil || len(m) != 2 {
-		return nil, err
+		return nil, ErrInvalidResponse
 	}
 
 	inven := map[string]InventoryAppStats{}
@@ -0,0 +1,191 @@
+package steam
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+const apiGetInventoryItemsWithDescriptions = "IEconService/GetInventoryItemsWithDescriptions"
+
+// flexNumber decodes the numbers the protobuf backed WebAPI endpoints
+// return, which come as numbers, strings or booleans depending on the
+// field.
+type flexNumber uint64
+
+func (n *flexNumber) UnmarshalJSON(b []byte) error {
+	b = bytes.Trim(b, `"`)
+	switch string(b) {
+	case "", "null", "false":
+		*n = 0
+		return nil
+	case "true":
+		*n = 1
+		return nil
+	}
+
+	v, err := strconv.ParseUint(string(b), 10, 64)
+	if err != nil {
+		return err
+	}
+
+	*n = flexNumber(v)
+	return nil
+}
+
+type apiInventoryAsset struct {
+	AppID      flexNumber `json:"appid"`
+	ContextID  flexNumber `json:"contextid"`
+	AssetID    flexNumber `json:"assetid"`
+	ClassID    flexNumber `json:"classid"`
+	InstanceID flexNumber `json:"instanceid"`
+	Amount     flexNumber `json:"amount"`
+}
+
+type apiInventoryDescription struct {
+	ClassID                     flexNumber    `json:"classid"`
+	InstanceID                  flexNumber    `json:"instanceid"`
+	Currency                    flexNumber    `json:"currency"`
+	BackgroundColor             string        `json:"background_color"`
+	IconURL                     string        `json:"icon_url"`
+	IconLargeURL                string        `json:"icon_url_large"`
+	Tradable                    flexNumber    `json:"tradable"`
+	Name                        string        `json:"name"`
+	NameColor                   string        `json:"name_color"`
+	Type                        string        `json:"type"`
+	MarketName                  string        `json:"market_name"`
+	MarketHashName              string        `json:"market_hash_name"`
+	Commodity                   flexNumber    `json:"commodity"`
+	MarketTradableRestriction   flexNumber    `json:"market_tradable_restriction"`
+	MarketMarketableRestriction flexNumber    `json:"market_marketable_restriction"`
+	Marketable                  flexNumber    `json:"marketable"`
+	Actions                     []*EconAction `json:"actions"`
+	Tags                        []*EconTag    `json:"tags"`
+	Descriptions                []*EconDesc   `json:"descriptions"`
+}
+
+func (d *apiInventoryDescription) desc() *EconItemDesc {
+	return &EconItemDesc{
+		ClassID:                     uint64(d.ClassID),
+		InstanceID:                  uint64(d.InstanceID),
+		Currency:                    int(d.Currency),
+		BackgroundColor:             d.BackgroundColor,
+		IconURL:                     d.IconURL,
+		IconLargeURL:                d.IconLargeURL,
+		Tradable:                    int(d.Tradable),
+		Name:                        d.Name,
+		NameColor:                   d.NameColor,
+		Type:                        d.Type,
+		MarketName:                  d.MarketName,
+		MarketHashName:              d.MarketHashName,
+		Commodity:                   int(d.Commodity),
+		MarketTradableRestriction:   int(d.MarketTradableRestriction),
+		MarketMarketableRestriction: int(d.MarketMarketableRestriction),
+		Marketable:                  int(d.Marketable),
+		Actions:                     d.Actions,
+		Tags:                        d.Tags,
+		Descriptions:                d.Descriptions,
+	}
+}
+
+func (session *Session) fetchInventoryViaAPI(inventory *Inventory, startAssetID uint64, filters []Filter) (hasMore bool, lastAssetID uint64, err error) {
+	params := session.authorize(url.Values{
+		"steamid":          {inventory.SteamID.ToString()},
+		"appid":            {strconv.FormatUint(inventory.AppID, 10)},
+		"contextid":        {strconv.FormatUint(inventory.ContextID, 10)},
+		"get_descriptions": {"true"},
+		"count":            {"2000"},
+	})
+	if len(session.language) != 0 {
+		params.Set("language", session.language)
+	}
+	if startAssetID != 0 {
+		params.Set("start_assetid", strconv.FormatUint(startAssetID, 10))
+	}
+
+	resp, err := session.client.Get(session.apiURL(apiGetInventoryItemsWithDescriptions) + "?" + params.Encode())
+	if resp != nil {
+		defer func(Body io.ReadCloser) {
+			err := Body.Close()
+			if err != nil {
+				return
+			}
+		}(resp.Body)
+	}
+
+	if err != nil {
+		return false, 0, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return false, 0, fmt.Errorf("http error: %d", resp.StatusCode)
+	}
+
+	type Response struct {
+		Inner *struct {
+			Assets              []*apiInventoryAsset       `json:"assets"`
+			Descriptions        []*apiInventoryDescription `json:"descriptions"`
+			TotalInventoryCount int                        `json:"total_inventory_count"`
+			MoreItems           flexNumber                 `json:"more_items"`
+			LastAssetID         flexNumber                 `json:"last_assetid"`
+		} `json:"response"`
+	}
+
+	var response Response
+	if err = session.readJSON(resp, &response); err != nil {
+		return false, 0, err
+	}
+
+	if response.Inner == nil {
+		return false, 0, ErrInvalidResponse
+	}
+
+	assets := make([]inventoryAsset, 0, len(response.Inner.Assets))
+	for _, asset := range response.Inner.Assets {
+		assets = append(assets, inventoryAsset{
+			AppID:      uint32(asset.AppID),
+			ContextID:  uint64(asset.ContextID),
+			AssetID:    uint64(asset.AssetID),
+			ClassID:    uint64(asset.ClassID),
+			InstanceID: uint64(asset.InstanceID),
+			Amount:     strconv.FormatUint(uint64(asset.Amount), 10),
+		})
+	}
+
+	descs := make([]*EconItemDesc, 0, len(response.Inner.Descriptions))
+	for _, desc := range response.Inner.Descriptions {
+		descs = append(descs, desc.desc())
+	}
+
+	inventory.TotalCount = response.Inner.TotalInventoryCount
+	joinDescriptions(assets, descs, filters, &inventory.Items)
+
+	return response.Inner.MoreItems != 0, uint64(response.Inner.LastAssetID), nil
+}
+
+// GetInventoryViaAPI is GetInventoryContents through the
+// IEconService/GetInventoryItemsWithDescriptions WebAPI instead of the
+// community inventory, which is far less rate limited.  startAssetID
+// resumes from a previous listing, 0 starts from the beginning.
+func (session *Session) GetInventoryViaAPI(sid SteamID, appID, contextID, startAssetID uint64, filters []Filter) (*Inventory, error) {
+	inventory := &Inventory{
+		SteamID:   sid,
+		AppID:     appID,
+		ContextID: contextID,
+	}
+
+	for {
+		hasMore, lastAssetID, err := session.fetchInventoryViaAPI(inventory, startAssetID, filters)
+		if err != nil {
+			return nil, err
+		}
+
+		if !hasMore || lastAssetID == 0 {
+			return inventory, nil
+		}
+
+		startAssetID = lastAssetID
+	}
+}
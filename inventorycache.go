@@ -0,0 +1,125 @@
+package steam
+
+import (
+	"sync"
+	"time"
+)
+
+type InventoryKey struct {
+	SteamID   SteamID
+	AppID     uint64
+	ContextID uint64
+}
+
+// InventoryCache stores unfiltered inventories, implementations must be
+// safe for concurrent use.
+type InventoryCache interface {
+	Get(key InventoryKey) (*Inventory, bool)
+	Set(key InventoryKey, inventory *Inventory)
+	// Invalidate drops every cached inventory of sid.
+	Invalidate(sid SteamID)
+}
+
+// RevalidatingInventoryCache is an InventoryCache keeping expired
+// inventories, which are then downloaded again with their ETag and
+// Last-Modified validators and reused when Steam answers 304 Not Modified.
+type RevalidatingInventoryCache interface {
+	InventoryCache
+	// Stale returns the inventory of key, also when it expired.
+	Stale(key InventoryKey) (*Inventory, bool)
+}
+
+type inventoryCacheEntry struct {
+	inventory *Inventory
+	expires   time.Time
+}
+
+// MemoryInventoryCache is the default InventoryCache, entries live for ttl
+// and are revalidated after that, see RevalidatingInventoryCache.  Expired
+// entries are kept until they are replaced or invalidated.
+type MemoryInventoryCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[InventoryKey]inventoryCacheEntry
+}
+
+func NewMemoryInventoryCache(ttl time.Duration) *MemoryInventoryCache {
+	return &MemoryInventoryCache{
+		ttl:     ttl,
+		entries: make(map[InventoryKey]inventoryCacheEntry),
+	}
+}
+
+func (c *MemoryInventoryCache) Get(key InventoryKey) (*Inventory, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok || time.Now().After(entry.expires) {
+		return nil, false
+	}
+
+	return entry.inventory, true
+}
+
+func (c *MemoryInventoryCache) Stale(key InventoryKey) (*Inventory, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	return entry.inventory, ok
+}
+
+func (c *MemoryInventoryCache) Set(key InventoryKey, inventory *Inventory) {
+	c.mu.Lock()
+	c.entries[key] = inventoryCacheEntry{inventory: inventory, expires: time.Now().Add(c.ttl)}
+	c.mu.Unlock()
+}
+
+func (c *MemoryInventoryCache) Invalidate(sid SteamID) {
+	c.mu.Lock()
+	for key := range c.entries {
+		if key.SteamID == sid {
+			delete(c.entries, key)
+		}
+	}
+	c.mu.Unlock()
+}
+
+// SetInventoryCache enables caching of inventories fetched through the
+// session, nil disables it.  Our own inventory is invalidated whenever a
+// trade is accepted or confirmed.
+func (session *Session) SetInventoryCache(cache InventoryCache) {
+	session.inventoryCache = cache
+}
+
+// InvalidateInventory drops the cached inventories of sid, if any.
+func (session *Session) InvalidateInventory(sid SteamID) {
+	if session.inventoryCache != nil {
+		session.inventoryCache.Invalidate(sid)
+	}
+}
+
+func (session *Session) cachedInventory(sid SteamID, appID, contextID uint64, filters []Filter) (*Inventory, error) {
+	key := InventoryKey{SteamID: sid, AppID: appID, ContextID: contextID}
+
+	inventory, ok := session.inventoryCache.Get(key)
+	if !ok {
+		var stale *Inventory
+		if cache, ok := session.inventoryCache.(RevalidatingInventoryCache); ok {
+			stale, _ = cache.Stale(key)
+		}
+
+		// A 304 hands back stale, which is cached for another ttl.
+		var err error
+		if inventory, err = session.downloadInventory(sid, appID, contextID, nil, stale); err != nil {
+			return nil, err
+		}
+
+		session.inventoryCache.Set(key, inventory)
+	}
+
+	filtered := *inventory
+	filtered.Items = inventory.Filter(filters...)
+	return &filtered, nil
+}
@@ -0,0 +1,51 @@
+package steam
+
+import (
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestInventoryCacheRevalidates(t *testing.T) {
+	var (
+		mu         sync.Mutex
+		downloads  int
+		notChanged int
+	)
+	session := newTestSession(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notChanged++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		downloads++
+		w.Header().Set("ETag", `"v1"`)
+		answer(`{"success":1,"total_inventory_count":1,
+			"assets":[{"appid":730,"contextid":"2","assetid":"10","classid":"20","instanceid":"0","amount":"1"}]}`).ServeHTTP(w, r)
+	}))
+
+	// Expired as soon as stored, every call revalidates.
+	session.SetInventoryCache(NewMemoryInventoryCache(-time.Second))
+
+	for i := 0; i < 3; i++ {
+		items, err := session.GetInventory(testSteamID, 730, 2, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(items) != 1 || items[0].AssetID != 10 {
+			t.Fatalf("call %d got %+v, want the cached item", i, items)
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if downloads != 1 || notChanged != 2 {
+		t.Errorf("got %d downloads and %d revalidations, want 1 and 2", downloads, notChanged)
+	}
+}
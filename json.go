@@ -0,0 +1,18 @@
+package steam
+
+import (
+	"encoding/json"
+	"io"
+)
+
+/* Steam sends 64-bit IDs (assets, offers, trades, SteamIDs...) as strings,
+ * decoding them into uint64 with the ",string" option or into string
+ * fields keeps them exact.  Responses that have to go through interface{}
+ * are decoded with decodeJSON so numbers stay json.Number instead of
+ * float64, which only holds integers up to 2^53.  */
+
+func decodeJSON(r io.Reader, v interface{}) error {
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+	return dec.Decode(v)
+}
@@ -0,0 +1,114 @@
+package steam
+
+import (
+	"strconv"
+	"testing"
+	"time"
+)
+
+// bigID is 2^53+1, the first integer a float64 cannot hold.
+const (
+	bigID       uint64 = 9007199254740993
+	bigIDString        = "9007199254740993"
+)
+
+func TestLargeIDsKeepPrecision(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		get  func(*Session) (got, want interface{}, err error)
+	}{
+		{
+			name: "trade offer id",
+			body: `{"response":{"trade_offers_sent":[{"tradeofferid":"` + bigIDString + `","tradeid":"` + bigIDString + `"}]}}`,
+			get: func(s *Session) (interface{}, interface{}, error) {
+				resp, err := s.GetTradeOffers(TradeFilterSentOffers, time.Time{})
+				if err != nil || len(resp.SentOffers) != 1 {
+					return nil, bigID, err
+				}
+				return resp.SentOffers[0].ID, bigID, nil
+			},
+		},
+		{
+			name: "trade receipt id",
+			body: `{"response":{"trade_offers_sent":[{"tradeofferid":"1","tradeid":"` + bigIDString + `"}]}}`,
+			get: func(s *Session) (interface{}, interface{}, error) {
+				resp, err := s.GetTradeOffers(TradeFilterSentOffers, time.Time{})
+				if err != nil || len(resp.SentOffers) != 1 {
+					return nil, bigID, err
+				}
+				return resp.SentOffers[0].ReceiptID, bigID, nil
+			},
+		},
+		{
+			name: "trade offer asset id",
+			body: `{"response":{"trade_offers_sent":[{"tradeofferid":"1","items_to_give":[{"assetid":"` + bigIDString + `","classid":"` + bigIDString + `","instanceid":"0","appid":730,"contextid":"2","amount":"1"}]}]}}`,
+			get: func(s *Session) (interface{}, interface{}, error) {
+				resp, err := s.GetTradeOffers(TradeFilterSentOffers, time.Time{})
+				if err != nil || len(resp.SentOffers) != 1 || len(resp.SentOffers[0].SendItems) != 1 {
+					return nil, bigIDString, err
+				}
+				item := resp.SentOffers[0].SendItems[0]
+				return item.AssetID + "/" + item.ClassID, bigIDString + "/" + bigIDString, nil
+			},
+		},
+		{
+			name: "inventory asset id",
+			body: `{"success":1,"total_inventory_count":1,"assets":[{"appid":730,"contextid":"2","assetid":"` + bigIDString + `","classid":"` + bigIDString + `","instanceid":"0","amount":"1"}]}`,
+			get: func(s *Session) (interface{}, interface{}, error) {
+				items, err := s.GetInventory(testSteamID, 730, 2, false)
+				if err != nil || len(items) != 1 {
+					return nil, bigID, err
+				}
+				return [2]uint64{items[0].AssetID, items[0].ClassID}, [2]uint64{bigID, bigID}, nil
+			},
+		},
+		{
+			name: "market listing id",
+			body: `{"success":true,"total_count":1,"listings":[{"listingid":"` + bigIDString + `","asset":{"appid":730,"contextid":"2","id":"` + bigIDString + `","amount":"1"}}]}`,
+			get: func(s *Session) (interface{}, interface{}, error) {
+				listings, err := s.GetMyMarketListings()
+				if err != nil || len(listings) != 1 {
+					return nil, bigID, err
+				}
+				return [2]uint64{listings[0].ID, listings[0].AssetID}, [2]uint64{bigID, bigID}, nil
+			},
+		},
+		{
+			name: "buy order purchase",
+			body: `{"success":1,"active":0,"purchased":1,"quantity":"1","quantity_remaining":"0","purchases":[{"appid":730,"contextid":"2","assetid":"` + bigIDString + `","listingid":"` + bigIDString + `"}]}`,
+			get: func(s *Session) (interface{}, interface{}, error) {
+				status, err := s.GetBuyOrderStatus(1)
+				if err != nil || len(status.Purchases) != 1 {
+					return nil, bigID, err
+				}
+				p := status.Purchases[0]
+				return p.ListingID + "/" + strconv.FormatUint(p.AssetID, 10), bigIDString + "/" + bigIDString, nil
+			},
+		},
+		{
+			name: "histogram quantity",
+			body: `{"success":1,"highest_buy_order":"` + bigIDString + `","lowest_sell_order":"1","buy_order_graph":[[1.5,` + bigIDString + `,"x"]],"sell_order_graph":[]}`,
+			get: func(s *Session) (interface{}, interface{}, error) {
+				h, err := s.GetItemOrdersHistogram(1, CurrencyUSD)
+				if err != nil || len(h.BuyOrders) != 1 {
+					return nil, bigID, err
+				}
+				return [2]uint64{h.HighestBuyOrder, h.BuyOrders[0].Quantity}, [2]uint64{bigID, bigID}, nil
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session := newTestSession(t, answer(tt.body))
+			got, want, err := tt.get(session)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != want {
+				t.Errorf("got %v, want %v", got, want)
+			}
+		})
+	}
+}
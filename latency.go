@@ -0,0 +1,147 @@
+package steam
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxLatencySamples bounds the memory used per endpoint, the oldest samples
+// are dropped first.
+const maxLatencySamples = 2048
+
+// EndpointStats summarizes the requests made to one endpoint during the
+// latency window.
+type EndpointStats struct {
+	Count      int           `json:"count"`
+	ErrorRatio float64       `json:"error_ratio"`
+	P50        time.Duration `json:"p50"`
+	P95        time.Duration `json:"p95"`
+	P99        time.Duration `json:"p99"`
+}
+
+type latencySample struct {
+	at      time.Time
+	latency time.Duration
+	failed  bool
+}
+
+type latencyTransport struct {
+	session *Session
+	window  time.Duration
+	next    http.RoundTripper
+
+	mu      sync.Mutex
+	samples map[string][]latencySample
+}
+
+// endpointName names the endpoint of a request: interface/method for the
+// Web API, the first path element elsewhere.
+func endpointName(req *http.Request) string {
+	parts := strings.SplitN(strings.Trim(req.URL.Path, "/"), "/", 3)
+	if req.URL.Hostname() == "api.steampowered.com" && len(parts) >= 2 {
+		return parts[0] + "/" + parts[1]
+	}
+
+	return req.URL.Hostname() + "/" + parts[0]
+}
+
+func (t *latencyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+
+	sample := latencySample{
+		at:      start,
+		latency: time.Since(start),
+		failed:  err != nil || resp.StatusCode >= 500,
+	}
+
+	name := endpointName(t.session.withDefaultHost(req))
+	t.mu.Lock()
+	samples := append(t.prune(t.samples[name], start), sample)
+	if len(samples) > maxLatencySamples {
+		samples = samples[len(samples)-maxLatencySamples:]
+	}
+	t.samples[name] = samples
+	t.mu.Unlock()
+
+	return resp, err
+}
+
+func (t *latencyTransport) prune(samples []latencySample, now time.Time) []latencySample {
+	i := 0
+	for i < len(samples) && now.Sub(samples[i].at) > t.window {
+		i++
+	}
+
+	return samples[i:]
+}
+
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+
+	return sorted[int(float64(len(sorted)-1)*p)]
+}
+
+func (t *latencyTransport) stats() map[string]EndpointStats {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	stats := make(map[string]EndpointStats, len(t.samples))
+	for name, samples := range t.samples {
+		samples = t.prune(samples, now)
+		t.samples[name] = samples
+		if len(samples) == 0 {
+			continue
+		}
+
+		latencies := make([]time.Duration, len(samples))
+		failed := 0
+		for i, sample := range samples {
+			latencies[i] = sample.latency
+			if sample.failed {
+				failed++
+			}
+		}
+		sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+
+		stats[name] = EndpointStats{
+			Count:      len(samples),
+			ErrorRatio: float64(failed) / float64(len(samples)),
+			P50:        percentile(latencies, 0.50),
+			P95:        percentile(latencies, 0.95),
+			P99:        percentile(latencies, 0.99),
+		}
+	}
+
+	return stats
+}
+
+// EnableLatencyTracking records the latency and outcome of every request
+// made through the session client, per endpoint, over a sliding window.
+// The numbers show up in Stats.
+func (session *Session) EnableLatencyTracking(window time.Duration) {
+	next := session.client.Transport
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	t := &latencyTransport{session: session, window: window, next: next, samples: make(map[string][]latencySample)}
+	session.client.Transport = t
+	session.latency = t
+}
+
+// EndpointStats returns the latency percentiles and error ratio of every
+// endpoint used during the latency window.
+func (session *Session) EndpointStats() map[string]EndpointStats {
+	if session.latency == nil {
+		return map[string]EndpointStats{}
+	}
+
+	return session.latency.stats()
+}
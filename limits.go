@@ -0,0 +1,192 @@
+package steam
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// DefaultMaxBodySize is the response size SetResponseLimits allows when
+// ResponseLimits.MaxBodySize is 0, inventories with descriptions are the
+// largest legitimate answers and stay well below it.
+const DefaultMaxBodySize = 32 << 20
+
+var (
+	ErrResponseTooLarge      = errors.New("response body exceeds the size limit")
+	ErrUnexpectedContentType = errors.New("unexpected response content type")
+)
+
+// ResponseError is returned when a response fails the checks enabled with
+// SetResponseLimits, it matches ErrResponseTooLarge or
+// ErrUnexpectedContentType with errors.Is.
+type ResponseError struct {
+	URL         string // without its query, which may carry the API key
+	StatusCode  int
+	ContentType string
+	Err         error
+}
+
+func (e *ResponseError) Error() string {
+	return fmt.Sprintf("%s (%d, %s): %v", e.URL, e.StatusCode, e.ContentType, e.Err)
+}
+
+func (e *ResponseError) Unwrap() error {
+	return e.Err
+}
+
+// ResponseLimits configures the checks SetResponseLimits applies to every
+// response.
+type ResponseLimits struct {
+	MaxBodySize int64 // bytes, DefaultMaxBodySize when 0
+
+	// CheckContentType fails WebAPI responses, and responses to requests
+	// accepting only JSON, that are HTML pages instead, before their body
+	// is handed to a JSON decoder.
+	CheckContentType bool
+}
+
+type limitTransport struct {
+	session *Session
+	limits  ResponseLimits
+	next    http.RoundTripper
+}
+
+// limitedBody fails the read that goes past the limit instead of
+// truncating the body silently like io.LimitReader.
+type limitedBody struct {
+	io.ReadCloser
+	remaining int64
+	err       *ResponseError
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if b.remaining <= 0 {
+		// Anything left beyond the limit is an error, a body of exactly
+		// the limit is not.
+		var one [1]byte
+		if n, _ := b.ReadCloser.Read(one[:]); n == 0 {
+			return 0, io.EOF
+		}
+
+		return 0, b.err
+	}
+
+	if int64(len(p)) > b.remaining {
+		p = p[:b.remaining]
+	}
+
+	n, err := b.ReadCloser.Read(p)
+	b.remaining -= int64(n)
+	return n, err
+}
+
+// expectsJSON reports whether the response to req can only be JSON.
+func expectsJSON(req *http.Request) bool {
+	if req.URL.Hostname() == DefaultAPIHost {
+		return true
+	}
+
+	accept := req.Header.Get("Accept")
+	return strings.HasPrefix(accept, "application/json") && !strings.Contains(accept, ",")
+}
+
+func (t *limitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+
+	contentType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	u := *req.URL
+	u.RawQuery = ""
+	newError := func(err error) *ResponseError {
+		return &ResponseError{
+			URL:         u.Redacted(),
+			StatusCode:  resp.StatusCode,
+			ContentType: contentType,
+			Err:         err,
+		}
+	}
+
+	if t.limits.CheckContentType && contentType == "text/html" && expectsJSON(t.session.withDefaultHost(req)) {
+		resp.Body.Close()
+		return nil, newError(ErrUnexpectedContentType)
+	}
+
+	if resp.ContentLength > t.limits.MaxBodySize {
+		resp.Body.Close()
+		return nil, newError(ErrResponseTooLarge)
+	}
+
+	resp.Body = &limitedBody{
+		ReadCloser: resp.Body,
+		remaining:  t.limits.MaxBodySize,
+		err:        newError(ErrResponseTooLarge),
+	}
+	return resp, nil
+}
+
+// SetResponseLimits makes every read of a response of the session fail
+// with a ResponseError past limits.MaxBodySize, and optionally rejects HTML
+// error pages where JSON is expected, so long running bots get a clear
+// error rather than a runaway read or a confusing decode failure.
+func (session *Session) SetResponseLimits(limits ResponseLimits) {
+	if limits.MaxBodySize == 0 {
+		limits.MaxBodySize = DefaultMaxBodySize
+	}
+
+	if t := session.limits; t != nil {
+		t.limits = limits
+		return
+	}
+
+	next := session.client.Transport
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	session.limits = &limitTransport{session: session, limits: limits, next: next}
+	session.client.Transport = session.limits
+}
+
+// limitBody returns the body of resp capped at DefaultMaxBodySize, unless
+// the transport of SetResponseLimits already caps it.
+func (session *Session) limitBody(resp *http.Response) io.Reader {
+	if session.limits != nil {
+		return resp.Body
+	}
+
+	return limitBody(resp)
+}
+
+// readJSON decodes the JSON body of resp into v, reading no more than
+// the session allows.
+func (session *Session) readJSON(resp *http.Response, v interface{}) error {
+	return json.NewDecoder(session.limitBody(resp)).Decode(v)
+}
+
+// limitBody caps the body of a response to a request made outside of a
+// session at DefaultMaxBodySize.
+func limitBody(resp *http.Response) io.Reader {
+	var u url.URL
+	if resp.Request != nil {
+		u = *resp.Request.URL
+		u.RawQuery = ""
+	}
+
+	return &limitedBody{
+		ReadCloser: resp.Body,
+		remaining:  DefaultMaxBodySize,
+		err:        &ResponseError{URL: u.Redacted(), StatusCode: resp.StatusCode, Err: ErrResponseTooLarge},
+	}
+}
+
+// readJSON is Session.readJSON for requests made outside of a session.
+func readJSON(resp *http.Response, v interface{}) error {
+	return json.NewDecoder(limitBody(resp)).Decode(v)
+}
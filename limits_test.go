@@ -0,0 +1,47 @@
+package steam
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+// oversized answers a JSON object padded with whitespace past size.
+func oversized(size int64) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(bytes.Repeat([]byte{' '}, int(size)+1))
+		w.Write([]byte(`{"response":{}}`))
+	})
+}
+
+func TestResponseLimitWithoutTransport(t *testing.T) {
+	session := newTestSession(t, oversized(DefaultMaxBodySize))
+
+	_, err := session.GetPlayerSummaries("76561197960287930")
+	var responseErr *ResponseError
+	if !errors.Is(err, ErrResponseTooLarge) || !errors.As(err, &responseErr) {
+		t.Fatalf("got %v, want ErrResponseTooLarge", err)
+	}
+
+	if len(responseErr.URL) == 0 || bytes.Contains([]byte(responseErr.URL), []byte("key=")) {
+		t.Errorf("got URL %q, want it without its query", responseErr.URL)
+	}
+}
+
+func TestSetResponseLimitsInstallsOnce(t *testing.T) {
+	session := newTestSession(t, oversized(1024))
+
+	session.SetResponseLimits(ResponseLimits{MaxBodySize: 1 << 20})
+	transport := session.client.Transport
+	session.SetResponseLimits(ResponseLimits{MaxBodySize: 512})
+
+	if session.client.Transport != transport {
+		t.Fatal("transport wrapped again")
+	}
+
+	if _, err := session.GetPlayerSummaries("76561197960287930"); !errors.Is(err, ErrResponseTooLarge) {
+		t.Fatalf("got %v, want the new limit of 512 bytes", err)
+	}
+}
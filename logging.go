@@ -0,0 +1,104 @@
+package steam
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// Logger receives the diagnostics of a Session, see SetLogger.
+type Logger interface {
+	Debugf(format string, args ...interface{})
+	Infof(format string, args ...interface{})
+	Warnf(format string, args ...interface{})
+	Errorf(format string, args ...interface{})
+}
+
+type nopLogger struct{}
+
+func (nopLogger) Debugf(string, ...interface{}) {}
+func (nopLogger) Infof(string, ...interface{})  {}
+func (nopLogger) Warnf(string, ...interface{})  {}
+func (nopLogger) Errorf(string, ...interface{}) {}
+
+type slogLogger struct {
+	l *slog.Logger
+}
+
+// NewSlogLogger adapts a *slog.Logger to Logger.
+func NewSlogLogger(l *slog.Logger) Logger {
+	return &slogLogger{l: l}
+}
+
+func (s *slogLogger) logf(level slog.Level, format string, args ...interface{}) {
+	if s.l.Enabled(context.Background(), level) {
+		s.l.Log(context.Background(), level, fmt.Sprintf(format, args...))
+	}
+}
+
+func (s *slogLogger) Debugf(format string, args ...interface{}) {
+	s.logf(slog.LevelDebug, format, args...)
+}
+
+func (s *slogLogger) Infof(format string, args ...interface{}) {
+	s.logf(slog.LevelInfo, format, args...)
+}
+
+func (s *slogLogger) Warnf(format string, args ...interface{}) {
+	s.logf(slog.LevelWarn, format, args...)
+}
+
+func (s *slogLogger) Errorf(format string, args ...interface{}) {
+	s.logf(slog.LevelError, format, args...)
+}
+
+// SetLogger makes the session report what it does to logger, nil silences
+// it again.
+func (session *Session) SetLogger(logger Logger) {
+	session.logger = logger
+}
+
+func (session *Session) log() Logger {
+	if session.logger == nil {
+		return nopLogger{}
+	}
+
+	return session.logger
+}
+
+type tracingTransport struct {
+	session *Session
+	next    http.RoundTripper
+}
+
+func (t *tracingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		t.session.log().Debugf("%s %s failed after %s: %v", req.Method, req.URL.Redacted(), time.Since(start), err)
+		return resp, err
+	}
+
+	t.session.log().Debugf("%s %s -> %d (eresult %q) in %s", req.Method, req.URL.Redacted(), resp.StatusCode, resp.Header.Get("x-eresult"), time.Since(start))
+	return resp, nil
+}
+
+// SetRequestTracing logs every request made through the session client at
+// debug level, with its status, EResult and duration.  Query strings may
+// carry the API key, keep tracing off where logs are shared.
+func (session *Session) SetRequestTracing(enabled bool) {
+	t, tracing := session.client.Transport.(*tracingTransport)
+	switch {
+	case enabled && !tracing:
+		next := session.client.Transport
+		if next == nil {
+			next = http.DefaultTransport
+		}
+
+		session.client.Transport = &tracingTransport{session: session, next: next}
+	case !enabled && tracing:
+		session.client.Transport = t.next
+	}
+}
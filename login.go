@@ -2,14 +2,12 @@ package steam
 
 import (
 	"bytes"
-	"crypto/md5"
 	"crypto/rand"
 	"crypto/rsa"
 	"encoding/base64"
 	"encoding/hex"
 	"encoding/json"
 	"errors"
-	"fmt"
 	"github.com/hiship/go-steam/pb"
 	"google.golang.org/protobuf/proto"
 	"io"
@@ -19,6 +17,7 @@ import (
 	"net/http/cookiejar"
 	"net/url"
 	"strconv"
+	"sync"
 	"time"
 )
 
@@ -48,6 +47,15 @@ type OAuth struct {
 }
 
 type Session struct {
+	sessionState
+
+	tokenMu  sync.Mutex // guards the access token renewal
+	regionMu sync.Mutex // guards regionReport
+}
+
+// sessionState is all of a Session but its locks, Clone copies it as a
+// value.
+type sessionState struct {
 	client      *http.Client
 	oauth       OAuth
 	sessionID   string
@@ -57,6 +65,47 @@ type Session struct {
 	chatMessage int
 	language    string
 	expireTime  time.Time // 登录过期时间
+	stats       *sessionStats
+	storage     Storage
+	givePolicy  *GivePolicy
+
+	logger          Logger
+	breakers        *breakerTransport
+	latency         *latencyTransport
+	quota           *quotaTransport
+	apiVersions     map[string]int
+	refreshToken    string
+	descriptions    *DescriptionCache
+	inventoryCache  InventoryCache
+	partners        *partnerCache
+	tokenPreflight  bool
+	loginApproval   bool
+	marketGuard     *MarketGuard
+	readOnly        bool
+	floatChecker    FloatChecker
+	regionCheck     bool
+	regionStrict    bool
+	regionReport    *RegionReport
+	evidenceCapture func(*Confirmation) bool
+	reloginPolicy   *ReloginPolicy
+	relogin         *reloginTransport
+
+	accessTokenExpiry   time.Time
+	tokenRenewalErr     error
+	tokenRenewalRetry   time.Time
+	tokenRenewalBackoff time.Duration
+	emailCodes          EmailCodeProvider
+	emailTimeout        time.Duration
+	offerDedup          bool
+	metrics             Metrics
+	requestMetrics      *metricsTransport
+	apiBaseURL          string
+	communityBaseURL    string
+	captchaSolver       CaptchaSolver
+	steamIDDeviceID     bool
+	failover            *failoverTransport
+	limiter             *rateLimitTransport
+	limits              *limitTransport
 }
 
 const (
@@ -91,22 +140,27 @@ func getRSAKey(accountName string) (*pb.CAuthentication_GetPasswordRSAPublicKey_
 	}
 
 	if xe := resp.Header.Get("x-eresult"); xe != "1" {
-		return nil, errors.New(xe)
+		return nil, eresultError(xe)
 	}
 
-	b, err = io.ReadAll(resp.Body)
+	b, err = io.ReadAll(limitBody(resp))
 
 	if b == nil {
 		return nil, err
 	}
 
 	var rsaKey pb.CAuthentication_GetPasswordRSAPublicKey_Response
-	_ = proto.Unmarshal(b, &rsaKey)
+	if err = proto.Unmarshal(b, &rsaKey); err != nil {
+		return nil, err
+	}
 
 	return &rsaKey, nil
 }
 
 func encryptPasword(pwd string, key *pb.CAuthentication_GetPasswordRSAPublicKey_Response) (string, error) {
+	if key.PublickeyMod == nil || key.PublickeyExp == nil {
+		return "", ErrInvalidResponse
+	}
 
 	var n big.Int
 	n.SetString(*key.PublickeyMod, 16)
@@ -171,27 +225,33 @@ func beginAuthSession(crypt string, accountName string, timestamp *uint64) (*pb.
 	}
 
 	if xe := resp.Header.Get("x-eresult"); xe != "1" {
-		return nil, errors.New(xe)
+		return nil, eresultError(xe)
 	}
 
-	b, err := io.ReadAll(resp.Body)
+	b, err := io.ReadAll(limitBody(resp))
 	if b == nil {
 		return nil, err
 	}
 
 	var authResponse pb.CAuthentication_BeginAuthSessionViaCredentials_Response
-	_ = proto.Unmarshal(b, &authResponse)
+	if err = proto.Unmarshal(b, &authResponse); err != nil {
+		return nil, err
+	}
+
+	if authResponse.Steamid == nil {
+		return nil, ErrInvalidResponse
+	}
 
 	return &authResponse, nil
 }
 
-func updateAuthSession(code string, authSession *pb.CAuthentication_BeginAuthSessionViaCredentials_Response) error {
+func updateAuthSession(code string, codeType GuardType, authSession *pb.CAuthentication_BeginAuthSessionViaCredentials_Response) error {
 
 	reqBody := pb.CAuthentication_UpdateAuthSessionWithSteamGuardCode_Request{
 		ClientId: authSession.ClientId,
 		Steamid:  authSession.Steamid,
 		Code:     &code,
-		CodeType: pb.EAuthSessionGuardType_k_EAuthSessionGuardType_DeviceCode.Enum(),
+		CodeType: pb.EAuthSessionGuardType(codeType).Enum(),
 	}
 
 	data, _ := proto.Marshal(&reqBody)
@@ -217,7 +277,7 @@ func updateAuthSession(code string, authSession *pb.CAuthentication_BeginAuthSes
 	}
 
 	if xe := resp.Header.Get("x-eresult"); xe != "1" {
-		return errors.New(xe)
+		return eresultError(xe)
 	}
 
 	return nil
@@ -253,21 +313,31 @@ func pollAuthSession(authSession *pb.CAuthentication_BeginAuthSessionViaCredenti
 	}
 
 	if xe := resp.Header.Get("x-eresult"); xe != "1" {
-		return nil, errors.New(xe)
+		return nil, eresultError(xe)
 	}
 
-	b, err := io.ReadAll(resp.Body)
+	b, err := io.ReadAll(limitBody(resp))
 	if b == nil {
 		return nil, err
 	}
 
 	var pollAuth pb.CAuthentication_PollAuthSessionStatus_Response
-	_ = proto.Unmarshal(b, &pollAuth)
+	if err = proto.Unmarshal(b, &pollAuth); err != nil {
+		return nil, err
+	}
+
+	if pollAuth.RefreshToken == nil {
+		return nil, ErrInvalidResponse
+	}
 
 	return &pollAuth, nil
 }
 
 func (session *Session) finalizeLogin(pollAuth *pb.CAuthentication_PollAuthSessionStatus_Response) error {
+	session.tokenMu.Lock()
+	session.refreshToken = pollAuth.GetRefreshToken()
+	session.setAccessToken(pollAuth.GetAccessToken())
+	session.tokenMu.Unlock()
 
 	if session.sessionID == "" {
 		randomBytes := make([]byte, 12)
@@ -308,15 +378,16 @@ func (session *Session) finalizeLogin(pollAuth *pb.CAuthentication_PollAuthSessi
 		return err
 	}
 
-	d := json.NewDecoder(resp.Body)
+	d := json.NewDecoder(limitBody(resp))
 
 	var loginFinalized LoginFinalized
 	if err = d.Decode(&loginFinalized); err != nil {
 		return err
 	}
 
-	// Add Refresh Cookie
-	jar, err := cookiejar.New(nil)
+	/* The cookies go into the jar requests are already using, replacing
+	 * it would race with them.  */
+	jar, err := session.cookieJar()
 	if err != nil {
 		return err
 	}
@@ -359,17 +430,36 @@ func (session *Session) finalizeLogin(pollAuth *pb.CAuthentication_PollAuthSessi
 		}
 		for _, cookie := range resp.Cookies() {
 			if cookie.Name == "steamLoginSecure" {
-				jar.SetCookies(&url.URL{Scheme: "https", Host: "steamcommunity.com"}, []*http.Cookie{cookie, {Name: "sessionid", Value: session.sessionID, SameSite: http.SameSiteNoneMode, Secure: true, HttpOnly: true, Path: "/"}})
+				jar.SetCookies(session.communityURL(), []*http.Cookie{cookie, {Name: "sessionid", Value: session.sessionID, SameSite: http.SameSiteNoneMode, Secure: true, HttpOnly: true, Path: "/"}})
 				break
 			}
 		}
 		break
 	}
-	session.client.Jar = jar
+
+	session.tokenMu.Lock()
+	session.expireTime = time.Now().Add(2 * 24 * time.Hour)
+	session.tokenMu.Unlock()
 	return nil
 }
 
+// cookieJar returns the jar of the session client, creating it when the
+// client has none.
+func (session *Session) cookieJar() (http.CookieJar, error) {
+	if session.client.Jar == nil {
+		jar, err := cookiejar.New(nil)
+		if err != nil {
+			return nil, err
+		}
+
+		session.client.Jar = jar
+	}
+
+	return session.client.Jar, nil
+}
+
 func (session *Session) Login(accountName, password, sharedSecret string, timeOffset time.Duration) error {
+	start := time.Now()
 
 	key, err := getRSAKey(accountName)
 	if key == nil {
@@ -382,14 +472,58 @@ func (session *Session) Login(accountName, password, sharedSecret string, timeOf
 	}
 
 	authSession, err := beginAuthSession(crypt, accountName, key.Timestamp)
+	if errors.Is(err, ErrLoginThrottled) && session.captchaSolver != nil {
+		if err = session.loginCaptcha(accountName, crypt, key.GetTimestamp()); err != nil {
+			return err
+		}
+
+		authSession, err = beginAuthSession(crypt, accountName, key.Timestamp)
+	}
 	if err != nil {
 		return err
 	}
 
-	code, _ := GenerateTwoFactorCode(sharedSecret, time.Now().Add(timeOffset).Unix())
+	types := guardTypes(authSession)
+	if _, ok := types[GuardTypeDeviceCode]; ok && len(sharedSecret) != 0 {
+		code, err := GenerateTwoFactorCode(sharedSecret, time.Now().Add(timeOffset).Unix())
+		if err != nil {
+			return err
+		}
 
-	if err = updateAuthSession(code, authSession); err != nil {
-		return err
+		return session.completeLogin(accountName, password, authSession, code, GuardTypeDeviceCode)
+	}
+
+	if _, ok := types[GuardTypeEmailCode]; ok && session.emailCodes != nil {
+		if _, ok = types[GuardTypeDeviceCode]; !ok {
+			code, err := session.emailGuardCode(accountName, start)
+			if err != nil {
+				return err
+			}
+
+			return session.completeLogin(accountName, password, authSession, code, GuardTypeEmailCode)
+		}
+	}
+
+	for _, guard := range []GuardType{GuardTypeDeviceCode, GuardTypeEmailCode} {
+		if message, ok := types[guard]; ok {
+			return &SteamGuardRequiredError{
+				Type:        guard,
+				EmailDomain: message,
+				accountName: accountName,
+				password:    password,
+				authSession: authSession,
+			}
+		}
+	}
+
+	return session.completeLogin(accountName, password, authSession, "", GuardTypeNone)
+}
+
+func (session *Session) completeLogin(accountName, password string, authSession *pb.CAuthentication_BeginAuthSessionViaCredentials_Response, code string, codeType GuardType) error {
+	if codeType != GuardTypeNone {
+		if err := updateAuthSession(code, codeType, authSession); err != nil {
+			return err
+		}
 	}
 
 	pollAuth, err := pollAuthSession(authSession)
@@ -401,15 +535,15 @@ func (session *Session) Login(accountName, password, sharedSecret string, timeOf
 	if err != nil {
 		return err
 	}
-	//Set the login expiration time
-	session.expireTime = time.Now().Add(2 * 24 * time.Hour)
-	sum := md5.Sum([]byte(accountName + password))
-	session.deviceID = fmt.Sprintf(
-		"android:%x-%x-%x-%x-%x",
-		sum[:2], sum[2:4], sum[4:6], sum[6:8], sum[8:10],
-	)
 
 	session.oauth.SteamID = SteamID(*authSession.Steamid)
+	if session.deviceID == "" {
+		if session.steamIDDeviceID {
+			session.deviceID = GenerateDeviceID(session.oauth.SteamID)
+		} else {
+			session.deviceID = LegacyDeviceID(accountName, password)
+		}
+	}
 	session.addMobileAuthCookies()
 	return nil
 }
@@ -421,7 +555,10 @@ func (session *Session) IsLogged() bool {
 	}
 
 	// 检查登录是否过期
-	if time.Now().After(session.expireTime) {
+	session.tokenMu.Lock()
+	expireTime := session.expireTime
+	session.tokenMu.Unlock()
+	if time.Now().After(expireTime) {
 		return false
 	}
 
@@ -446,7 +583,7 @@ func (session *Session) Refresh() error {
 		}
 	}
 	for _, cookie := range resp.Cookies() {
-		println(cookie.Name)
+		session.log().Debugf("refresh cookie: %s", cookie.Name)
 		if cookie.Name == "steamRefresh_steam" {
 			session.client.Jar.SetCookies(&url.URL{Scheme: "https", Host: "login.steampowered.com"}, []*http.Cookie{cookie})
 			break
@@ -455,7 +592,7 @@ func (session *Session) Refresh() error {
 	return nil
 }
 
-func (session Session) addMobileAuthCookies() {
+func (session *Session) addMobileAuthCookies() {
 
 	cookies := []*http.Cookie{
 		{Name: "mobileClientVersion", Value: "0 (2.1.3)"},
@@ -465,13 +602,45 @@ func (session Session) addMobileAuthCookies() {
 		{Name: "dob", Value: ""},
 	}
 
-	session.client.Jar.SetCookies(&url.URL{Scheme: "https", Host: "steamcommunity.com"}, cookies)
+	session.client.Jar.SetCookies(session.communityURL(), cookies)
 }
 
 func (session *Session) GetSteamID() SteamID {
 	return session.oauth.SteamID
 }
 
+// GetRefreshToken returns the refresh token of the last login, which the
+// cm package logs on to the Steam client network with.  It is as
+// sensitive as the password.
+func (session *Session) GetRefreshToken() string {
+	session.tokenMu.Lock()
+	defer session.tokenMu.Unlock()
+
+	return session.refreshToken
+}
+
+// GetDeviceID returns the android device ID sent along mobile confirmation
+// and authenticator requests, it must match the one the authenticator was
+// registered with.
+func (session *Session) GetDeviceID() string {
+	return session.deviceID
+}
+
+// SetDeviceID overrides the device ID, e.g. with the device_id of a
+// maFile, it is kept across logins.  An empty id is derived again on the
+// next login, see SetSteamIDDeviceID.
+func (session *Session) SetDeviceID(id string) {
+	session.deviceID = id
+}
+
+// SetSteamIDDeviceID makes logins without a device ID derive it from the
+// SteamID with GenerateDeviceID, as the mobile authenticator tools do,
+// rather than with LegacyDeviceID.  Only enable it for authenticators
+// registered with such a device ID.
+func (session *Session) SetSteamIDDeviceID(enabled bool) {
+	session.steamIDDeviceID = enabled
+}
+
 func (session *Session) SetLanguage(lang string) {
 	session.language = lang
 }
@@ -481,17 +650,21 @@ func (session *Session) GetClient() *http.Client {
 }
 
 func NewSessionWithAPIKey(apiKey string) *Session {
-	return &Session{
-		client:   &http.Client{},
-		apiKey:   apiKey,
-		language: "english",
-	}
+	return &Session{sessionState: sessionState{
+		client:       withDecoding(&http.Client{}),
+		apiKey:       apiKey,
+		language:     "english",
+		stats:        newSessionStats(),
+		descriptions: NewDescriptionCache(),
+	}}
 }
 
 func NewSession(client *http.Client, apiKey string) *Session {
-	return &Session{
-		client:   client,
-		apiKey:   apiKey,
-		language: "english",
-	}
+	return &Session{sessionState: sessionState{
+		client:       withDecoding(client),
+		apiKey:       apiKey,
+		language:     "english",
+		stats:        newSessionStats(),
+		descriptions: NewDescriptionCache(),
+	}}
 }
@@ -0,0 +1,112 @@
+package steam
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/hiship/go-steam/pb"
+)
+
+var (
+	ErrInvalidPassword = errors.New("invalid account name or password")
+	ErrLoginThrottled  = errors.New("too many login attempts, try again later")
+)
+
+// LoginError is returned when one of the authentication calls fails with a
+// non-OK EResult.  It matches ErrInvalidPassword and ErrLoginThrottled with
+// errors.Is where applicable.
+type LoginError struct {
+	EResult int
+}
+
+func (e *LoginError) Error() string {
+	return "login failed: eresult " + strconv.Itoa(e.EResult)
+}
+
+func (e *LoginError) Is(target error) bool {
+	switch target {
+	case ErrInvalidPassword:
+		return e.EResult == 5 // InvalidPassword
+	case ErrLoginThrottled:
+		return e.EResult == 84 || e.EResult == 87 // RateLimitExceeded, AccountLoginDeniedThrottle
+	}
+
+	return false
+}
+
+func eresultError(xe string) error {
+	result, err := strconv.Atoi(xe)
+	if err != nil {
+		return errors.New(xe)
+	}
+
+	return &LoginError{EResult: result}
+}
+
+// GuardType is the kind of Steam Guard code a login is waiting for.
+type GuardType int
+
+const (
+	GuardTypeNone       GuardType = GuardType(pb.EAuthSessionGuardType_k_EAuthSessionGuardType_None)
+	GuardTypeEmailCode  GuardType = GuardType(pb.EAuthSessionGuardType_k_EAuthSessionGuardType_EmailCode)
+	GuardTypeDeviceCode GuardType = GuardType(pb.EAuthSessionGuardType_k_EAuthSessionGuardType_DeviceCode)
+)
+
+// SteamGuardRequiredError is returned by Login when Steam wants a code the
+// session cannot generate by itself (an email code, or a device code when
+// no shared secret was given).  The login stays pending and can be resumed
+// with Submit.
+//
+// The IAuthenticationService flow does not hand out captchas, repeated
+// failures end up as ErrLoginThrottled instead, which Login gets past
+// with the CaptchaSolver of the session when one is set.
+type SteamGuardRequiredError struct {
+	Type        GuardType
+	EmailDomain string // set for GuardTypeEmailCode
+
+	accountName string
+	password    string
+	authSession *pb.CAuthentication_BeginAuthSessionViaCredentials_Response
+}
+
+func (e *SteamGuardRequiredError) Error() string {
+	if e.Type == GuardTypeEmailCode {
+		return "steam guard email code required (" + e.EmailDomain + ")"
+	}
+
+	return "steam guard device code required"
+}
+
+// Submit finishes the pending login with the code the user received.
+func (e *SteamGuardRequiredError) Submit(session *Session, code string) error {
+	return session.completeLogin(e.accountName, e.password, e.authSession, code, e.Type)
+}
+
+// guardTypes lists the confirmation types Steam offered for the login.
+func guardTypes(authSession *pb.CAuthentication_BeginAuthSessionViaCredentials_Response) map[GuardType]string {
+	types := make(map[GuardType]string)
+	for _, confirmation := range authSession.AllowedConfirmations {
+		types[GuardType(confirmation.GetConfirmationType())] = confirmation.GetAssociatedMessage()
+	}
+
+	return types
+}
+
+// LoginWithCodeCallback logs in without a shared secret, calling code
+// whenever Steam asks for a Steam Guard code (from the mobile app or sent
+// by email) so a human can type it in.
+func (session *Session) LoginWithCodeCallback(accountName, password string, code func() (string, error)) error {
+	err := session.Login(accountName, password, "", 0)
+
+	var guardErr *SteamGuardRequiredError
+	if !errors.As(err, &guardErr) {
+		return err
+	}
+
+	c, err := code()
+	if err != nil {
+		return err
+	}
+
+	return guardErr.Submit(session, c)
+}
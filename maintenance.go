@@ -0,0 +1,99 @@
+package steam
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+)
+
+var (
+	// ErrSteamMaintenance is returned when Steam answers with its
+	// maintenance page, usually during the Tuesday maintenance.
+	ErrSteamMaintenance = errors.New("steam is down for maintenance")
+
+	// ErrSteamBusy is returned when Steam answers with an error page
+	// asking to try again later.  Unlike authentication failures retrying
+	// after a while is expected to work.
+	ErrSteamBusy = errors.New("steam is busy, try again later")
+)
+
+// maxErrorPageSize bounds how much of an error page is read to classify it.
+const maxErrorPageSize = 64 << 10
+
+var (
+	maintenanceMarkers = [][]byte{
+		[]byte("down for maintenance"),
+		[]byte("scheduled maintenance"),
+		[]byte("routine maintenance"),
+	}
+	busyMarkers = [][]byte{
+		[]byte("try again later"),
+		[]byte("too many requests"),
+		[]byte("currently unavailable"),
+	}
+)
+
+func containsAny(body []byte, markers [][]byte) bool {
+	for _, marker := range markers {
+		if bytes.Contains(body, marker) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// unavailableError classifies a page Steam answered with, nil when it is
+// not an unavailability page.  Pages served with 200 must also be titled as
+// an error page, regular pages carry "try again later" in their scripts.
+func unavailableError(status int, body []byte) error {
+	lower := bytes.ToLower(body)
+	if status == http.StatusOK && !bytes.Contains(lower, []byte(":: error</title>")) {
+		return nil
+	}
+
+	switch {
+	case containsAny(lower, maintenanceMarkers):
+		return ErrSteamMaintenance
+	case containsAny(lower, busyMarkers):
+		return ErrSteamBusy
+	case status == http.StatusServiceUnavailable || status == http.StatusTooManyRequests:
+		return ErrSteamBusy
+	}
+
+	return nil
+}
+
+// checkUnavailable turns the HTML error pages answered with a 429 or 5xx
+// status, or with a 200 and an error title, into ErrSteamMaintenance or
+// ErrSteamBusy, other responses are returned as they are.
+func checkUnavailable(resp *http.Response) (*http.Response, error) {
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode < 500 {
+		return resp, nil
+	}
+
+	contentType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if contentType != "text/html" {
+		return resp, nil
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxErrorPageSize))
+	if err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+
+	if err = unavailableError(resp.StatusCode, body); err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+
+	// Not recognized, let the caller see the page as it came.
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(body), resp.Body), resp.Body}
+	return resp, nil
+}
@@ -0,0 +1,48 @@
+package steam
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+)
+
+func TestCheckUnavailable(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		err    error
+	}{
+		{"maintenance", http.StatusServiceUnavailable, "<p>Steam is down for maintenance</p>", ErrSteamMaintenance},
+		{"busy", http.StatusTooManyRequests, "<p>Too many requests</p>", ErrSteamBusy},
+		{"error page answered with 200", http.StatusOK, "<title>Steam Community :: Error</title><p>Please try again later.</p>", ErrSteamBusy},
+		{"regular page", http.StatusOK, "<title>Steam Community :: Market</title><script>alert('try again later')</script>", nil},
+		{"not found", http.StatusNotFound, "<p>try again later</p>", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session := newTestSession(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "text/html; charset=utf-8")
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+
+			resp, err := session.client.Get("https://steamcommunity.com/market/")
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("got %v, want %v", err, tt.err)
+			}
+			if err != nil {
+				return
+			}
+			defer resp.Body.Close()
+
+			// Pages that are not recognized come through whole.
+			body, err := io.ReadAll(resp.Body)
+			if err != nil || string(body) != tt.body {
+				t.Errorf("got body %q, %v, want it unchanged", body, err)
+			}
+		})
+	}
+}
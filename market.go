@@ -9,50 +9,54 @@ import (
 	"net/url"
 	"strconv"
 	"strings"
+
+	"github.com/hiship/go-steam/currency"
 )
 
-const (
-	CurrencyUSD = "1"
-	CurrencyGBP = "2"
-	CurrencyEUR = "3"
-	CurrencyCHF = "4"
-	CurrencyRUB = "5"
-	CurrencyPLN = "6"
-	CurrencyBRL = "7"
-	CurrencyJPY = "8"
-	CurrencyNOK = "9"
-	CurrencyIDR = "10"
-	CurrencyMYR = "11"
-	CurrencyPHP = "12"
-	CurrencySGD = "13"
-	CurrencyTHB = "14"
-	CurrencyVND = "15"
-	CurrencyKRW = "16"
-	CurrencyTRY = "17"
-	CurrencyUAH = "18"
-	CurrencyMXN = "19"
-	CurrencyCAD = "20"
-	CurrencyAUD = "21"
-	CurrencyNZD = "22"
-	CurrencyCNY = "23"
-	CurrencyINR = "24"
-	CurrencyCLP = "25"
-	CurrencyPEN = "26"
-	CurrencyCOP = "27"
-	CurrencyZAR = "28"
-	CurrencyHKD = "29"
-	CurrencyTWD = "30"
-	CurrencySAR = "31"
-	CurrencyAED = "32"
-	CurrencyARS = "34"
-	CurrencyILS = "35"
-	CurrencyBYN = "36"
-	CurrencyKZT = "37"
-	CurrencyKWD = "38"
-	CurrencyQAR = "39"
-	CurrencyCRC = "40"
-	CurrencyUYU = "41"
-	CurrencyRMB = "9000"
+// The currency parameters of the market endpoints, the currency package
+// has the currencies themselves.
+var (
+	CurrencyUSD = currency.USD.Param()
+	CurrencyGBP = currency.GBP.Param()
+	CurrencyEUR = currency.EUR.Param()
+	CurrencyCHF = currency.CHF.Param()
+	CurrencyRUB = currency.RUB.Param()
+	CurrencyPLN = currency.PLN.Param()
+	CurrencyBRL = currency.BRL.Param()
+	CurrencyJPY = currency.JPY.Param()
+	CurrencyNOK = currency.NOK.Param()
+	CurrencyIDR = currency.IDR.Param()
+	CurrencyMYR = currency.MYR.Param()
+	CurrencyPHP = currency.PHP.Param()
+	CurrencySGD = currency.SGD.Param()
+	CurrencyTHB = currency.THB.Param()
+	CurrencyVND = currency.VND.Param()
+	CurrencyKRW = currency.KRW.Param()
+	CurrencyTRY = currency.TRY.Param()
+	CurrencyUAH = currency.UAH.Param()
+	CurrencyMXN = currency.MXN.Param()
+	CurrencyCAD = currency.CAD.Param()
+	CurrencyAUD = currency.AUD.Param()
+	CurrencyNZD = currency.NZD.Param()
+	CurrencyCNY = currency.CNY.Param()
+	CurrencyINR = currency.INR.Param()
+	CurrencyCLP = currency.CLP.Param()
+	CurrencyPEN = currency.PEN.Param()
+	CurrencyCOP = currency.COP.Param()
+	CurrencyZAR = currency.ZAR.Param()
+	CurrencyHKD = currency.HKD.Param()
+	CurrencyTWD = currency.TWD.Param()
+	CurrencySAR = currency.SAR.Param()
+	CurrencyAED = currency.AED.Param()
+	CurrencyARS = currency.ARS.Param()
+	CurrencyILS = currency.ILS.Param()
+	CurrencyBYN = currency.BYN.Param()
+	CurrencyKZT = currency.KZT.Param()
+	CurrencyKWD = currency.KWD.Param()
+	CurrencyQAR = currency.QAR.Param()
+	CurrencyCRC = currency.CRC.Param()
+	CurrencyUYU = currency.UYU.Param()
+	CurrencyRMB = currency.RMB.Param()
 )
 
 type MarketItemPriceOverview struct {
@@ -95,7 +99,7 @@ var (
 )
 
 func (session *Session) GetMarketItemPriceHistory(appID uint64, marketHashName string) ([]*MarketItemPrice, error) {
-	resp, err := session.client.Get("https://steamcommunity.com/market/pricehistory/?" + url.Values{
+	resp, err := session.client.Get(session.CommunityBaseURL() + "/market/pricehistory/?" + url.Values{
 		"appid":            {strconv.FormatUint(appID, 10)},
 		"market_hash_name": {marketHashName},
 	}.Encode())
@@ -117,7 +121,7 @@ func (session *Session) GetMarketItemPriceHistory(appID uint64, marketHashName s
 	}
 
 	response := MarketItemResponse{}
-	if err = json.NewDecoder(resp.Body).Decode(&response); err != nil {
+	if err = decodeJSON(session.limitBody(resp), &response); err != nil {
 		return nil, err
 	}
 
@@ -143,8 +147,8 @@ func (session *Session) GetMarketItemPriceHistory(appID uint64, marketHashName s
 					} else {
 						item.Date = val
 					}
-				case float64:
-					item.Price = val
+				case json.Number:
+					item.Price, _ = val.Float64()
 				}
 			}
 			items = append(items, item)
@@ -154,7 +158,7 @@ func (session *Session) GetMarketItemPriceHistory(appID uint64, marketHashName s
 }
 
 func (session *Session) GetMarketItemPriceOverview(appID uint64, country, currencyID, marketHashName string) (*MarketItemPriceOverview, error) {
-	resp, err := session.client.Get("https://steamcommunity.com/market/priceoverview/?" + url.Values{
+	resp, err := session.client.Get(session.CommunityBaseURL() + "/market/priceoverview/?" + url.Values{
 		"appid":            {strconv.FormatUint(appID, 10)},
 		"country":          {country},
 		"currencyID":       {currencyID},
@@ -173,14 +177,14 @@ func (session *Session) GetMarketItemPriceOverview(appID uint64, country, curren
 		return nil, err
 	}
 	if resp == nil {
-		return nil, errors.New("invalid response")
+		return nil, ErrInvalidResponse
 	}
 	if resp.StatusCode != http.StatusOK {
 		return nil, fmt.Errorf("http error: %d", resp.StatusCode)
 	}
 
 	overview := &MarketItemPriceOverview{}
-	if err = json.NewDecoder(resp.Body).Decode(overview); err != nil {
+	if err = session.readJSON(resp, overview); err != nil {
 		return nil, err
 	}
 
@@ -188,9 +192,21 @@ func (session *Session) GetMarketItemPriceOverview(appID uint64, country, curren
 }
 
 func (session *Session) SellItem(item *InventoryItem, amount, price uint64) (*MarketSellResponse, error) {
+	if err := session.checkWritable(); err != nil {
+		return nil, err
+	}
+
+	if err := session.checkMarketGuard(); err != nil {
+		return nil, err
+	}
+
+	if err := session.checkRegion(); err != nil {
+		return nil, err
+	}
+
 	req, err := http.NewRequest(
 		http.MethodPost,
-		"https://steamcommunity.com/market/sellitem/",
+		session.CommunityBaseURL()+"/market/sellitem/",
 		strings.NewReader(url.Values{
 			"amount":    {strconv.FormatUint(amount, 10)},
 			"appid":     {strconv.FormatUint(uint64(item.AppID), 10)},
@@ -230,7 +246,7 @@ func (session *Session) SellItem(item *InventoryItem, amount, price uint64) (*Ma
 	}
 
 	response := &MarketSellResponse{}
-	if err = json.NewDecoder(resp.Body).Decode(response); err != nil {
+	if err = session.readJSON(resp, response); err != nil {
 		return nil, err
 	}
 
@@ -238,9 +254,21 @@ func (session *Session) SellItem(item *InventoryItem, amount, price uint64) (*Ma
 }
 
 func (session *Session) PlaceBuyOrder(appid uint64, priceTotal float64, quantity uint64, currencyID, marketHashName string) (*MarketBuyOrderResponse, error) {
+	if err := session.checkWritable(); err != nil {
+		return nil, err
+	}
+
+	if err := session.checkMarketGuard(); err != nil {
+		return nil, err
+	}
+
+	if err := session.checkRegion(); err != nil {
+		return nil, err
+	}
+
 	req, err := http.NewRequest(
 		http.MethodPost,
-		"https://steamcommunity.com/market/createbuyorder/",
+		session.CommunityBaseURL()+"/market/createbuyorder/",
 		strings.NewReader(url.Values{
 			"appid":            {strconv.FormatUint(appid, 10)},
 			"currency":         {currencyID},
@@ -260,7 +288,7 @@ func (session *Session) PlaceBuyOrder(appid uint64, priceTotal float64, quantity
 
 	req.Header.Add(
 		"Referer",
-		fmt.Sprintf("https://steamcommunity.com/market/listings/%d/%s", appid, referer),
+		session.CommunityBaseURL()+fmt.Sprintf("/market/listings/%d/%s", appid, referer),
 	)
 	req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
 
@@ -279,7 +307,7 @@ func (session *Session) PlaceBuyOrder(appid uint64, priceTotal float64, quantity
 	}
 
 	response := &MarketBuyOrderResponse{}
-	if err = json.NewDecoder(resp.Body).Decode(response); err != nil {
+	if err = session.readJSON(resp, response); err != nil {
 		return nil, err
 	}
 
@@ -287,9 +315,13 @@ func (session *Session) PlaceBuyOrder(appid uint64, priceTotal float64, quantity
 }
 
 func (session *Session) CancelBuyOrder(orderid uint64) error {
+	if err := session.checkWritable(); err != nil {
+		return err
+	}
+
 	req, err := http.NewRequest(
 		http.MethodPost,
-		"https://steamcommunity.com/market/cancelbuyorder/",
+		session.CommunityBaseURL()+"/market/cancelbuyorder/",
 		strings.NewReader(url.Values{
 			"sessionid":   {session.sessionID},
 			"buy_orderid": {strconv.FormatUint(orderid, 10)},
@@ -299,7 +331,7 @@ func (session *Session) CancelBuyOrder(orderid uint64) error {
 		return err
 	}
 
-	req.Header.Add("Referer", "https://steamcommunity.com/market")
+	req.Header.Add("Referer", session.CommunityBaseURL()+"/market")
 	req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
 
 	resp, err := session.client.Do(req)
@@ -322,3 +354,79 @@ func (session *Session) CancelBuyOrder(orderid uint64) error {
 
 	return nil
 }
+
+type BuyOrderPurchase struct {
+	AppID         uint32 `json:"appid"`
+	ContextID     uint64 `json:"contextid,string"`
+	AssetID       uint64 `json:"assetid,string"`
+	ListingID     string `json:"listingid"`
+	PriceSubtotal uint64 `json:"price_subtotal"`
+	PriceFee      uint64 `json:"price_fee"`
+}
+
+// BuyOrderStatus tells how much of a buy order got filled, the order is
+// cancelled when it is no longer active with items still remaining.
+type BuyOrderStatus struct {
+	Active            bool
+	Quantity          uint64
+	QuantityRemaining uint64
+	Purchased         uint64 // paid for
+	Cancelled         uint64
+	Purchases         []*BuyOrderPurchase
+}
+
+func (session *Session) GetBuyOrderStatus(orderID uint64) (*BuyOrderStatus, error) {
+	resp, err := session.client.Get(session.CommunityBaseURL() + "/market/getbuyorderstatus/?" + url.Values{
+		"sessionid":   {session.sessionID},
+		"buy_orderid": {strconv.FormatUint(orderID, 10)},
+	}.Encode())
+	if resp != nil {
+		defer func(Body io.ReadCloser) {
+			err := Body.Close()
+			if err != nil {
+				return
+			}
+		}(resp.Body)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("http error: %d", resp.StatusCode)
+	}
+
+	type Response struct {
+		Success           int                 `json:"success"`
+		Active            int                 `json:"active"`
+		Purchased         uint64              `json:"purchased"`
+		Quantity          uint64              `json:"quantity,string"`
+		QuantityRemaining uint64              `json:"quantity_remaining,string"`
+		Purchases         []*BuyOrderPurchase `json:"purchases"`
+	}
+
+	var response Response
+	if err = session.readJSON(resp, &response); err != nil {
+		return nil, err
+	}
+
+	if response.Success != 1 {
+		return nil, ErrInvalidResponse
+	}
+
+	status := &BuyOrderStatus{
+		Active:            response.Active != 0,
+		Quantity:          response.Quantity,
+		QuantityRemaining: response.QuantityRemaining,
+		Purchased:         response.Quantity - response.QuantityRemaining,
+		Purchases:         response.Purchases,
+	}
+	if response.Purchased > status.Purchased {
+		status.Purchased = response.Purchased
+	}
+	if !status.Active {
+		status.Cancelled = response.QuantityRemaining
+	}
+
+	return status, nil
+}
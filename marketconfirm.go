@@ -0,0 +1,118 @@
+package steam
+
+import (
+	"errors"
+	"strconv"
+	"time"
+)
+
+// marketConfirmInterval is how long to wait between looks for the
+// confirmation of a new listing, Steam takes a moment to list it.
+const marketConfirmInterval = 2 * time.Second
+
+var (
+	ErrListingNotCreated = errors.New("market listing was not created")
+	ErrListingAmbiguous  = errors.New("several market listings match the item and price")
+)
+
+// SellItemConfirmed lists the whole stack of item on the market at price
+// (in cents, what the seller receives) and allows the mobile confirmation
+// of the listing with identitySecret.  It returns the id of the listing.
+//
+// The listing is told apart from the others of the account by the asset
+// ID and the price, and only the confirmation created for that listing is
+// allowed.  When several listings match, e.g. the same item relisted at
+// the same price, ErrListingAmbiguous is returned and nothing is allowed.
+func (session *Session) SellItemConfirmed(item *InventoryItem, price uint64, identitySecret string) (uint64, error) {
+	amount := uint64(1)
+	if n, err := strconv.ParseUint(item.Amount, 10, 64); err == nil && n != 0 {
+		amount = n
+	}
+
+	response, err := session.SellItem(item, amount, price)
+	if err != nil {
+		return 0, err
+	}
+
+	if !response.Success {
+		return 0, ErrListingNotCreated
+	}
+
+	awaiting := response.RequiresConfirmation != 0 || response.MobileConfirmationRequired
+	for attempt := 0; attempt < confirmAttempts; attempt++ {
+		if attempt != 0 {
+			time.Sleep(marketConfirmInterval)
+		}
+
+		id, err := session.findListing(item.AssetID, price, awaiting)
+		if err != nil {
+			return 0, err
+		}
+		if id == 0 {
+			continue
+		}
+
+		if !awaiting {
+			return id, nil
+		}
+
+		current := time.Now().Unix()
+		confirmations, err := session.GetConfirmations(identitySecret, current)
+		if err != nil {
+			return 0, err
+		}
+
+		confirmation := listingConfirmation(confirmations, id)
+		if confirmation == nil {
+			continue
+		}
+
+		if err = session.AnswerConfirmation(confirmation, identitySecret, "allow", current); err != nil {
+			return 0, err
+		}
+
+		return id, nil
+	}
+
+	if !awaiting {
+		return 0, ErrListingNotCreated
+	}
+
+	return 0, ErrNoConfirmation
+}
+
+// findListing returns the id of the only listing of assetID at price
+// awaiting confirmation or not, 0 when there is none yet.
+func (session *Session) findListing(assetID, price uint64, awaiting bool) (uint64, error) {
+	listings, err := session.GetMyMarketListings()
+	if err != nil {
+		return 0, err
+	}
+
+	var id uint64
+	for _, listing := range listings {
+		if listing.AssetID != assetID || listing.Price != price || listing.AwaitingConfirmation != awaiting {
+			continue
+		}
+
+		if id != 0 {
+			return 0, ErrListingAmbiguous
+		}
+		id = listing.ID
+	}
+
+	return id, nil
+}
+
+// listingConfirmation picks the market listing confirmation created for
+// the listing id.
+func listingConfirmation(confirmations []*Confirmation, id uint64) *Confirmation {
+	creator := strconv.FormatUint(id, 10)
+	for _, confirmation := range confirmations {
+		if confirmation.Type == ConfirmationTypeMarketListing && confirmation.Creator == creator {
+			return confirmation
+		}
+	}
+
+	return nil
+}
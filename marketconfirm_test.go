@@ -0,0 +1,76 @@
+package steam
+
+import (
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestSellItemConfirmed(t *testing.T) {
+	secret := base64.StdEncoding.EncodeToString([]byte("identity secret"))
+	listing := func(id, assetID, price string) string {
+		return `{"listingid":"` + id + `","original_price":` + price + `,"asset":{"appid":730,"contextid":"2","id":"` + assetID + `","amount":"1"}}`
+	}
+
+	tests := []struct {
+		name      string
+		toConfirm string
+		id        uint64
+		err       error
+	}{
+		{"matches asset and price", listing("5", "10", "100") + "," + listing("6", "11", "150") + "," + listing("7", "11", "100"), 7, nil},
+		{"ambiguous", listing("7", "11", "100") + "," + listing("8", "11", "100"), 0, ErrListingAmbiguous},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				mu       sync.Mutex
+				answered []string
+			)
+			session := newTestSession(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var body string
+				switch r.URL.Path {
+				case "/my":
+					http.Redirect(w, r, "https://steamcommunity.com/profiles/76561197960287930/", http.StatusFound)
+					return
+				case "/market/sellitem/":
+					body = `{"success":true,"requires_confirmation":1,"needs_mobile_confirmation":true}`
+				case "/market/mylistings/render/":
+					body = `{"success":true,"total_count":0,"listings":[],"listings_to_confirm":[` + tt.toConfirm + `]}`
+				case "/mobileconf/getlist":
+					body = `{"success":true,"conf":[
+						{"id":"50","type":3,"creator_id":"5","nonce":"n5","headline":"AK-47 | Redline"},
+						{"id":"70","type":3,"creator_id":"7","nonce":"n7","headline":"AK-47 | Redline"},
+						{"id":"80","type":3,"creator_id":"8","nonce":"n8","headline":"AK-47 | Redline"}]}`
+				case "/mobileconf/ajaxop":
+					mu.Lock()
+					answered = append(answered, r.URL.Query().Get("cid"))
+					mu.Unlock()
+					body = `{"success":true}`
+				default:
+					http.NotFound(w, r)
+					return
+				}
+				answer(body).ServeHTTP(w, r)
+			}))
+
+			item := &InventoryItem{AppID: 730, ContextID: 2, AssetID: 11, Amount: "1"}
+			id, err := session.SellItemConfirmed(item, 100, secret)
+			if !errors.Is(err, tt.err) || id != tt.id {
+				t.Fatalf("got %d, %v, want %d, %v", id, err, tt.id, tt.err)
+			}
+
+			want := "70"
+			if tt.err != nil {
+				want = ""
+			}
+			if got := func() string { mu.Lock(); defer mu.Unlock(); return strings.Join(answered, ",") }(); got != want {
+				t.Errorf("allowed confirmations %q, want %q", got, want)
+			}
+		})
+	}
+}
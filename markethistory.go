@@ -0,0 +1,257 @@
+package steam
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/hiship/go-steam/currency"
+)
+
+const marketHistoryURL = "/market/myhistory/render/?"
+
+// The date columns of the history show no year, e.g. "12 Oct" or
+// "Oct 12" depending on the language.
+var marketHistoryDateLayouts = []string{"2 Jan", "Jan 2"}
+
+var (
+	// marketHoverExp matches the hover registration of a history row, which
+	// is the only place naming the asset of the row:
+	//
+	//	CreateItemHoverFromContainer( g_rgAssets, 'history_row_..._name', 730, '2', '25081553418', 0 );
+	marketHoverExp = regexp.MustCompile(`'(history_row_\w+?)_(?:name|image)',\s*(\d+),\s*'(\d+)',\s*'(\d+)'`)
+
+	profileLinkExp = regexp.MustCompile(`/profiles/(\d+)`)
+)
+
+// MarketAction is what happened in a row of the market history.
+type MarketAction uint8
+
+const (
+	MarketActionUnknown MarketAction = iota
+	MarketActionListed
+	MarketActionCanceled
+	MarketActionSold
+	MarketActionPurchased
+)
+
+var marketActionStrings = [...]string{
+	MarketActionUnknown:   "Unknown",
+	MarketActionListed:    "Listed",
+	MarketActionCanceled:  "Canceled",
+	MarketActionSold:      "Sold",
+	MarketActionPurchased: "Purchased",
+}
+
+func (action MarketAction) String() string {
+	if int(action) < len(marketActionStrings) {
+		return marketActionStrings[action]
+	}
+
+	return "Unknown(" + strconv.FormatUint(uint64(action), 10) + ")"
+}
+
+// MarketHistoryRow is one row of the market history of the account.
+type MarketHistoryRow struct {
+	ID        string // the row id, unique within the history
+	ListingID uint64
+	Action    MarketAction
+
+	AppID     uint32
+	ContextID uint64
+	AssetID   uint64
+	Desc      *EconItemDesc // may be nil
+	Name      string
+	GameName  string
+
+	// Partner is the buyer or seller, zero for listings created or
+	// canceled and for partners linked by a custom URL.
+	Partner     SteamID
+	PartnerName string
+	PartnerURL  string
+
+	// Price is what the buyer pays in cents of the account currency, Fee
+	// the part of it the Steam and (default) publisher fees take, as
+	// computed from Price.
+	PriceText string
+	Price     uint64
+	Fee       uint64
+	Currency  currency.ID // as told by PriceText, zero if it names none
+
+	DateText string    // as Steam wrote it, "acted on" column
+	Date     time.Time // DateText with the year guessed, zero if unknown
+}
+
+type marketHistoryResponse struct {
+	Success    bool                                           `json:"success"`
+	TotalCount int                                            `json:"total_count"`
+	Assets     map[string]map[string]map[string]*EconItemDesc `json:"assets"`
+	Hovers     string                                         `json:"hovers"`
+	HTML       string                                         `json:"results_html"`
+}
+
+// GetMarketHistory returns count rows of the market history from start,
+// the newest first, and the number of rows in the whole history.
+func (session *Session) GetMarketHistory(start, count int) ([]*MarketHistoryRow, int, error) {
+	resp, err := session.client.Get(session.CommunityBaseURL() + marketHistoryURL + url.Values{
+		"query": {""},
+		"start": {strconv.Itoa(start)},
+		"count": {strconv.Itoa(count)},
+	}.Encode())
+	if resp != nil {
+		defer func(Body io.ReadCloser) {
+			err := Body.Close()
+			if err != nil {
+				return
+			}
+		}(resp.Body)
+	}
+
+	if err != nil {
+		return nil, 0, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, 0, fmt.Errorf("http error: %d", resp.StatusCode)
+	}
+
+	var response marketHistoryResponse
+	if err = session.readJSON(resp, &response); err != nil {
+		return nil, 0, err
+	}
+
+	if !response.Success {
+		return nil, 0, ErrInvalidResponse
+	}
+
+	rows, err := parseMarketHistory(&response, time.Now())
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return rows, response.TotalCount, nil
+}
+
+type hoverAsset struct {
+	appID     uint32
+	contextID uint64
+	assetID   uint64
+}
+
+func parseMarketHistory(response *marketHistoryResponse, now time.Time) ([]*MarketHistoryRow, error) {
+	p, err := parsePage("market/myhistory", []byte(response.HTML))
+	if err != nil {
+		return nil, err
+	}
+
+	hovers := make(map[string]hoverAsset)
+	for _, m := range marketHoverExp.FindAllStringSubmatch(response.Hovers, -1) {
+		appID, _ := strconv.ParseUint(m[2], 10, 32)
+		contextID, _ := strconv.ParseUint(m[3], 10, 64)
+		assetID, _ := strconv.ParseUint(m[4], 10, 64)
+		hovers[m[1]] = hoverAsset{uint32(appID), contextID, assetID}
+	}
+
+	var rows []*MarketHistoryRow
+	p.doc.Find(".market_listing_row[id^='history_row_']").Each(func(_ int, s *goquery.Selection) {
+		id, _ := s.Attr("id")
+		row := &MarketHistoryRow{ID: id}
+
+		if parts := strings.SplitN(strings.TrimPrefix(id, "history_row_"), "_", 2); len(parts) != 0 {
+			row.ListingID, _ = strconv.ParseUint(parts[0], 10, 64)
+		}
+
+		if asset, ok := hovers[id]; ok {
+			row.AppID, row.ContextID, row.AssetID = asset.appID, asset.contextID, asset.assetID
+			row.Desc = response.Assets[strconv.FormatUint(uint64(asset.appID), 10)][strconv.FormatUint(asset.contextID, 10)][strconv.FormatUint(asset.assetID, 10)]
+		}
+
+		row.Name = strings.TrimSpace(s.Find(".market_listing_item_name").First().Text())
+		row.GameName = strings.TrimSpace(s.Find(".market_listing_game_name").First().Text())
+
+		who := s.Find(".market_listing_whoactedwith")
+		if link := who.Find("a").First(); link.Length() != 0 {
+			row.PartnerURL, _ = link.Attr("href")
+			row.PartnerName = strings.TrimSpace(link.Text())
+			if m := profileLinkExp.FindStringSubmatch(row.PartnerURL); m != nil {
+				id, _ := strconv.ParseUint(m[1], 10, 64)
+				row.Partner = SteamID(id)
+			}
+		}
+
+		switch gain := strings.TrimSpace(s.Find(".market_listing_gainorloss").Text()); {
+		case gain == "+":
+			row.Action = MarketActionPurchased
+		case gain == "-":
+			row.Action = MarketActionSold
+		case strings.Contains(strings.ToLower(who.Text()), "cancel"):
+			row.Action = MarketActionCanceled
+		case strings.Contains(strings.ToLower(who.Text()), "created"):
+			row.Action = MarketActionListed
+		}
+
+		row.PriceText = strings.TrimSpace(s.Find(".market_listing_price").First().Text())
+		if price, id, err := currency.Parse(row.PriceText); err == nil && price > 0 {
+			row.Price = uint64(price)
+			row.Fee = row.Price - marketReceived(row.Price)
+			row.Currency = id
+		}
+
+		row.DateText = strings.TrimSpace(s.Find(".market_listing_listed_date").First().Text())
+		row.Date = guessMarketDate(row.DateText, now)
+
+		rows = append(rows, row)
+	})
+
+	return rows, nil
+}
+
+// guessMarketDate parses a history date, taking the latest year that does
+// not put it in the future.
+func guessMarketDate(text string, now time.Time) time.Time {
+	for _, layout := range marketHistoryDateLayouts {
+		t, err := time.ParseInLocation(layout, text, now.Location())
+		if err != nil {
+			continue
+		}
+
+		t = t.AddDate(now.Year(), 0, 0)
+		if t.After(now) {
+			t = t.AddDate(-1, 0, 0)
+		}
+
+		return t
+	}
+
+	return time.Time{}
+}
+
+// marketFees is what the Steam fee (5%) and the default publisher fee
+// (10%) add to a listing the seller receives received cents for, each at
+// least a cent.
+func marketFees(received uint64) uint64 {
+	return max(received*5/100, 1) + max(received*10/100, 1)
+}
+
+// marketReceived is what the seller gets of a buyer price of paid cents.
+func marketReceived(paid uint64) uint64 {
+	if paid < 3 {
+		return 0
+	}
+
+	received := paid * 100 / 115
+	for received > 0 && received+marketFees(received) > paid {
+		received--
+	}
+
+	for received+1+marketFees(received+1) <= paid {
+		received++
+	}
+
+	return received
+}
@@ -0,0 +1,223 @@
+package steam
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	myListingsURL    = "/market/mylistings/render/?"
+	removeListingURL = "/market/removelisting/%d"
+
+	myListingsPageSize = 100
+
+	// Listings are removed one request at a time, removeListingsBatch of
+	// them removeListingInterval apart before a removeListingsPause, which
+	// keeps long runs clear of the market rate limit.
+	removeListingsBatch   = 25
+	removeListingInterval = 500 * time.Millisecond
+	removeListingsPause   = 10 * time.Second
+)
+
+// MarketListing is one of the sell listings of the account, see
+// GetMyMarketListings.
+type MarketListing struct {
+	ID      uint64
+	Created time.Time
+	Price   uint64 // what the seller receives, in cents of the listing currency
+	Fee     uint64 // what the buyer pays on top of Price
+
+	AppID          uint32
+	ContextID      uint64
+	AssetID        uint64
+	Amount         uint64
+	Name           string
+	MarketHashName string
+
+	// AwaitingConfirmation is set while the listing waits for its mobile
+	// or email confirmation, OnHold while the item is held back.
+	AwaitingConfirmation bool
+	OnHold               bool
+}
+
+type myListing struct {
+	ID      uint64 `json:"listingid,string"`
+	Created int64  `json:"time_created"`
+	Price   uint64 `json:"original_price"`
+	Fee     uint64 `json:"fee"`
+	Asset   struct {
+		AppID          uint32     `json:"appid"`
+		ContextID      uint64     `json:"contextid,string"`
+		AssetID        uint64     `json:"id,string"`
+		Amount         flexNumber `json:"amount"`
+		Name           string     `json:"name"`
+		MarketHashName string     `json:"market_hash_name"`
+	} `json:"asset"`
+}
+
+func (l *myListing) listing() *MarketListing {
+	return &MarketListing{
+		ID:             l.ID,
+		Created:        time.Unix(l.Created, 0),
+		Price:          l.Price,
+		Fee:            l.Fee,
+		AppID:          l.Asset.AppID,
+		ContextID:      l.Asset.ContextID,
+		AssetID:        l.Asset.AssetID,
+		Amount:         uint64(l.Asset.Amount),
+		Name:           l.Asset.Name,
+		MarketHashName: l.Asset.MarketHashName,
+	}
+}
+
+type myListingsResponse struct {
+	Success    bool         `json:"success"`
+	TotalCount int          `json:"total_count"`
+	Listings   []*myListing `json:"listings"`
+	OnHold     []*myListing `json:"listings_on_hold"`
+	ToConfirm  []*myListing `json:"listings_to_confirm"`
+}
+
+func (session *Session) getMyListingsPage(start int) (*myListingsResponse, error) {
+	resp, err := session.client.Get(session.CommunityBaseURL() + myListingsURL + url.Values{
+		"norender": {"1"},
+		"start":    {strconv.Itoa(start)},
+		"count":    {strconv.Itoa(myListingsPageSize)},
+	}.Encode())
+	if resp != nil {
+		defer func(Body io.ReadCloser) {
+			err := Body.Close()
+			if err != nil {
+				return
+			}
+		}(resp.Body)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("http error: %d", resp.StatusCode)
+	}
+
+	var response myListingsResponse
+	if err = session.readJSON(resp, &response); err != nil {
+		return nil, err
+	}
+
+	if !response.Success {
+		return nil, ErrInvalidResponse
+	}
+
+	return &response, nil
+}
+
+// GetMyMarketListings returns every sell listing of the account: the
+// active ones, those on hold and those awaiting confirmation.
+func (session *Session) GetMyMarketListings() ([]*MarketListing, error) {
+	var listings []*MarketListing
+	seen := make(map[uint64]bool)
+	add := func(page []*myListing, onHold, toConfirm bool) {
+		for _, l := range page {
+			if seen[l.ID] {
+				continue
+			}
+
+			seen[l.ID] = true
+			listing := l.listing()
+			listing.OnHold = onHold
+			listing.AwaitingConfirmation = toConfirm
+			listings = append(listings, listing)
+		}
+	}
+
+	for start := 0; ; {
+		response, err := session.getMyListingsPage(start)
+		if err != nil {
+			return nil, err
+		}
+
+		// The listings on hold and to confirm come whole with every page.
+		add(response.Listings, false, false)
+		add(response.OnHold, true, false)
+		add(response.ToConfirm, false, true)
+
+		start += len(response.Listings)
+		if len(response.Listings) == 0 || start >= response.TotalCount {
+			return listings, nil
+		}
+	}
+}
+
+// RemoveMarketListing takes the sell listing id off the market, the item
+// returns to the inventory.
+func (session *Session) RemoveMarketListing(id uint64) error {
+	if err := session.checkWritable(); err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(
+		http.MethodPost,
+		session.CommunityBaseURL()+fmt.Sprintf(removeListingURL, id),
+		strings.NewReader(url.Values{"sessionid": {session.sessionID}}.Encode()),
+	)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Add("Referer", session.CommunityBaseURL()+"/market/")
+	req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := session.client.Do(req)
+	if resp != nil {
+		defer func(Body io.ReadCloser) {
+			err := Body.Close()
+			if err != nil {
+				return
+			}
+		}(resp.Body)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("cannot remove listing %d: %d", id, resp.StatusCode)
+	}
+
+	return nil
+}
+
+// ListingRemoval is the outcome of removing one listing, see
+// RemoveMarketListings.
+type ListingRemoval struct {
+	ID  uint64
+	Err error
+}
+
+// RemoveMarketListings removes the listings ids one by one, spaced out to
+// stay under the market rate limit, and carries on past the ones that
+// fail.  Results are in the order of ids.
+func (session *Session) RemoveMarketListings(ids []uint64) []*ListingRemoval {
+	results := make([]*ListingRemoval, len(ids))
+	for i, id := range ids {
+		if i != 0 {
+			if i%removeListingsBatch == 0 {
+				time.Sleep(removeListingsPause)
+			} else {
+				time.Sleep(removeListingInterval)
+			}
+		}
+
+		results[i] = &ListingRemoval{ID: id, Err: session.RemoveMarketListing(id)}
+	}
+
+	session.InvalidateInventory(session.oauth.SteamID)
+	return results
+}
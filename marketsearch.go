@@ -0,0 +1,154 @@
+package steam
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+const marketSearchURL = "/market/search/render/?"
+
+// Market search sort columns.
+const (
+	MarketSortPopular  = "popular"
+	MarketSortPrice    = "price"
+	MarketSortQuantity = "quantity"
+	MarketSortName     = "name"
+)
+
+// MarketSearchQuery mirrors the market search page.  Tags maps a tag
+// category to the internal tag names to match, e.g.
+// {"Type": {"tag_CSGO_Type_Rifle"}}.
+type MarketSearchQuery struct {
+	AppID              uint64
+	Query              string
+	Tags               map[string][]string
+	SearchDescriptions bool
+	SortColumn         string // one of the MarketSort* constants, defaults to popular
+	SortDesc           bool
+	Start              int
+	Count              int  // page size, Steam caps it at 100
+	AllPages           bool // keep fetching pages from Start until the end
+}
+
+type MarketSearchResult struct {
+	Name          string        `json:"name"`
+	HashName      string        `json:"hash_name"`
+	Quantity      uint64        `json:"sell_listings"`
+	Price         uint64        `json:"sell_price"` // lowest listing in cents of the session currency
+	PriceText     string        `json:"sell_price_text"`
+	SalePriceText string        `json:"sale_price_text"` // what the seller receives
+	AppName       string        `json:"app_name"`
+	Desc          *EconItemDesc `json:"asset_description"`
+}
+
+// IconURL is the item icon at the given size, see EconItemDesc.ImageURL.
+func (result *MarketSearchResult) IconURL(size string) string {
+	if result.Desc == nil {
+		return ""
+	}
+
+	return result.Desc.ImageURL(size)
+}
+
+type marketSearchResponse struct {
+	Success    bool                  `json:"success"`
+	Start      int                   `json:"start"`
+	PageSize   int                   `json:"pagesize"`
+	TotalCount int                   `json:"total_count"`
+	Results    []*MarketSearchResult `json:"results"`
+}
+
+func (query *MarketSearchQuery) values(start int) url.Values {
+	params := url.Values{
+		"norender": {"1"},
+		"query":    {query.Query},
+		"start":    {strconv.Itoa(start)},
+		"count":    {"100"},
+		"sort_dir": {"asc"},
+	}
+
+	if query.Count != 0 {
+		params.Set("count", strconv.Itoa(query.Count))
+	}
+
+	if query.AppID != 0 {
+		params.Set("appid", strconv.FormatUint(query.AppID, 10))
+	}
+
+	if query.SearchDescriptions {
+		params.Set("search_descriptions", "1")
+	}
+
+	if len(query.SortColumn) != 0 {
+		params.Set("sort_column", query.SortColumn)
+	} else {
+		params.Set("sort_column", MarketSortPopular)
+	}
+
+	if query.SortDesc {
+		params.Set("sort_dir", "desc")
+	}
+
+	for category, tags := range query.Tags {
+		key := fmt.Sprintf("category_%d_%s[]", query.AppID, category)
+		for _, tag := range tags {
+			params.Add(key, tag)
+		}
+	}
+
+	return params
+}
+
+func (session *Session) searchMarketPage(query *MarketSearchQuery, start int) (*marketSearchResponse, error) {
+	resp, err := session.client.Get(session.CommunityBaseURL() + marketSearchURL + query.values(start).Encode())
+	if resp != nil {
+		defer func(Body io.ReadCloser) {
+			err := Body.Close()
+			if err != nil {
+				return
+			}
+		}(resp.Body)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("http error: %d", resp.StatusCode)
+	}
+
+	var response marketSearchResponse
+	if err = session.readJSON(resp, &response); err != nil {
+		return nil, err
+	}
+
+	if !response.Success {
+		return nil, ErrInvalidResponse
+	}
+
+	return &response, nil
+}
+
+// SearchMarket returns the matching listings and the total number of
+// matches, only the page at query.Start unless query.AllPages is set.
+func (session *Session) SearchMarket(query MarketSearchQuery) ([]*MarketSearchResult, int, error) {
+	results := []*MarketSearchResult{}
+	start := query.Start
+
+	for {
+		response, err := session.searchMarketPage(&query, start)
+		if err != nil {
+			return results, 0, err
+		}
+
+		results = append(results, response.Results...)
+		start += len(response.Results)
+
+		if !query.AllPages || len(response.Results) == 0 || start >= response.TotalCount {
+			return results, response.TotalCount, nil
+		}
+	}
+}
@@ -0,0 +1,294 @@
+package steam
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Metrics receives the measurements of a Session, see SetMetrics.  The
+// methods are called from the goroutines making the requests and must not
+// block.
+type Metrics interface {
+	// ObserveRequest is called once per request made through the session
+	// client.  status is 0 and eresult empty when the request failed.
+	ObserveRequest(endpoint string, status int, eresult string, latency time.Duration)
+
+	// ObserveConfirmation is called with the outcome of every confirmation
+	// answer sent to Steam.
+	ObserveConfirmation(kind ConfirmationType, success bool)
+
+	// ObservePollLag is called by OfferWatcher.Run with how late each poll
+	// started compared to its interval.
+	ObservePollLag(lag time.Duration)
+}
+
+type nopMetrics struct{}
+
+func (nopMetrics) ObserveRequest(string, int, string, time.Duration) {}
+func (nopMetrics) ObserveConfirmation(ConfirmationType, bool)        {}
+func (nopMetrics) ObservePollLag(time.Duration)                      {}
+
+type metricsTransport struct {
+	session *Session
+	next    http.RoundTripper
+
+	mu      sync.RWMutex
+	metrics Metrics // nil when reporting stopped
+}
+
+func (t *metricsTransport) hook() Metrics {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.metrics
+}
+
+func (t *metricsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	metrics := t.hook()
+	if metrics == nil {
+		return t.next.RoundTrip(req)
+	}
+
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	endpoint := endpointName(t.session.withDefaultHost(req))
+	if err != nil {
+		metrics.ObserveRequest(endpoint, 0, "", time.Since(start))
+		return resp, err
+	}
+
+	metrics.ObserveRequest(endpoint, resp.StatusCode, resp.Header.Get("x-eresult"), time.Since(start))
+	return resp, nil
+}
+
+// SetMetrics reports the requests, confirmations and offer polls of the
+// session to metrics, nil stops reporting.  Like the other transports the
+// hook is installed once and shared with clones, setting it again swaps
+// the metrics in place.
+func (session *Session) SetMetrics(metrics Metrics) {
+	session.metrics = metrics
+
+	if t := session.requestMetrics; t != nil {
+		t.mu.Lock()
+		t.metrics = metrics
+		t.mu.Unlock()
+		return
+	}
+
+	if metrics == nil {
+		return
+	}
+
+	next := session.client.Transport
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	session.requestMetrics = &metricsTransport{session: session, next: next, metrics: metrics}
+	session.client.Transport = session.requestMetrics
+}
+
+func (session *Session) metricsHook() Metrics {
+	if session.metrics == nil {
+		return nopMetrics{}
+	}
+
+	return session.metrics
+}
+
+// DefaultLatencyBuckets are the upper bounds, in seconds, of the
+// PrometheusMetrics latency histograms.
+var DefaultLatencyBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+
+type histogram struct {
+	counts []uint64 // per bucket, not cumulative
+	sum    float64
+	count  uint64
+}
+
+func (h *histogram) observe(buckets []float64, v float64) {
+	if h.counts == nil {
+		h.counts = make([]uint64, len(buckets))
+	}
+
+	for i, bound := range buckets {
+		if v <= bound {
+			h.counts[i]++
+			break
+		}
+	}
+
+	h.sum += v
+	h.count++
+}
+
+// PrometheusMetrics implements Metrics and serves the collected values in
+// the Prometheus text exposition format, mount it on /metrics.  Series are
+// prefixed with Namespace, "steam" when empty.
+type PrometheusMetrics struct {
+	Namespace string
+	Buckets   []float64 // DefaultLatencyBuckets when nil
+
+	mu            sync.Mutex
+	requests      map[[2]string]uint64 // endpoint, status
+	eresults      map[[2]string]uint64 // endpoint, eresult
+	latencies     map[string]*histogram
+	confirmations map[[2]string]uint64 // type, outcome
+	pollLag       histogram
+}
+
+// NewPrometheusMetrics returns an empty PrometheusMetrics.
+func NewPrometheusMetrics(namespace string) *PrometheusMetrics {
+	return &PrometheusMetrics{Namespace: namespace}
+}
+
+func (m *PrometheusMetrics) buckets() []float64 {
+	if m.Buckets == nil {
+		return DefaultLatencyBuckets
+	}
+
+	return m.Buckets
+}
+
+func (m *PrometheusMetrics) ObserveRequest(endpoint string, status int, eresult string, latency time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.requests == nil {
+		m.requests = make(map[[2]string]uint64)
+		m.eresults = make(map[[2]string]uint64)
+		m.latencies = make(map[string]*histogram)
+	}
+
+	code := "error"
+	if status != 0 {
+		code = strconv.Itoa(status)
+	}
+	m.requests[[2]string{endpoint, code}]++
+
+	if len(eresult) != 0 {
+		m.eresults[[2]string{endpoint, eresult}]++
+	}
+
+	h := m.latencies[endpoint]
+	if h == nil {
+		h = &histogram{}
+		m.latencies[endpoint] = h
+	}
+	h.observe(m.buckets(), latency.Seconds())
+}
+
+func (m *PrometheusMetrics) ObserveConfirmation(kind ConfirmationType, success bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.confirmations == nil {
+		m.confirmations = make(map[[2]string]uint64)
+	}
+
+	outcome := "failure"
+	if success {
+		outcome = "success"
+	}
+	m.confirmations[[2]string{kind.String(), outcome}]++
+}
+
+func (m *PrometheusMetrics) ObservePollLag(lag time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.pollLag.observe(m.buckets(), lag.Seconds())
+}
+
+func sortedKeys[K [2]string | string, V any](values map[K]V, less func(a, b K) bool) []K {
+	keys := make([]K, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return less(keys[i], keys[j]) })
+	return keys
+}
+
+func lessPair(a, b [2]string) bool {
+	if a[0] != b[0] {
+		return a[0] < b[0]
+	}
+
+	return a[1] < b[1]
+}
+
+func writeHistogram(b *strings.Builder, name, labels string, buckets []float64, h *histogram) {
+	sep := ""
+	if len(labels) != 0 {
+		sep = ","
+	}
+
+	var cumulative uint64
+	for i, bound := range buckets {
+		if h.counts != nil {
+			cumulative += h.counts[i]
+		}
+		fmt.Fprintf(b, "%s_bucket{%s%sle=\"%s\"} %d\n", name, labels, sep, strconv.FormatFloat(bound, 'g', -1, 64), cumulative)
+	}
+	fmt.Fprintf(b, "%s_bucket{%s%sle=\"+Inf\"} %d\n", name, labels, sep, h.count)
+
+	if len(labels) != 0 {
+		labels = "{" + labels + "}"
+	}
+	fmt.Fprintf(b, "%s_sum%s %s\n", name, labels, strconv.FormatFloat(h.sum, 'g', -1, 64))
+	fmt.Fprintf(b, "%s_count%s %d\n", name, labels, h.count)
+}
+
+// String renders the metrics in the Prometheus text format.
+func (m *PrometheusMetrics) String() string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	ns := m.Namespace
+	if len(ns) == 0 {
+		ns = "steam"
+	}
+
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# HELP %s_requests_total Requests made, by endpoint and HTTP status.\n", ns)
+	fmt.Fprintf(&b, "# TYPE %s_requests_total counter\n", ns)
+	for _, key := range sortedKeys(m.requests, lessPair) {
+		fmt.Fprintf(&b, "%s_requests_total{endpoint=%q,status=%q} %d\n", ns, key[0], key[1], m.requests[key])
+	}
+
+	fmt.Fprintf(&b, "# HELP %s_eresults_total EResults reported in x-eresult, by endpoint.\n", ns)
+	fmt.Fprintf(&b, "# TYPE %s_eresults_total counter\n", ns)
+	for _, key := range sortedKeys(m.eresults, lessPair) {
+		fmt.Fprintf(&b, "%s_eresults_total{endpoint=%q,eresult=%q} %d\n", ns, key[0], key[1], m.eresults[key])
+	}
+
+	fmt.Fprintf(&b, "# HELP %s_request_duration_seconds Request latency, by endpoint.\n", ns)
+	fmt.Fprintf(&b, "# TYPE %s_request_duration_seconds histogram\n", ns)
+	for _, endpoint := range sortedKeys(m.latencies, func(a, b string) bool { return a < b }) {
+		writeHistogram(&b, ns+"_request_duration_seconds", fmt.Sprintf("endpoint=%q", endpoint), m.buckets(), m.latencies[endpoint])
+	}
+
+	fmt.Fprintf(&b, "# HELP %s_confirmations_total Confirmation answers, by type and outcome.\n", ns)
+	fmt.Fprintf(&b, "# TYPE %s_confirmations_total counter\n", ns)
+	for _, key := range sortedKeys(m.confirmations, lessPair) {
+		fmt.Fprintf(&b, "%s_confirmations_total{type=%q,outcome=%q} %d\n", ns, key[0], key[1], m.confirmations[key])
+	}
+
+	fmt.Fprintf(&b, "# HELP %s_offer_poll_lag_seconds Delay of offer polls past their interval.\n", ns)
+	fmt.Fprintf(&b, "# TYPE %s_offer_poll_lag_seconds histogram\n", ns)
+	writeHistogram(&b, ns+"_offer_poll_lag_seconds", "", m.buckets(), &m.pollLag)
+
+	return b.String()
+}
+
+// ServeHTTP serves the metrics to a Prometheus scraper.
+func (m *PrometheusMetrics) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_, _ = w.Write([]byte(m.String()))
+}
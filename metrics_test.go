@@ -0,0 +1,46 @@
+package steam
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type countingMetrics struct {
+	nopMetrics
+	requests int32
+}
+
+func (m *countingMetrics) ObserveRequest(string, int, string, time.Duration) {
+	atomic.AddInt32(&m.requests, 1)
+}
+
+func TestSetMetrics(t *testing.T) {
+	session := newTestSession(t, answer(`{}`))
+	get := func() {
+		t.Helper()
+		resp, err := session.client.Get("https://steamcommunity.com/market/")
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+
+	first, second := &countingMetrics{}, &countingMetrics{}
+	session.SetMetrics(first)
+	// Another transport on top must not make SetMetrics wrap again.
+	session.EnableKeyQuota(QuotaConfig{})
+	session.SetMetrics(second)
+	get()
+
+	if first.requests != 0 || second.requests != 1 {
+		t.Errorf("got %d and %d requests, want 0 and 1", first.requests, second.requests)
+	}
+
+	session.SetMetrics(nil)
+	get()
+
+	if second.requests != 1 {
+		t.Errorf("got %d requests after stopping, want 1", second.requests)
+	}
+}
@@ -0,0 +1,59 @@
+package steam
+
+import (
+	"encoding/json"
+	"strconv"
+)
+
+const notificationCountsURL = "/actions/GetNotificationCounts"
+
+// NotificationCounts are the unread notifications shown in the community
+// header, cheap to poll before anything more expensive.
+type NotificationCounts struct {
+	TradeOffers        int
+	GameTurns          int // async games waiting for a move
+	ModeratorMessages  int
+	Comments           int
+	Items              int
+	Invites            int
+	Gifts              int
+	Chat               int // offline chat messages
+	HelpRequestReplies int
+	AccountAlerts      int
+}
+
+// GetNotificationCounts returns the unread notification counts.
+func (session *Session) GetNotificationCounts() (*NotificationCounts, error) {
+	body, err := session.getPage(session.CommunityBaseURL() + notificationCountsURL)
+	if err != nil {
+		return nil, err
+	}
+
+	var response struct {
+		Notifications map[string]int `json:"notifications"`
+	}
+	if err = json.Unmarshal(body, &response); err != nil {
+		return nil, err
+	}
+
+	if response.Notifications == nil {
+		return nil, ErrInvalidResponse
+	}
+
+	n := func(kind int) int {
+		return response.Notifications[strconv.Itoa(kind)]
+	}
+
+	return &NotificationCounts{
+		TradeOffers:        n(1),
+		GameTurns:          n(2),
+		ModeratorMessages:  n(3),
+		Comments:           n(4),
+		Items:              n(5),
+		Invites:            n(6),
+		Gifts:              n(8),
+		Chat:               n(9),
+		HelpRequestReplies: n(10),
+		AccountAlerts:      n(11),
+	}, nil
+}
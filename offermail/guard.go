@@ -0,0 +1,170 @@
+package offermail
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"html"
+	"io"
+	"mime/quotedprintable"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var (
+	guardCodeExp    = regexp.MustCompile(`(?m)^\s*([A-Z0-9]{5})\s*$`)
+	confirmLinkExp  = regexp.MustCompile(`https://(?:store\.steampowered\.com|steamcommunity\.com|help\.steampowered\.com)/[^\s"'<>]*(?:confirm|verif|validate)[^\s"'<>]*`)
+	internalDateExp = regexp.MustCompile(`INTERNALDATE "([^"]+)"`)
+	tagExp          = regexp.MustCompile(`<[^>]+>`)
+
+	ErrNoMail = errors.New("no matching mail")
+)
+
+// GuardMailbox reads Steam Guard codes and confirmation links from a
+// mailbox over IMAP, it implements steam.EmailCodeProvider:
+//
+//	session.SetEmailCodeProvider(&offermail.GuardMailbox{
+//		Addr: "imap.example.com:993", Username: u, Password: p,
+//	}, 0)
+//
+// When several accounts share the mailbox, mails are told apart by the
+// account name Steam greets with.
+type GuardMailbox struct {
+	Addr      string // host:port of the IMAP over TLS server
+	Username  string
+	Password  string
+	Mailbox   string        // defaults to INBOX
+	Interval  time.Duration // between checks, defaults to 10 seconds
+	TLSConfig *tls.Config
+}
+
+type mail struct {
+	body string
+}
+
+// decodeBody undoes the quoted-printable encoding and the entities of a
+// mail body, as far as finding codes and links goes.
+func decodeBody(body string) string {
+	decoded, err := io.ReadAll(quotedprintable.NewReader(strings.NewReader(body)))
+	if err != nil {
+		body = strings.ReplaceAll(body, "=\r\n", "")
+		body = strings.ReplaceAll(body, "=\n", "")
+	} else {
+		body = string(decoded)
+	}
+
+	return html.UnescapeString(body)
+}
+
+// recent returns the Steam mails received after since, newest first.
+func (m *GuardMailbox) recent(cn *conn, since time.Time) ([]*mail, error) {
+	if _, err := cn.command("NOOP"); err != nil {
+		return nil, err
+	}
+
+	/* SINCE only has a day resolution and ignores time zones, the exact
+	 * time is checked on INTERNALDATE.  */
+	responses, err := cn.command("UID SEARCH FROM %s SINCE %s", quote(SteamSender), since.AddDate(0, 0, -1).Format("2-Jan-2006"))
+	if err != nil {
+		return nil, err
+	}
+
+	var uids []string
+	for _, resp := range responses {
+		if strings.HasPrefix(resp.line, "* SEARCH") {
+			uids = append(uids, strings.Fields(resp.line)[2:]...)
+		}
+	}
+
+	var mails []*mail
+	for i := len(uids) - 1; i >= 0; i-- {
+		responses, err = cn.command("UID FETCH %s (INTERNALDATE BODY.PEEK[TEXT])", uids[i])
+		if err != nil {
+			return nil, err
+		}
+
+		for _, resp := range responses {
+			d := internalDateExp.FindStringSubmatch(resp.line)
+			if d == nil || len(resp.literals) == 0 {
+				continue
+			}
+
+			date, err := time.Parse("2-Jan-2006 15:04:05 -0700", strings.TrimSpace(d[1]))
+			if err != nil || date.Before(since.Truncate(time.Second)) {
+				continue
+			}
+
+			mails = append(mails, &mail{body: decodeBody(resp.literals[0])})
+		}
+	}
+
+	return mails, nil
+}
+
+// find waits for a mail to accountName match finds something in, the
+// newest one first.
+func (m *GuardMailbox) find(ctx context.Context, accountName string, since time.Time, match func(string) string) (string, error) {
+	cn, err := dial(ctx, m.Addr, m.Username, m.Password, m.Mailbox, m.TLSConfig)
+	if err != nil {
+		return "", err
+	}
+	defer cn.close()
+	defer cn.closeOnDone(ctx)()
+
+	interval := m.Interval
+	if interval == 0 {
+		interval = 10 * time.Second
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		mails, err := m.recent(cn, since)
+		if err != nil {
+			if ctx.Err() != nil {
+				return "", errors.Join(ErrNoMail, ctx.Err())
+			}
+
+			return "", err
+		}
+
+		for _, mail := range mails {
+			if len(accountName) != 0 && !strings.Contains(strings.ToLower(mail.body), strings.ToLower(accountName)) {
+				continue
+			}
+
+			if found := match(mail.body); len(found) != 0 {
+				return found, nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return "", errors.Join(ErrNoMail, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// GuardCode waits for the Steam Guard code mailed to accountName after
+// since.
+func (m *GuardMailbox) GuardCode(ctx context.Context, accountName string, since time.Time) (string, error) {
+	return m.find(ctx, accountName, since, func(body string) string {
+		// The code sits alone in a table cell of the HTML part.
+		if c := guardCodeExp.FindStringSubmatch(tagExp.ReplaceAllString(body, "\n")); c != nil {
+			return c[1]
+		}
+
+		return ""
+	})
+}
+
+// ConfirmationLink waits for a confirmation link mailed to accountName
+// after since, e.g. the one of a Web API key registration.
+func (m *GuardMailbox) ConfirmationLink(ctx context.Context, accountName string, since time.Time) (string, error) {
+	return m.find(ctx, accountName, since, func(body string) string {
+		return confirmLinkExp.FindString(body)
+	})
+}
@@ -0,0 +1,282 @@
+// Package offermail watches a mailbox over IMAP for the "new trade offer"
+// notifications Steam sends and reports the offer IDs they link to, so a
+// bot can poll that one offer right away instead of polling all offers
+// often:
+//
+//	w := &offermail.Watcher{Addr: "imap.example.com:993", Username: u, Password: p}
+//	err := w.Run(ctx, func(offerID uint64) {
+//		offer, err := session.GetTradeOffer(offerID)
+//		...
+//	})
+//
+// GuardMailbox reads Steam Guard codes from the same kind of mailbox.
+//
+// Only plain IMAP over TLS with LOGIN authentication is supported, and
+// notifications are marked as read once reported.
+package offermail
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// SteamSender is the address Steam notifications come from.
+	SteamSender = "noreply@steampowered.com"
+
+	defaultSubject = "trade offer"
+
+	// commandTimeout bounds every command, a server that stops answering
+	// fails the check instead of hanging it.
+	commandTimeout = time.Minute
+)
+
+var (
+	offerLinkExp = regexp.MustCompile(`steamcommunity\.com/tradeoffer/(\d+)`)
+	literalExp   = regexp.MustCompile(`\{(\d+)\}$`)
+
+	ErrCommandFailed = errors.New("imap command failed")
+)
+
+type Watcher struct {
+	Addr      string // host:port of the IMAP over TLS server
+	Username  string
+	Password  string
+	Mailbox   string        // defaults to INBOX
+	Subject   string        // subject filter, defaults to "trade offer"
+	Interval  time.Duration // defaults to 15 seconds
+	TLSConfig *tls.Config
+}
+
+type conn struct {
+	c   net.Conn
+	r   *bufio.Reader
+	tag int
+}
+
+// response is one untagged response line with its literals appended.
+type response struct {
+	line     string
+	literals []string
+}
+
+func quote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+func (c *conn) readLine() (string, error) {
+	line, err := c.r.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// command sends a command and collects the untagged responses until the
+// tagged completion.
+func (c *conn) command(format string, args ...interface{}) ([]*response, error) {
+	c.tag++
+	tag := "a" + strconv.Itoa(c.tag)
+
+	if err := c.c.SetDeadline(time.Now().Add(commandTimeout)); err != nil {
+		return nil, err
+	}
+
+	if _, err := fmt.Fprintf(c.c, "%s %s\r\n", tag, fmt.Sprintf(format, args...)); err != nil {
+		return nil, err
+	}
+
+	var responses []*response
+	for {
+		line, err := c.readLine()
+		if err != nil {
+			return nil, err
+		}
+
+		if strings.HasPrefix(line, tag+" ") {
+			if !strings.HasPrefix(line[len(tag)+1:], "OK") {
+				return nil, fmt.Errorf("%w: %s", ErrCommandFailed, line)
+			}
+
+			return responses, nil
+		}
+
+		resp := &response{line: line}
+		for {
+			m := literalExp.FindStringSubmatch(line)
+			if m == nil {
+				break
+			}
+
+			n, _ := strconv.Atoi(m[1])
+			literal := make([]byte, n)
+			if _, err = io.ReadFull(c.r, literal); err != nil {
+				return nil, err
+			}
+			resp.literals = append(resp.literals, string(literal))
+
+			if line, err = c.readLine(); err != nil {
+				return nil, err
+			}
+			resp.line += line
+		}
+
+		responses = append(responses, resp)
+	}
+}
+
+func (w *Watcher) dial(ctx context.Context) (*conn, error) {
+	return dial(ctx, w.Addr, w.Username, w.Password, w.Mailbox, w.TLSConfig)
+}
+
+func dial(ctx context.Context, addr, username, password, mailbox string, config *tls.Config) (*conn, error) {
+	dialer := &tls.Dialer{Config: config}
+	c, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	return open(c, username, password, mailbox)
+}
+
+// open reads the greeting, logs in and selects mailbox on c.
+func open(c net.Conn, username, password, mailbox string) (*conn, error) {
+	cn := &conn{c: c, r: bufio.NewReader(c)}
+	c.SetDeadline(time.Now().Add(commandTimeout))
+	if _, err := cn.readLine(); err != nil { // greeting
+		c.Close()
+		return nil, err
+	}
+
+	if len(mailbox) == 0 {
+		mailbox = "INBOX"
+	}
+
+	if _, err := cn.command("LOGIN %s %s", quote(username), quote(password)); err != nil {
+		c.Close()
+		return nil, err
+	}
+
+	if _, err := cn.command("SELECT %s", quote(mailbox)); err != nil {
+		c.Close()
+		return nil, err
+	}
+
+	return cn, nil
+}
+
+// close logs out and closes the connection.
+func (c *conn) close() {
+	c.command("LOGOUT")
+	c.c.Close()
+}
+
+// closeOnDone closes the connection once ctx is done, so that a command
+// waiting for the server returns right away.  The returned function stops
+// it.
+func (c *conn) closeOnDone(ctx context.Context) func() bool {
+	return context.AfterFunc(ctx, func() {
+		c.c.Close()
+	})
+}
+
+// offerID finds the offer link in a notification body, undoing the
+// quoted-printable soft line breaks it may be split across.
+func offerID(body string) uint64 {
+	body = strings.ReplaceAll(body, "=\r\n", "")
+	body = strings.ReplaceAll(body, "=\n", "")
+
+	m := offerLinkExp.FindStringSubmatch(body)
+	if m == nil {
+		return 0
+	}
+
+	id, _ := strconv.ParseUint(m[1], 10, 64)
+	return id
+}
+
+func (w *Watcher) check(cn *conn, notify func(uint64)) error {
+	subject := w.Subject
+	if len(subject) == 0 {
+		subject = defaultSubject
+	}
+
+	responses, err := cn.command("UID SEARCH UNSEEN FROM %s SUBJECT %s", quote(SteamSender), quote(subject))
+	if err != nil {
+		return err
+	}
+
+	var uids []string
+	for _, resp := range responses {
+		if strings.HasPrefix(resp.line, "* SEARCH") {
+			uids = append(uids, strings.Fields(resp.line)[2:]...)
+		}
+	}
+
+	for _, uid := range uids {
+		responses, err = cn.command("UID FETCH %s BODY.PEEK[TEXT]", uid)
+		if err != nil {
+			return err
+		}
+
+		for _, resp := range responses {
+			for _, literal := range resp.literals {
+				if id := offerID(literal); id != 0 {
+					notify(id)
+					break
+				}
+			}
+		}
+
+		if _, err = cn.command(`UID STORE %s +FLAGS (\Seen)`, uid); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Run checks the mailbox every Interval and calls notify with the offer ID
+// of every new notification until ctx is done or the connection fails.
+func (w *Watcher) Run(ctx context.Context, notify func(offerID uint64)) error {
+	cn, err := w.dial(ctx)
+	if err != nil {
+		return err
+	}
+	defer cn.close()
+	defer cn.closeOnDone(ctx)()
+
+	interval := w.Interval
+	if interval == 0 {
+		interval = 15 * time.Second
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err = w.check(cn, notify); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,191 @@
+package offermail
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeIMAP answers the commands sent on the returned connection with what
+// reply returns for them, the tag is prepended to the last line.  Commands
+// are recorded in order.
+func fakeIMAP(t *testing.T, reply func(command string) string) (*conn, *[]string) {
+	t.Helper()
+
+	client, server := net.Pipe()
+	t.Cleanup(func() {
+		client.Close()
+		server.Close()
+	})
+
+	var commands []string
+	go func() {
+		r := bufio.NewReader(server)
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+
+			tag, command, _ := strings.Cut(strings.TrimRight(line, "\r\n"), " ")
+			commands = append(commands, command)
+
+			answer := reply(command)
+			i := strings.LastIndex(answer, "\r\n") + 2
+			if i == 1 {
+				i = 0
+			}
+			if _, err = server.Write([]byte(answer[:i] + tag + " " + answer[i:] + "\r\n")); err != nil {
+				return
+			}
+		}
+	}()
+
+	return &conn{c: client, r: bufio.NewReader(client)}, &commands
+}
+
+func TestCommand(t *testing.T) {
+	tests := []struct {
+		name     string
+		reply    string
+		lines    []string
+		literals [][]string
+		err      error
+	}{
+		{
+			name:  "untagged",
+			reply: "* SEARCH 3 7\r\nOK SEARCH completed",
+			lines: []string{"* SEARCH 3 7"},
+		},
+		{
+			name:     "literal",
+			reply:    "* 1 FETCH (UID 3 BODY[TEXT] {12}\r\nhello\r\nworld)\r\nOK FETCH completed",
+			lines:    []string{"* 1 FETCH (UID 3 BODY[TEXT] {12})"},
+			literals: [][]string{{"hello\r\nworld"}},
+		},
+		{
+			name:     "two literals",
+			reply:    "* 1 FETCH (INTERNALDATE {5}\r\nfirst BODY[TEXT] {6}\r\nsecond)\r\nOK done",
+			lines:    []string{"* 1 FETCH (INTERNALDATE {5} BODY[TEXT] {6})"},
+			literals: [][]string{{"first", "second"}},
+		},
+		{
+			name:  "failed",
+			reply: "NO [AUTHENTICATIONFAILED] invalid credentials",
+			err:   ErrCommandFailed,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cn, _ := fakeIMAP(t, func(string) string {
+				return test.reply
+			})
+
+			responses, err := cn.command("NOOP")
+			if !errors.Is(err, test.err) {
+				t.Fatalf("got %v, want %v", err, test.err)
+			}
+
+			if len(responses) != len(test.lines) {
+				t.Fatalf("got %d responses, want %d", len(responses), len(test.lines))
+			}
+
+			for i, resp := range responses {
+				if resp.line != test.lines[i] {
+					t.Errorf("got line %q, want %q", resp.line, test.lines[i])
+				}
+
+				var literals []string
+				if test.literals != nil {
+					literals = test.literals[i]
+				}
+				if strings.Join(resp.literals, "|") != strings.Join(literals, "|") {
+					t.Errorf("got literals %q, want %q", resp.literals, literals)
+				}
+			}
+		})
+	}
+}
+
+func TestOfferID(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		id   uint64
+	}{
+		{"plain", "View it: https://steamcommunity.com/tradeoffer/5678123401/", 5678123401},
+		{"soft break", "https://steamcommunity.com/trade=\r\noffer/5678123401/", 5678123401},
+		{"soft break in the ID", "https://steamcommunity.com/tradeoffer/5678=\n123401/", 5678123401},
+		{"no link", "Your Steam account: Access from new web or mobile device", 0},
+	}
+
+	for _, test := range tests {
+		if id := offerID(test.body); id != test.id {
+			t.Errorf("%s: got %d, want %d", test.name, id, test.id)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	cn, commands := fakeIMAP(t, func(command string) string {
+		switch {
+		case strings.HasPrefix(command, "UID SEARCH"):
+			return "* SEARCH 42\r\nOK SEARCH completed"
+		case strings.HasPrefix(command, "UID FETCH"):
+			body := "You have a new trade offer=\r\n https://steamcommunity.com/tradeoffer/=\r\n5678123401/"
+			return "* 1 FETCH (UID 42 BODY[TEXT] {" + strconv.Itoa(len(body)) + "}\r\n" + body + ")\r\nOK FETCH completed"
+		}
+
+		return "OK done"
+	})
+
+	var ids []uint64
+	if err := (&Watcher{}).check(cn, func(id uint64) { ids = append(ids, id) }); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(ids) != 1 || ids[0] != 5678123401 {
+		t.Errorf("got offers %v, want [5678123401]", ids)
+	}
+
+	if len(*commands) != 3 || (*commands)[2] != `UID STORE 42 +FLAGS (\Seen)` {
+		t.Errorf("got commands %q, want the notification marked as read", *commands)
+	}
+}
+
+func TestCloseOnDone(t *testing.T) {
+	// The server takes the command and never answers.
+	asked, hang := make(chan struct{}), make(chan struct{})
+	defer close(hang)
+	cn, _ := fakeIMAP(t, func(string) string {
+		close(asked)
+		<-hang
+		return "OK"
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cn.closeOnDone(ctx)()
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := cn.command("NOOP")
+		done <- err
+	}()
+
+	<-asked
+	cancel()
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Fatal("command succeeded")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("command still waiting after the context was done")
+	}
+}
@@ -0,0 +1,197 @@
+package steam
+
+import (
+	"fmt"
+	"time"
+)
+
+// OfferAction is what an OfferPolicy decided to do with an offer.
+type OfferAction int
+
+const (
+	OfferActionNone OfferAction = iota // no rule matched, leave the offer alone
+	OfferActionAccept
+	OfferActionDecline
+	OfferActionEscalate // hand over to a human, see OfferPolicy.OnEscalate
+)
+
+func (action OfferAction) String() string {
+	switch action {
+	case OfferActionAccept:
+		return "accept"
+	case OfferActionDecline:
+		return "decline"
+	case OfferActionEscalate:
+		return "escalate"
+	}
+
+	return "none"
+}
+
+// OfferEvaluator is one rule of an OfferPolicy, it returns OfferActionNone
+// when it has nothing to say about the offer and a reason otherwise.
+type OfferEvaluator interface {
+	Evaluate(event *OfferEvent) (OfferAction, string, error)
+}
+
+// OfferEvaluatorFunc turns a function into an OfferEvaluator.
+type OfferEvaluatorFunc func(event *OfferEvent) (OfferAction, string, error)
+
+func (f OfferEvaluatorFunc) Evaluate(event *OfferEvent) (OfferAction, string, error) {
+	return f(event)
+}
+
+// OfferRule is a named evaluator, the name ends up in the decision.
+type OfferRule struct {
+	Name      string
+	Evaluator OfferEvaluator
+}
+
+// OfferDecision is the outcome of an OfferPolicy, Rule is empty when the
+// default action was taken.
+type OfferDecision struct {
+	Action OfferAction
+	Rule   string
+	Reason string
+}
+
+// OfferPolicy decides what to do with incoming offers: the first rule that
+// returns an action wins, Default applies when none does.
+//
+//	policy := &steam.OfferPolicy{
+//		WithPartner: true,
+//		Rules: []steam.OfferRule{
+//			{"gifts", steam.AcceptGifts()},
+//			{"young", steam.DeclineYoungAccounts(30 * 24 * time.Hour)},
+//			{"big", steam.EscalateGivingOver(10000, nil)},
+//		},
+//		Default: steam.OfferActionDecline,
+//	}
+type OfferPolicy struct {
+	Rules   []OfferRule
+	Default OfferAction
+
+	// WithPartner fetches the partner snapshot the partner rules need.
+	WithPartner bool
+
+	// OnEscalate is called by ApplyOfferPolicy for escalated offers.
+	OnEscalate func(event *OfferEvent, decision *OfferDecision)
+}
+
+// Decide runs the rules on event.
+func (policy *OfferPolicy) Decide(event *OfferEvent) (*OfferDecision, error) {
+	for _, rule := range policy.Rules {
+		action, reason, err := rule.Evaluator.Evaluate(event)
+		if err != nil {
+			return nil, fmt.Errorf("offer rule %s: %w", rule.Name, err)
+		}
+
+		if action != OfferActionNone {
+			return &OfferDecision{Action: action, Rule: rule.Name, Reason: reason}, nil
+		}
+	}
+
+	return &OfferDecision{Action: policy.Default}, nil
+}
+
+// ApplyOfferPolicy decides on an incoming offer and carries the decision
+// out.  Accepting still goes through the give policy, see SetGivePolicy.
+func (session *Session) ApplyOfferPolicy(policy *OfferPolicy, offer *TradeOffer) (*OfferDecision, error) {
+	event, err := session.NewOfferEvent(offer, policy.WithPartner)
+	if err != nil {
+		return nil, err
+	}
+
+	decision, err := policy.Decide(event)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(decision.Rule) == 0 {
+		session.log().Infof("offer %d: %s by default", offer.ID, decision.Action)
+	} else {
+		session.log().Infof("offer %d: %s by rule %s: %s", offer.ID, decision.Action, decision.Rule, decision.Reason)
+	}
+
+	switch decision.Action {
+	case OfferActionAccept:
+		_, err = session.AcceptTradeOffer(offer.ID)
+	case OfferActionDecline:
+		err = session.DeclineTradeOffer(offer.ID)
+	case OfferActionEscalate:
+		if policy.OnEscalate != nil {
+			policy.OnEscalate(event, decision)
+		}
+	}
+
+	return decision, err
+}
+
+// AcceptGifts accepts offers that take nothing from us.
+func AcceptGifts() OfferEvaluator {
+	return OfferEvaluatorFunc(func(event *OfferEvent) (OfferAction, string, error) {
+		if len(event.Offer.SendItems) == 0 {
+			return OfferActionAccept, "gives nothing", nil
+		}
+
+		return OfferActionNone, "", nil
+	})
+}
+
+// DeclineYoungAccounts declines offers from profiles created less than
+// minAge ago, hidden creation dates count as young.  It needs the partner
+// snapshot, see OfferPolicy.WithPartner.
+func DeclineYoungAccounts(minAge time.Duration) OfferEvaluator {
+	return OfferEvaluatorFunc(func(event *OfferEvent) (OfferAction, string, error) {
+		if event.Partner == nil {
+			return OfferActionNone, "", nil
+		}
+
+		if age := event.Partner.ProfileAge(); age < minAge {
+			return OfferActionDecline, fmt.Sprintf("profile age %s", age.Truncate(time.Hour)), nil
+		}
+
+		return OfferActionNone, "", nil
+	})
+}
+
+// DeclineBannedPartners declines offers from partners with an economy ban
+// or on probation.  It needs the partner snapshot.
+func DeclineBannedPartners() OfferEvaluator {
+	return OfferEvaluatorFunc(func(event *OfferEvent) (OfferAction, string, error) {
+		if event.Partner == nil || event.Partner.Bans == nil {
+			return OfferActionNone, "", nil
+		}
+
+		if ban := event.Partner.Bans.EconomyBan; len(ban) != 0 && ban != "none" {
+			return OfferActionDecline, "economy ban " + ban, nil
+		}
+
+		return OfferActionNone, "", nil
+	})
+}
+
+// EscalateGivingOver escalates offers taking more than limit from us,
+// valued by prices or by EstimatedGive when prices is nil.
+func EscalateGivingOver(limit uint64, prices PriceProvider) OfferEvaluator {
+	return OfferEvaluatorFunc(func(event *OfferEvent) (OfferAction, string, error) {
+		total := event.Offer.EstimatedGive()
+		if prices != nil {
+			total = 0
+			for _, item := range event.Offer.SendItems {
+				value, err := prices.ItemValue(item)
+				if err != nil {
+					return OfferActionNone, "", err
+				}
+
+				total += value
+			}
+		}
+
+		if total > limit {
+			return OfferActionEscalate, fmt.Sprintf("gives %d over %d", total, limit), nil
+		}
+
+		return OfferActionNone, "", nil
+	})
+}
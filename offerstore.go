@@ -0,0 +1,151 @@
+package steam
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+var ErrOfferNotStored = errors.New("offer is not in the store")
+
+// OfferStore persists the offers an OfferWatcher has seen and their last
+// state, so that a restarted watcher does not report them again.
+// Implementations must be safe for concurrent use.
+type OfferStore interface {
+	Save(offer *TradeOffer) error
+	Load(offerID uint64) (*TradeOffer, error) // ErrOfferNotStored when unknown
+	ListByState(state TradeState) ([]*TradeOffer, error)
+}
+
+// MemoryOfferStore keeps the offers in memory, it does not survive a
+// restart and is mostly useful for tests.
+type MemoryOfferStore struct {
+	mu     sync.Mutex
+	offers map[uint64]TradeOffer
+}
+
+func NewMemoryOfferStore() *MemoryOfferStore {
+	return &MemoryOfferStore{offers: make(map[uint64]TradeOffer)}
+}
+
+func (s *MemoryOfferStore) Save(offer *TradeOffer) error {
+	s.mu.Lock()
+	s.offers[offer.ID] = *offer
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *MemoryOfferStore) Load(offerID uint64) (*TradeOffer, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	offer, ok := s.offers[offerID]
+	if !ok {
+		return nil, ErrOfferNotStored
+	}
+
+	return &offer, nil
+}
+
+// ListByState returns the offers in state, oldest ID first.
+func (s *MemoryOfferStore) ListByState(state TradeState) ([]*TradeOffer, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var offers []*TradeOffer
+	for _, offer := range s.offers {
+		if offer.State == state {
+			offer := offer
+			offers = append(offers, &offer)
+		}
+	}
+	sort.Slice(offers, func(i, j int) bool { return offers[i].ID < offers[j].ID })
+
+	return offers, nil
+}
+
+// SQLiteOfferStore keeps the offers in a SQLite database opened by the
+// caller with the driver of their choice (e.g. modernc.org/sqlite or
+// github.com/mattn/go-sqlite3), in the table created by NewSQLiteOfferStore.
+type SQLiteOfferStore struct {
+	db *sql.DB
+}
+
+// NewSQLiteOfferStore creates the steam_offers table when missing.
+func NewSQLiteOfferStore(db *sql.DB) (*SQLiteOfferStore, error) {
+	_, err := db.Exec(`CREATE TABLE IF NOT EXISTS steam_offers (
+		id INTEGER PRIMARY KEY,
+		state INTEGER NOT NULL,
+		offer TEXT NOT NULL,
+		updated_at INTEGER NOT NULL
+	)`)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = db.Exec(`CREATE INDEX IF NOT EXISTS steam_offers_state ON steam_offers (state)`)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SQLiteOfferStore{db: db}, nil
+}
+
+func (s *SQLiteOfferStore) Save(offer *TradeOffer) error {
+	b, err := json.Marshal(offer)
+	if err != nil {
+		return err
+	}
+
+	// The IDs are stored as signed integers, SQLite has no unsigned type.
+	_, err = s.db.Exec(`INSERT INTO steam_offers (id, state, offer, updated_at) VALUES (?, ?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET state = excluded.state, offer = excluded.offer, updated_at = excluded.updated_at`,
+		int64(offer.ID), offer.State, string(b), time.Now().Unix())
+	return err
+}
+
+func (s *SQLiteOfferStore) Load(offerID uint64) (*TradeOffer, error) {
+	var b string
+	err := s.db.QueryRow(`SELECT offer FROM steam_offers WHERE id = ?`, int64(offerID)).Scan(&b)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrOfferNotStored
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	offer := &TradeOffer{}
+	if err = json.Unmarshal([]byte(b), offer); err != nil {
+		return nil, err
+	}
+
+	return offer, nil
+}
+
+// ListByState returns the offers in state, oldest ID first.
+func (s *SQLiteOfferStore) ListByState(state TradeState) ([]*TradeOffer, error) {
+	rows, err := s.db.Query(`SELECT offer FROM steam_offers WHERE state = ? ORDER BY id`, state)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var offers []*TradeOffer
+	for rows.Next() {
+		var b string
+		if err = rows.Scan(&b); err != nil {
+			return nil, err
+		}
+
+		offer := &TradeOffer{}
+		if err = json.Unmarshal([]byte(b), offer); err != nil {
+			return nil, err
+		}
+		offers = append(offers, offer)
+	}
+
+	return offers, rows.Err()
+}
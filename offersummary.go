@@ -0,0 +1,95 @@
+package steam
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TradeStateNames maps trade offer states to the names used by
+// TradeState.String, StateName and Summary, replace entries to localize
+// them.  The names are also what TradeState marshals to in JSON, stored
+// offers keep decoding as long as the numbers or the old names are known.
+var TradeStateNames = map[TradeState]string{
+	TradeStateNone:                     "None",
+	TradeStateInvalid:                  "Invalid",
+	TradeStateActive:                   "Active",
+	TradeStateAccepted:                 "Accepted",
+	TradeStateCountered:                "Countered",
+	TradeStateExpired:                  "Expired",
+	TradeStateCanceled:                 "Canceled",
+	TradeStateDeclined:                 "Declined",
+	TradeStateInvalidItems:             "InvalidItems",
+	TradeStateCreatedNeedsConfirmation: "CreatedNeedsConfirmation",
+	TradeStateCanceledByTwoFactor:      "CanceledByTwoFactor",
+	TradeStateInEscrow:                 "InEscrow",
+}
+
+// OfferSummary is what SummaryFormatter gets to work with, values are the
+// est_usd sums in cents.
+type OfferSummary struct {
+	GiveCount int
+	GiveValue uint64
+	GiveNames []string // only filled when descriptions are known
+	RecvCount int
+	RecvValue uint64
+	RecvNames []string // only filled when descriptions are known
+	State     string
+}
+
+// SummaryFormatter renders an OfferSummary, replace it to localize
+// TradeOffer.Summary.
+var SummaryFormatter = func(s *OfferSummary) string {
+	side := func(verb string, count int, value uint64, names []string) string {
+		noun := "items"
+		if count == 1 {
+			noun = "item"
+		}
+
+		str := fmt.Sprintf("%s %d %s", verb, count, noun)
+		if len(names) != 0 {
+			str += " [" + strings.Join(names, ", ") + "]"
+		}
+
+		return str + fmt.Sprintf(" (≈$%d.%02d)", value/100, value%100)
+	}
+
+	return side("Giving", s.GiveCount, s.GiveValue, s.GiveNames) + ", " +
+		side("receiving", s.RecvCount, s.RecvValue, s.RecvNames) + ", state: " + s.State
+}
+
+func (offer *TradeOffer) StateName() string {
+	return offer.State.String()
+}
+
+// Summary describes the offer in one line for logs and notifications, descs
+// may be nil, when given item names are included.
+func (offer *TradeOffer) Summary(descs []*EconItemDesc) string {
+	names := make(map[string]string, len(descs))
+	for _, desc := range descs {
+		names[fmt.Sprintf("%d_%d", desc.ClassID, desc.InstanceID)] = desc.MarketHashName
+	}
+
+	summary := &OfferSummary{State: offer.StateName()}
+	summary.GiveCount, summary.GiveValue, summary.GiveNames = summarizeItems(offer.SendItems, names)
+	summary.RecvCount, summary.RecvValue, summary.RecvNames = summarizeItems(offer.RecvItems, names)
+
+	return SummaryFormatter(summary)
+}
+
+func summarizeItems(items []*EconItem, names map[string]string) (count int, value uint64, itemNames []string) {
+	for _, item := range items {
+		count++
+		value += uint64(item.EstUSD)
+
+		instanceID := item.InstanceID
+		if len(instanceID) == 0 {
+			instanceID = "0"
+		}
+
+		if name, ok := names[item.ClassID+"_"+instanceID]; ok {
+			itemNames = append(itemNames, name)
+		}
+	}
+
+	return count, value, itemNames
+}
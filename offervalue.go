@@ -0,0 +1,37 @@
+package steam
+
+// EstimateConverter converts the est_usd values Steam gives items (cents of
+// USD) into the currency the estimates below are expressed in, nil keeps
+// USD cents.
+var EstimateConverter func(usdCents uint64) uint64
+
+// EstimatedValue sums the est_usd values of items, converted with
+// EstimateConverter.  Items Steam has no estimate for count as 0.
+func EstimatedValue(items []*EconItem) uint64 {
+	var total uint64
+	for _, item := range items {
+		total += uint64(item.EstUSD)
+	}
+
+	if EstimateConverter != nil {
+		return EstimateConverter(total)
+	}
+
+	return total
+}
+
+// EstimatedGive is the estimated value of what the offer takes from us.
+func (offer *TradeOffer) EstimatedGive() uint64 {
+	return EstimatedValue(offer.SendItems)
+}
+
+// EstimatedReceive is the estimated value of what the offer gives us.
+func (offer *TradeOffer) EstimatedReceive() uint64 {
+	return EstimatedValue(offer.RecvItems)
+}
+
+// EstimatedDelta is what we gain with the offer, negative when it costs
+// more than it brings.
+func (offer *TradeOffer) EstimatedDelta() int64 {
+	return int64(offer.EstimatedReceive()) - int64(offer.EstimatedGive())
+}
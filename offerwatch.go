@@ -0,0 +1,181 @@
+package steam
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+const defaultOfferPollInterval = 30 * time.Second
+
+// OfferChange is a trade offer seen for the first time or in a new state,
+// OldState is TradeStateNone for new offers.
+type OfferChange struct {
+	Offer    *TradeOffer
+	OldState TradeState
+}
+
+// OfferWatcher polls the sent and received offers and reports their state
+// changes, it is safe to Poll from several goroutines.
+type OfferWatcher struct {
+	session  *Session
+	interval time.Duration
+
+	// OnChange is called for every change, in the order a poll saw them.
+	// It is called after the poll released the watcher, concurrent polls
+	// may call it concurrently.
+	OnChange func(change *OfferChange)
+
+	// Webhook, when set, is sent every change after OnChange.
+	Webhook *Webhook
+
+	// Store, when set, remembers the states across restarts.  A change is
+	// saved once handled, a crash while handling it reports it again.
+	Store OfferStore
+
+	mu       sync.Mutex
+	states   map[uint64]watchedOffer
+	lastPoll time.Time
+}
+
+// watchedOffer is the last state an offer was seen in.
+type watchedOffer struct {
+	state   TradeState
+	updated time.Time
+}
+
+// finalTradeState tells whether an offer in state can no longer change.
+func finalTradeState(state TradeState) bool {
+	switch state {
+	case TradeStateNone, TradeStateActive, TradeStateCreatedNeedsConfirmation, TradeStateInEscrow:
+		return false
+	}
+
+	return true
+}
+
+// NewOfferWatcher returns a watcher polling every interval, 0 means 30
+// seconds.
+func (session *Session) NewOfferWatcher(interval time.Duration) *OfferWatcher {
+	if interval == 0 {
+		interval = defaultOfferPollInterval
+	}
+
+	return &OfferWatcher{
+		session:  session,
+		interval: interval,
+		states:   make(map[uint64]watchedOffer),
+	}
+}
+
+// Poll fetches the offers updated since the last poll, active ones on the
+// first, and reports what changed.
+func (w *OfferWatcher) Poll() ([]*OfferChange, error) {
+	changes, err := w.poll()
+	if err != nil {
+		return nil, err
+	}
+
+	// Delivered unlocked, a slow receiver must not hold up the next polls.
+	for _, change := range changes {
+		if w.OnChange != nil {
+			w.OnChange(change)
+		}
+
+		if w.Webhook != nil {
+			if err = w.Webhook.Emit(w.session, change); err != nil {
+				w.session.log().Warnf("offer %d: webhook: %v", change.Offer.ID, err)
+			}
+		}
+
+		if w.Store != nil {
+			if err = w.Store.Save(change.Offer); err != nil {
+				w.session.log().Warnf("offer %d: store: %v", change.Offer.ID, err)
+			}
+		}
+	}
+
+	return changes, nil
+}
+
+// poll collects the changes under the lock.
+func (w *OfferWatcher) poll() ([]*OfferChange, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	start := time.Now()
+	cutoff := w.lastPoll
+	if cutoff.IsZero() {
+		cutoff = start
+	}
+
+	// Give the clocks a little slack, changes are only reported once
+	// anyway.
+	cutoff = cutoff.Add(-time.Minute)
+	response, err := w.session.GetTradeOffers(TradeFilterSentOffers|TradeFilterRecvOffers|TradeFilterActiveOnly, cutoff)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []*OfferChange
+	for _, offers := range [][]*TradeOffer{response.SentOffers, response.ReceivedOffers} {
+		for _, offer := range offers {
+			seen, known := w.states[offer.ID]
+			old := seen.state
+			if !known && w.Store != nil {
+				stored, err := w.Store.Load(offer.ID)
+				switch {
+				case err == nil:
+					old, known = stored.State, true
+				case !errors.Is(err, ErrOfferNotStored):
+					w.session.log().Warnf("offer %d: store: %v", offer.ID, err)
+				}
+			}
+
+			if known && old == offer.State {
+				continue
+			}
+
+			w.states[offer.ID] = watchedOffer{state: offer.State, updated: time.Unix(offer.Updated, 0)}
+			changes = append(changes, &OfferChange{Offer: offer, OldState: old})
+		}
+	}
+
+	/* Offers that reached a final state before the cutoff are not
+	 * returned again, they can be forgotten.  */
+	for id, seen := range w.states {
+		if finalTradeState(seen.state) && seen.updated.Before(cutoff) {
+			delete(w.states, id)
+		}
+	}
+
+	w.lastPoll = start
+	return changes, nil
+}
+
+// Run polls until ctx is done, poll errors are logged and retried at the
+// next interval.
+func (w *OfferWatcher) Run(ctx context.Context) error {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	var last time.Time
+	for {
+		now := time.Now()
+		if !last.IsZero() {
+			w.session.metricsHook().ObservePollLag(max(now.Sub(last)-w.interval, 0))
+		}
+		last = now
+
+		if _, err := w.Poll(); err != nil {
+			w.session.log().Warnf("offer poll failed: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package steam
+
+import (
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestOfferWatcherDeliversUnlocked(t *testing.T) {
+	var polls atomic.Int32
+	session := newTestSession(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := polls.Add(1)
+		answer(fmt.Sprintf(`{"response":{"trade_offers_received":[{"tradeofferid":"%d","trade_offer_state":2}]}}`, id)).ServeHTTP(w, r)
+	}))
+
+	watcher := session.NewOfferWatcher(time.Minute)
+	entered, release := make(chan struct{}), make(chan struct{})
+	watcher.OnChange = func(change *OfferChange) {
+		if change.Offer.ID == 1 {
+			close(entered)
+			<-release
+		}
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := watcher.Poll()
+		done <- err
+	}()
+	<-entered
+
+	second := make(chan error, 1)
+	go func() {
+		_, err := watcher.Poll()
+		second <- err
+	}()
+
+	select {
+	case err := <-second:
+		if err != nil {
+			t.Error(err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Error("a poll waited for the receiver of another")
+	}
+
+	close(release)
+	if err := <-done; err != nil {
+		t.Error(err)
+	}
+}
+
+func TestOfferWatcherForgetsFinalOffers(t *testing.T) {
+	old := time.Now().Add(-time.Hour).Unix()
+	session := newTestSession(t, answer(fmt.Sprintf(`{"response":{"trade_offers_sent":[
+		{"tradeofferid":"1","trade_offer_state":%d,"time_updated":%d},
+		{"tradeofferid":"2","trade_offer_state":%d,"time_updated":%d},
+		{"tradeofferid":"3","trade_offer_state":%d,"time_updated":%d}
+	]}}`, TradeStateDeclined, old, TradeStateDeclined, time.Now().Unix(), TradeStateActive, old)))
+
+	watcher := session.NewOfferWatcher(time.Minute)
+	changes, err := watcher.Poll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 3 {
+		t.Fatalf("got %d changes, want 3", len(changes))
+	}
+
+	if _, ok := watcher.states[1]; ok {
+		t.Error("an offer declined before the cutoff is still watched")
+	}
+	if _, ok := watcher.states[2]; !ok {
+		t.Error("an offer declined after the cutoff is forgotten, it would be reported again")
+	}
+	if _, ok := watcher.states[3]; !ok {
+		t.Error("an active offer is forgotten")
+	}
+}
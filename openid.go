@@ -3,47 +3,158 @@ package steam
 import (
 	"bytes"
 	"errors"
-	"fmt"
+	"io"
 	"mime/multipart"
 	"net/http"
 	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
 
 	"github.com/PuerkitoBio/goquery"
 )
 
-const steamBaseUrl = "https://steamcommunity.com"
+const (
+	steamBaseUrl = "https://steamcommunity.com"
 
-func (session *Session) Auth(realm, return_to string) (*http.Response, error) {
+	openIDLoginURL = steamBaseUrl + "/openid/login"
+	openIDNS       = "http://specs.openid.net/auth/2.0"
+	openIDSelect   = "http://specs.openid.net/auth/2.0/identifier_select"
+)
+
+var (
+	claimedIDExp = regexp.MustCompile(`^https?://steamcommunity\.com/openid/id/(\d+)$`)
+
+	ErrOpenIDInvalidMode     = errors.New("openid response is not a positive assertion")
+	ErrOpenIDInvalidEndpoint = errors.New("openid response does not come from steam")
+	ErrOpenIDReturnMismatch  = errors.New("openid return_to does not match")
+	ErrOpenIDNotValid        = errors.New("steam did not validate the openid response")
+	ErrOpenIDInvalidClaimID  = errors.New("invalid openid claimed_id")
+	ErrOpenIDNoForm          = errors.New("openid login form not found, is the session logged in?")
+	ErrOpenIDNoRedirect      = errors.New("steam did not redirect to return_to")
+)
+
+// OpenIDLoginURL builds the "Sign in through Steam" URL users are sent to,
+// Steam redirects them back to returnTo with the openid.* parameters
+// VerifyOpenID expects.
+func OpenIDLoginURL(realm, returnTo string) string {
+	return openIDLoginURL + "?" + url.Values{
+		"openid.mode":       {"checkid_setup"},
+		"openid.ns":         {openIDNS},
+		"openid.realm":      {realm},
+		"openid.return_to":  {returnTo},
+		"openid.identity":   {openIDSelect},
+		"openid.claimed_id": {openIDSelect},
+	}.Encode()
+}
+
+// VerifyOpenID checks the parameters Steam redirected the user back with by
+// asking Steam itself (check_authentication) and returns the logged in
+// SteamID.  returnTo is the URL the parameters were received on, without
+// the query string, it must match what was passed to OpenIDLoginURL.
+func VerifyOpenID(client *http.Client, returnTo string, params url.Values) (SteamID, error) {
+	if params.Get("openid.mode") != "id_res" {
+		return 0, ErrOpenIDInvalidMode
+	}
+
+	if params.Get("openid.op_endpoint") != openIDLoginURL {
+		return 0, ErrOpenIDInvalidEndpoint
+	}
+
+	if r := params.Get("openid.return_to"); r != returnTo && !strings.HasPrefix(r, returnTo+"?") {
+		return 0, ErrOpenIDReturnMismatch
+	}
+
+	m := claimedIDExp.FindStringSubmatch(params.Get("openid.claimed_id"))
+	if m == nil {
+		return 0, ErrOpenIDInvalidClaimID
+	}
+
+	sid, err := strconv.ParseUint(m[1], 10, 64)
+	if err != nil {
+		return 0, ErrOpenIDInvalidClaimID
+	}
+
+	check := url.Values{}
+	for k, v := range params {
+		if strings.HasPrefix(k, "openid.") {
+			check[k] = v
+		}
+	}
+	check.Set("openid.mode", "check_authentication")
+
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.PostForm(openIDLoginURL, check)
+	if resp != nil {
+		defer func(Body io.ReadCloser) {
+			err := Body.Close()
+			if err != nil {
+				return
+			}
+		}(resp.Body)
+	}
+
+	if err != nil {
+		return 0, err
+	}
 
-	loginUrl := steamBaseUrl + "/openid/login?" + url.Values{
+	body, err := io.ReadAll(limitBody(resp))
+	if err != nil {
+		return 0, err
+	}
+
+	if !strings.Contains(string(body), "is_valid:true") {
+		return 0, ErrOpenIDNotValid
+	}
+
+	return SteamID(sid), nil
+}
+
+// OpenIDAuthResult is where Steam sends the browser back to after a
+// successful OpenID login, CallbackURL is meant to be opened on the relying
+// party to complete its side of the login.
+type OpenIDAuthResult struct {
+	SteamID     SteamID
+	CallbackURL string
+}
+
+// Auth logs the session into a third party website through Steam OpenID,
+// stopping right before the redirect to returnTo.
+func (session *Session) Auth(realm, returnTo string) (*OpenIDAuthResult, error) {
+	loginUrl := openIDLoginURL + "?" + url.Values{
 		"openid.mode":       {"checkid_setup"},
-		"openid.ns":         {"http://specs.openid.net/auth/2.0"},
+		"openid.ns":         {openIDNS},
 		"openid.realm":      {realm},
-		"openid.return_to":  {return_to},
+		"openid.return_to":  {returnTo},
 		"openid.ns.sreg":    {"http://openid.net/extensions/sreg/1.1"},
-		"openid.identity":   {"http://specs.openid.net/auth/2.0/identifier_select"},
-		"openid.claimed_id": {"http://specs.openid.net/auth/2.0/identifier_select"},
+		"openid.identity":   {openIDSelect},
+		"openid.claimed_id": {openIDSelect},
 	}.Encode()
 
 	req, _ := http.NewRequest("GET", loginUrl, nil)
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded; charset=UTF-8")
 	req.Header.Add("User-Agent", "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/51.0.2704.103 Safari/537.36")
 	req.Header.Add("Accept", "*/*")
-	//req.Header.Add()
 
 	resp, err := session.client.Do(req)
-
-	//out, err := os.Create("login.html")
-	//io.Copy(out, resp.Body)
-	//out.Close()
-
-	fmt.Println(resp.Header["Set-Cookie"])
-	fmt.Println(resp.Request.Header["Cookie"])
+	if resp != nil {
+		defer func(Body io.ReadCloser) {
+			err := Body.Close()
+			if err != nil {
+				return
+			}
+		}(resp.Body)
+	}
 
 	if err != nil {
 		return nil, err
 	}
 
+	session.log().Debugf("openid login: %d cookies set", len(resp.Cookies()))
+
 	doc, err := goquery.NewDocumentFromReader(resp.Body)
 	if err != nil {
 		return nil, err
@@ -51,12 +162,12 @@ func (session *Session) Auth(realm, return_to string) (*http.Response, error) {
 
 	openidparams, exists := doc.Find("input[name=openidparams]").Attr("value")
 	if !exists {
-		return nil, errors.New("No form")
+		return nil, ErrOpenIDNoForm
 	}
 
 	nonce, exists := doc.Find("input[name=nonce]").Attr("value")
 	if !exists {
-		return nil, errors.New("No form")
+		return nil, ErrOpenIDNoForm
 	}
 
 	body := new(bytes.Buffer)
@@ -80,13 +191,56 @@ func (session *Session) Auth(realm, return_to string) (*http.Response, error) {
 		return nil, err
 	}
 
-	req, _ = http.NewRequest("POST", steamBaseUrl+"/openid/login", body)
+	req, _ = http.NewRequest("POST", openIDLoginURL, body)
 	req.Header.Set("Content-Type", writer.FormDataContentType())
 	req.Header.Add("User-Agent", "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/51.0.2704.103 Safari/537.36")
 	req.Header.Add("Referer", loginUrl)
 	req.Header.Add("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,image/avif,image/webp,*/*;q=0.8")
 	req.Header.Add("Accept-Language", "en-US,en;q=0.5")
-	req.Header.Add("Accept-Encoding", "gzip, deflate, br")
 
-	return session.client.Do(req)
+	/* Follow Steam's own redirects but stop before leaving for returnTo.  */
+	client := *session.client
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if strings.HasPrefix(req.URL.String(), returnTo) {
+			return http.ErrUseLastResponse
+		}
+		if len(via) >= 10 {
+			return errors.New("stopped after 10 redirects")
+		}
+		return nil
+	}
+
+	resp2, err := client.Do(req)
+	if resp2 != nil {
+		defer func(Body io.ReadCloser) {
+			err := Body.Close()
+			if err != nil {
+				return
+			}
+		}(resp2.Body)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	location, err := resp2.Location()
+	if err != nil || !strings.HasPrefix(location.String(), returnTo) {
+		return nil, ErrOpenIDNoRedirect
+	}
+
+	m := claimedIDExp.FindStringSubmatch(location.Query().Get("openid.claimed_id"))
+	if m == nil {
+		return nil, ErrOpenIDInvalidClaimID
+	}
+
+	sid, err := strconv.ParseUint(m[1], 10, 64)
+	if err != nil {
+		return nil, ErrOpenIDInvalidClaimID
+	}
+
+	return &OpenIDAuthResult{
+		SteamID:     SteamID(sid),
+		CallbackURL: location.String(),
+	}, nil
 }
@@ -0,0 +1,243 @@
+package steam
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+/* The community pages below are parsed as documents: values are looked up
+ * by element or, for those only given to the page scripts, in the text of
+ * the <script> elements rather than across the whole HTML.  */
+
+var (
+	// receiptItemExp matches the items of a trade receipt script:
+	//	oItem = {"id":"...",...};
+	receiptItemExp = regexp.MustCompile(`oItem\s*=\s*(\{.+?\});`)
+	tradeTokenExp  = regexp.MustCompile(`token=([a-zA-Z0-9-_]+)`)
+)
+
+// PageParseError names the field of a community page that could not be
+// found or read, Err is the underlying error when there is one.
+type PageParseError struct {
+	Page  string
+	Field string
+	Err   error
+}
+
+func (e *PageParseError) Error() string {
+	msg := "cannot parse " + e.Field + " of " + e.Page
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+
+	return msg
+}
+
+func (e *PageParseError) Unwrap() error {
+	return e.Err
+}
+
+type page struct {
+	name    string
+	doc     *goquery.Document
+	scripts string
+}
+
+func parsePage(name string, body []byte) (*page, error) {
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(body))
+	if err != nil {
+		return nil, &PageParseError{Page: name, Field: "document", Err: err}
+	}
+
+	var scripts strings.Builder
+	doc.Find("script").Each(func(_ int, s *goquery.Selection) {
+		scripts.WriteString(s.Text())
+		scripts.WriteByte('\n')
+	})
+
+	return &page{name: name, doc: doc, scripts: scripts.String()}, nil
+}
+
+func (p *page) error(field string, err error) error {
+	return &PageParseError{Page: p.name, Field: field, Err: err}
+}
+
+// scriptVarExps caches the pattern of every name scriptVar looked up, the
+// set of names is small and fixed.
+var scriptVarExps sync.Map // name -> *regexp.Regexp
+
+// scriptVar returns the value a page script assigns to the global name.
+func (p *page) scriptVar(name string) (string, bool) {
+	exp, ok := scriptVarExps.Load(name)
+	if !ok {
+		exp, _ = scriptVarExps.LoadOrStore(name, regexp.MustCompile(`\bvar\s+`+regexp.QuoteMeta(name)+`\s*=\s*(.+?);`))
+	}
+
+	m := exp.(*regexp.Regexp).FindStringSubmatch(p.scripts)
+	if m == nil {
+		return "", false
+	}
+
+	return strings.TrimSpace(m[1]), true
+}
+
+// scriptInt is scriptVar for integers.
+func (p *page) scriptInt(name string) (int64, error) {
+	v, ok := p.scriptVar(name)
+	if !ok {
+		return 0, p.error(name, nil)
+	}
+
+	n, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		return 0, p.error(name, err)
+	}
+
+	return n, nil
+}
+
+// parseEscrow reads the escrow days of a trade offer page, or the error
+// Steam shows instead of the offer.
+func parseEscrow(body []byte) (*EscrowSteamGuardInfo, error) {
+	p, err := parsePage("tradeoffer", body)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.escrow()
+}
+
+func (p *page) escrow() (*EscrowSteamGuardInfo, error) {
+	var err error
+	info := &EscrowSteamGuardInfo{
+		ErrorMsg: strings.TrimSpace(p.doc.Find("#error_msg").First().Text()),
+	}
+	if len(info.ErrorMsg) != 0 {
+		return info, nil
+	}
+
+	if info.MyDays, err = p.scriptInt("g_daysMyEscrow"); err != nil {
+		return nil, err
+	}
+
+	if info.ThemDays, err = p.scriptInt("g_daysTheirEscrow"); err != nil {
+		return nil, err
+	}
+
+	return info, nil
+}
+
+// parseTradeToken reads the token of the trade URL shown on the trade
+// offer privacy page.
+func parseTradeToken(body []byte) (string, error) {
+	p, err := parsePage("tradeoffers/privacy", body)
+	if err != nil {
+		return "", err
+	}
+
+	value, ok := p.doc.Find("#trade_offer_access_url").First().Attr("value")
+	if !ok {
+		return "", p.error("trade_offer_access_url", ErrCannotFindOfferInfo)
+	}
+
+	if u, err := url.Parse(strings.TrimSpace(value)); err == nil {
+		if token := u.Query().Get("token"); len(token) != 0 {
+			return token, nil
+		}
+	}
+
+	// Not a well-formed URL, take whatever looks like the token.
+	if m := tradeTokenExp.FindStringSubmatch(value); m != nil {
+		return m[1], nil
+	}
+
+	return "", p.error("trade_offer_access_url", ErrCannotFindOfferInfo)
+}
+
+// parseReceipt reads the items of a trade receipt page.
+func parseReceipt(body []byte) ([]*InventoryItem, error) {
+	p, err := parsePage("trade receipt", body)
+	if err != nil {
+		return nil, err
+	}
+
+	m := receiptItemExp.FindAllStringSubmatch(p.scripts, -1)
+	if m == nil {
+		return nil, p.error("oItem", ErrReceiptMatch)
+	}
+
+	items := make([]*InventoryItem, len(m))
+	for k := range m {
+		item := &InventoryItem{}
+		if err := json.Unmarshal([]byte(m[k][1]), item); err != nil {
+			return nil, p.error("oItem "+strconv.Itoa(k), err)
+		}
+
+		items[k] = item
+	}
+
+	return items, nil
+}
+
+// profileEdit is what the profile edit page gives its form script, the
+// values a profileSave has to send back to keep them.
+type profileEdit struct {
+	PersonaName  string `json:"strPersonaName"`
+	CustomURL    string `json:"strCustomURL"`
+	RealName     string `json:"strRealName"`
+	Summary      string `json:"strSummary"`
+	LocationData struct {
+		CountryCode string          `json:"locCountryCode"`
+		StateCode   string          `json:"locStateCode"`
+		CityCode    json.RawMessage `json:"locCityCode"` // a number, or "" without a city
+	} `json:"LocationData"`
+	ProfilePreferences struct {
+		HideProfileAwards int `json:"hide_profile_awards"`
+	} `json:"ProfilePreferences"`
+}
+
+// parseProfileEdit reads the current profile of a profile edit page.
+func parseProfileEdit(body []byte) (*profileEdit, error) {
+	p, err := parsePage("profile edit", body)
+	if err != nil {
+		return nil, err
+	}
+
+	config, ok := p.doc.Find("#profile_edit_config").First().Attr("data-profile-edit")
+	if !ok {
+		return nil, p.error("profile_edit_config", ErrInvalidResponse)
+	}
+
+	edit := &profileEdit{}
+	if err = json.Unmarshal([]byte(config), edit); err != nil {
+		return nil, p.error("profile_edit_config", err)
+	}
+
+	return edit, nil
+}
+
+// values are the form fields of a profileSave keeping the profile as is.
+func (edit *profileEdit) values() url.Values {
+	city := strings.Trim(string(edit.LocationData.CityCode), `"`)
+	if city == "null" {
+		city = ""
+	}
+
+	return url.Values{
+		"personaName":         {edit.PersonaName},
+		"real_name":           {edit.RealName},
+		"summary":             {edit.Summary},
+		"customURL":           {edit.CustomURL},
+		"country":             {edit.LocationData.CountryCode},
+		"state":               {edit.LocationData.StateCode},
+		"city":                {city},
+		"hide_profile_awards": {strconv.Itoa(edit.ProfilePreferences.HideProfileAwards)},
+	}
+}
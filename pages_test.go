@@ -0,0 +1,198 @@
+package steam
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files of testdata")
+
+func readFixture(t testing.TB, name string) []byte {
+	t.Helper()
+
+	b, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return b
+}
+
+// checkGolden compares v encoded as JSON to testdata/name.golden, -update
+// rewrites the file instead.
+func checkGolden(t *testing.T, name string, v interface{}) {
+	t.Helper()
+
+	got, err := json.MarshalIndent(v, "", "\t")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got = append(got, '\n')
+
+	path := filepath.Join("testdata", name+".golden")
+	if *update {
+		if err = os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(got, want) {
+		t.Errorf("%s differs from the golden file:\ngot:\n%s\nwant:\n%s", name, got, want)
+	}
+}
+
+// checkParseError makes sure err is a PageParseError naming field.
+func checkParseError(t *testing.T, err error, field string) {
+	t.Helper()
+
+	var perr *PageParseError
+	if !errors.As(err, &perr) {
+		t.Fatalf("got error %v, want a PageParseError", err)
+	}
+
+	if perr.Field != field {
+		t.Errorf("error names field %q, want %q", perr.Field, field)
+	}
+}
+
+func TestParseEscrow(t *testing.T) {
+	for _, name := range []string{"tradeoffer", "tradeoffer_error"} {
+		t.Run(name, func(t *testing.T) {
+			info, err := parseEscrow(readFixture(t, name+".html"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			checkGolden(t, name, info)
+		})
+	}
+
+	t.Run("missing their escrow", func(t *testing.T) {
+		_, err := parseEscrow(readFixture(t, "tradeoffer_noescrow.html"))
+		checkParseError(t, err, "g_daysTheirEscrow")
+	})
+}
+
+func TestParseTradeToken(t *testing.T) {
+	tests := []struct {
+		fixture string
+		token   string
+	}{
+		{"privacy.html", "aBc-D_12"},
+		{"privacy_mangled.html", "xYz9-_"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			token, err := parseTradeToken(readFixture(t, tt.fixture))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if token != tt.token {
+				t.Errorf("got token %q, want %q", token, tt.token)
+			}
+		})
+	}
+
+	t.Run("missing", func(t *testing.T) {
+		_, err := parseTradeToken(readFixture(t, "privacy_missing.html"))
+		checkParseError(t, err, "trade_offer_access_url")
+		if !errors.Is(err, ErrCannotFindOfferInfo) {
+			t.Errorf("got %v, want it to wrap ErrCannotFindOfferInfo", err)
+		}
+	})
+}
+
+func TestParseProfileEdit(t *testing.T) {
+	edit, err := parseProfileEdit(readFixture(t, "profile_edit.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := url.Values{
+		"personaName":         {"old name"},
+		"real_name":           {"Jo & Co"},
+		"summary":             {"Trades <b>fast</b>.\nNo refunds."},
+		"customURL":           {"tradebot"},
+		"country":             {"DE"},
+		"state":               {"16"},
+		"city":                {"9640"},
+		"hide_profile_awards": {"1"},
+	}
+	if got := edit.values(); got.Encode() != want.Encode() {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	t.Run("missing", func(t *testing.T) {
+		_, err := parseProfileEdit(readFixture(t, "privacy.html"))
+		checkParseError(t, err, "profile_edit_config")
+	})
+}
+
+func TestParseReceipt(t *testing.T) {
+	items, err := parseReceipt(readFixture(t, "receipt.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	checkGolden(t, "receipt", items)
+
+	t.Run("no items", func(t *testing.T) {
+		_, err := parseReceipt(readFixture(t, "receipt_empty.html"))
+		checkParseError(t, err, "oItem")
+		if !errors.Is(err, ErrReceiptMatch) {
+			t.Errorf("got %v, want it to wrap ErrReceiptMatch", err)
+		}
+	})
+
+	t.Run("broken item", func(t *testing.T) {
+		_, err := parseReceipt(readFixture(t, "receipt_broken.html"))
+		checkParseError(t, err, "oItem 0")
+	})
+}
+
+func TestScriptVar(t *testing.T) {
+	p, err := parsePage("test", []byte(`<script>var g_a = 1; var g_ab = "x";</script><p>var g_c = 3;</p>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		value string
+		ok    bool
+	}{
+		{"g_a", "1", true},
+		{"g_ab", `"x"`, true},
+		{"g_c", "", false}, // outside of any script
+		{"g_", "", false},
+	}
+
+	for _, tt := range tests {
+		// Twice, the second lookup comes from the pattern cache.
+		for i := 0; i < 2; i++ {
+			value, ok := p.scriptVar(tt.name)
+			if value != tt.value || ok != tt.ok {
+				t.Errorf("scriptVar(%q) = %q, %v, want %q, %v", tt.name, value, ok, tt.value, tt.ok)
+			}
+		}
+	}
+
+	if !strings.Contains(p.scripts, "g_ab") {
+		t.Error("script text lost")
+	}
+}
@@ -0,0 +1,175 @@
+package steam
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// maxPartnerCacheEntries bounds the partner cache, once full the expired
+// snapshots go first and then the oldest one.
+const maxPartnerCacheEntries = 10000
+
+var ErrPartnerNotFound = errors.New("unable to find trade partner profile")
+
+// PartnerSnapshot gathers what offer policies usually look at before
+// trading with someone.
+type PartnerSnapshot struct {
+	SteamID     SteamID
+	Level       uint32
+	Bans        *PlayerBan
+	Summary     *PlayerSummary
+	CreatedAt   time.Time // zero when the profile hides its creation date
+	RetrievedAt time.Time
+}
+
+// ProfileAge is how old the profile is now, zero when it hides its creation
+// date.
+func (snapshot *PartnerSnapshot) ProfileAge() time.Duration {
+	if snapshot.CreatedAt.IsZero() {
+		return 0
+	}
+
+	return time.Since(snapshot.CreatedAt)
+}
+
+// OfferEvent is a trade offer together with the optional snapshot of its
+// partner, handed to whatever decides what to do with new offers.
+type OfferEvent struct {
+	Offer   *TradeOffer
+	Partner *PartnerSnapshot // nil unless requested
+}
+
+type partnerCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[SteamID]*PartnerSnapshot
+}
+
+func (c *partnerCache) get(sid SteamID) (*PartnerSnapshot, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	snapshot, ok := c.entries[sid]
+	if !ok {
+		return nil, false
+	}
+
+	if time.Since(snapshot.RetrievedAt) > c.ttl {
+		delete(c.entries, sid)
+		return nil, false
+	}
+
+	return snapshot, true
+}
+
+func (c *partnerCache) set(snapshot *PartnerSnapshot) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.entries[snapshot.SteamID]; !ok && len(c.entries) >= maxPartnerCacheEntries {
+		c.evict()
+	}
+
+	c.entries[snapshot.SteamID] = snapshot
+}
+
+// evict drops the expired snapshots, or the oldest one when none expired.
+func (c *partnerCache) evict() {
+	var oldest *PartnerSnapshot
+	for sid, snapshot := range c.entries {
+		if time.Since(snapshot.RetrievedAt) > c.ttl {
+			delete(c.entries, sid)
+			continue
+		}
+
+		if oldest == nil || snapshot.RetrievedAt.Before(oldest.RetrievedAt) {
+			oldest = snapshot
+		}
+	}
+
+	if len(c.entries) >= maxPartnerCacheEntries && oldest != nil {
+		delete(c.entries, oldest.SteamID)
+	}
+}
+
+// SetPartnerCacheTTL caches partner snapshots for ttl, 0 disables caching.
+// The cache holds at most the snapshots of 10000 partners.
+func (session *Session) SetPartnerCacheTTL(ttl time.Duration) {
+	if ttl == 0 {
+		session.partners = nil
+		return
+	}
+
+	session.partners = &partnerCache{ttl: ttl, entries: make(map[SteamID]*PartnerSnapshot)}
+}
+
+// GetPartnerSnapshot fetches level, bans and profile summary of sid, going
+// through the partner cache when enabled.
+func (session *Session) GetPartnerSnapshot(sid SteamID) (*PartnerSnapshot, error) {
+	if session.partners != nil {
+		if snapshot, ok := session.partners.get(sid); ok {
+			return snapshot, nil
+		}
+	}
+
+	summaries, err := session.GetPlayerSummaries(sid.ToString())
+	if err != nil {
+		return nil, err
+	}
+
+	if len(summaries) == 0 {
+		return nil, ErrPartnerNotFound
+	}
+
+	bans, err := session.GetPlayerBans(sid.ToString())
+	if err != nil {
+		return nil, err
+	}
+
+	level, err := session.GetSteamLevel(sid)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := &PartnerSnapshot{
+		SteamID:     sid,
+		Level:       level,
+		Summary:     summaries[0],
+		RetrievedAt: time.Now(),
+	}
+
+	if len(bans) != 0 {
+		snapshot.Bans = bans[0]
+	}
+
+	if summaries[0].TimeCreated != 0 {
+		snapshot.CreatedAt = time.Unix(summaries[0].TimeCreated, 0)
+	}
+
+	if session.partners != nil {
+		session.partners.set(snapshot)
+	}
+
+	return snapshot, nil
+}
+
+// NewOfferEvent wraps offer in an OfferEvent, fetching the partner snapshot
+// when withPartner is set.
+func (session *Session) NewOfferEvent(offer *TradeOffer, withPartner bool) (*OfferEvent, error) {
+	event := &OfferEvent{Offer: offer}
+	if !withPartner {
+		return event, nil
+	}
+
+	var sid SteamID
+	sid.ParseDefaults(offer.Partner)
+
+	snapshot, err := session.GetPartnerSnapshot(sid)
+	if err != nil {
+		return nil, err
+	}
+
+	event.Partner = snapshot
+	return event, nil
+}
@@ -0,0 +1,52 @@
+package steam
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPartnerCacheEvicts(t *testing.T) {
+	c := &partnerCache{ttl: time.Hour, entries: make(map[SteamID]*PartnerSnapshot)}
+
+	now := time.Now()
+	for i := 0; i < maxPartnerCacheEntries; i++ {
+		c.set(&PartnerSnapshot{SteamID: testSteamID + SteamID(i), RetrievedAt: now.Add(time.Duration(i) * time.Millisecond)})
+	}
+
+	// Full, the oldest snapshot makes room.
+	c.set(&PartnerSnapshot{SteamID: 1, RetrievedAt: now.Add(time.Minute)})
+	if len(c.entries) != maxPartnerCacheEntries {
+		t.Fatalf("got %d entries, want %d", len(c.entries), maxPartnerCacheEntries)
+	}
+	if _, ok := c.get(testSteamID); ok {
+		t.Error("oldest snapshot still cached")
+	}
+
+	// Expired snapshots all go at once.
+	for sid, snapshot := range c.entries {
+		if sid != 1 {
+			snapshot.RetrievedAt = now.Add(-2 * time.Hour)
+		}
+	}
+	c.set(&PartnerSnapshot{SteamID: 2, RetrievedAt: now})
+	if len(c.entries) != 2 {
+		t.Errorf("got %d entries, want the 2 fresh ones", len(c.entries))
+	}
+
+	// Reading an expired snapshot drops it.
+	c.entries[2].RetrievedAt = now.Add(-2 * time.Hour)
+	if _, ok := c.get(2); ok || len(c.entries) != 1 {
+		t.Errorf("expired snapshot returned or kept, %d entries", len(c.entries))
+	}
+}
+
+func TestPartnerProfileAge(t *testing.T) {
+	snapshot := &PartnerSnapshot{CreatedAt: time.Now().Add(-48 * time.Hour), RetrievedAt: time.Now().Add(-24 * time.Hour)}
+	if age := snapshot.ProfileAge(); age < 48*time.Hour || age > 49*time.Hour {
+		t.Errorf("got age %v, want 48h from the creation date", age)
+	}
+
+	if age := (&PartnerSnapshot{}).ProfileAge(); age != 0 {
+		t.Errorf("got age %v for a hidden creation date, want 0", age)
+	}
+}
@@ -0,0 +1,351 @@
+package steam
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+var (
+	ErrPoolEmpty        = errors.New("no account in the pool")
+	ErrNoHealthyAccount = errors.New("no healthy account in the pool")
+)
+
+// PoolSelection is how SessionPool picks the account of a request.
+type PoolSelection int
+
+const (
+	PoolRoundRobin PoolSelection = iota
+	PoolLeastRecentlyUsed
+)
+
+// PoolConfig configures a SessionPool, the zero value is usable.
+type PoolConfig struct {
+	Selection PoolSelection
+
+	// MinInterval is the least time between two uses of an account,
+	// Acquire waits for the first account that is ready.
+	MinInterval time.Duration
+
+	// MaxFailures consecutive failures take an account out of rotation
+	// for Cooldown, defaults to 3 and 5 minutes.
+	MaxFailures int
+	Cooldown    time.Duration
+}
+
+// PoolAccount is an account of a SessionPool.  IdentitySecret is only
+// needed by the confirmation broadcasts.
+type PoolAccount struct {
+	Name           string
+	Session        *Session
+	IdentitySecret string
+}
+
+// PoolHealth is how an account of the pool has been doing.
+type PoolHealth struct {
+	Healthy      bool      `json:"healthy"`
+	Failures     int       `json:"failures"` // consecutive
+	LastError    string    `json:"last_error,omitempty"`
+	LastUsed     time.Time `json:"last_used"`
+	CooldownTill time.Time `json:"cooldown_till,omitempty"`
+}
+
+type poolEntry struct {
+	account  *PoolAccount
+	lastUsed time.Time
+	failures int
+	lastErr  error
+	cooldown time.Time
+}
+
+// SessionPool spreads requests over the sessions of several accounts,
+// keeping each under a rate limit and out of rotation while it fails.
+type SessionPool struct {
+	config PoolConfig
+
+	mu      sync.Mutex
+	entries []*poolEntry
+	next    int
+}
+
+func NewSessionPool(config PoolConfig) *SessionPool {
+	if config.MaxFailures <= 0 {
+		config.MaxFailures = 3
+	}
+	if config.Cooldown <= 0 {
+		config.Cooldown = 5 * time.Minute
+	}
+
+	return &SessionPool{config: config}
+}
+
+// Add puts an account in the pool, replacing the one of the same name.
+func (pool *SessionPool) Add(account *PoolAccount) {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	for _, entry := range pool.entries {
+		if entry.account.Name == account.Name {
+			entry.account = account
+			return
+		}
+	}
+
+	pool.entries = append(pool.entries, &poolEntry{account: account})
+}
+
+// Remove takes an account out of the pool.
+func (pool *SessionPool) Remove(name string) {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	for i, entry := range pool.entries {
+		if entry.account.Name == name {
+			pool.entries = append(pool.entries[:i], pool.entries[i+1:]...)
+			return
+		}
+	}
+}
+
+// Accounts lists the accounts of the pool.
+func (pool *SessionPool) Accounts() []*PoolAccount {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	accounts := make([]*PoolAccount, 0, len(pool.entries))
+	for _, entry := range pool.entries {
+		accounts = append(accounts, entry.account)
+	}
+
+	return accounts
+}
+
+func (pool *SessionPool) healthy(entry *poolEntry, now time.Time) bool {
+	return entry.failures < pool.config.MaxFailures || now.After(entry.cooldown)
+}
+
+// pick returns the account to use now, or how long to wait for one.
+func (pool *SessionPool) pick(now time.Time) (*poolEntry, time.Duration, error) {
+	if len(pool.entries) == 0 {
+		return nil, 0, ErrPoolEmpty
+	}
+
+	var best *poolEntry
+	bestIndex := -1
+	wait := time.Duration(-1)
+	for i := range pool.entries {
+		index := i
+		if pool.config.Selection == PoolRoundRobin {
+			index = (pool.next + i) % len(pool.entries)
+		}
+
+		entry := pool.entries[index]
+		if !pool.healthy(entry, now) {
+			continue
+		}
+
+		if ready := entry.lastUsed.Add(pool.config.MinInterval).Sub(now); ready > 0 {
+			if wait < 0 || ready < wait {
+				wait = ready
+			}
+			continue
+		}
+
+		if pool.config.Selection == PoolRoundRobin {
+			best, bestIndex = entry, index
+			break
+		}
+
+		if best == nil || entry.lastUsed.Before(best.lastUsed) {
+			best, bestIndex = entry, index
+		}
+	}
+
+	if best != nil {
+		pool.next = bestIndex + 1
+		best.lastUsed = now
+		return best, 0, nil
+	}
+
+	if wait < 0 {
+		return nil, 0, ErrNoHealthyAccount
+	}
+
+	return nil, wait, nil
+}
+
+func (pool *SessionPool) acquire(ctx context.Context) (*poolEntry, error) {
+	for {
+		pool.mu.Lock()
+		entry, wait, err := pool.pick(time.Now())
+		pool.mu.Unlock()
+
+		if entry != nil || err != nil {
+			return entry, err
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// Acquire returns the next account according to the selection policy,
+// waiting for the rate limit of the accounts when all are busy.
+func (pool *SessionPool) Acquire(ctx context.Context) (*PoolAccount, error) {
+	entry, err := pool.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return entry.account, nil
+}
+
+// Report records the outcome of a request made with an account from
+// Acquire, failures count towards taking it out of rotation.
+func (pool *SessionPool) Report(name string, err error) {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	for _, entry := range pool.entries {
+		if entry.account.Name != name {
+			continue
+		}
+
+		if err == nil {
+			entry.failures = 0
+			entry.lastErr = nil
+			return
+		}
+
+		entry.failures++
+		entry.lastErr = err
+		if entry.failures >= pool.config.MaxFailures {
+			entry.cooldown = time.Now().Add(pool.config.Cooldown)
+		}
+		return
+	}
+}
+
+// Do runs fn with the next account and reports its outcome.
+func (pool *SessionPool) Do(ctx context.Context, fn func(*PoolAccount) error) error {
+	account, err := pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = fn(account)
+	pool.Report(account.Name, err)
+	return err
+}
+
+// Broadcast runs fn for every healthy account in parallel, ignoring the
+// rate limit, and returns the errors by account name.
+func (pool *SessionPool) Broadcast(ctx context.Context, fn func(*PoolAccount) error) map[string]error {
+	now := time.Now()
+
+	pool.mu.Lock()
+	var accounts []*PoolAccount
+	for _, entry := range pool.entries {
+		if pool.healthy(entry, now) {
+			accounts = append(accounts, entry.account)
+		}
+	}
+	pool.mu.Unlock()
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	errs := make(map[string]error)
+	for _, account := range accounts {
+		wg.Add(1)
+		go func(account *PoolAccount) {
+			defer wg.Done()
+
+			err := ctx.Err()
+			if err == nil {
+				err = fn(account)
+			}
+
+			pool.Report(account.Name, err)
+			if err != nil {
+				mu.Lock()
+				errs[account.Name] = err
+				mu.Unlock()
+			}
+		}(account)
+	}
+	wg.Wait()
+
+	return errs
+}
+
+// GetConfirmations fetches the confirmations of every healthy account
+// with an identity secret.
+func (pool *SessionPool) GetConfirmations(ctx context.Context, current int64) (map[string][]*Confirmation, map[string]error) {
+	var mu sync.Mutex
+	confirmations := make(map[string][]*Confirmation)
+	errs := pool.Broadcast(ctx, func(account *PoolAccount) error {
+		if len(account.IdentitySecret) == 0 {
+			return nil
+		}
+
+		list, err := account.Session.GetConfirmations(account.IdentitySecret, current)
+		if err != nil {
+			return err
+		}
+
+		mu.Lock()
+		confirmations[account.Name] = list
+		mu.Unlock()
+		return nil
+	})
+
+	return confirmations, errs
+}
+
+// CheckHealth asks every account whether it is still logged in, see
+// IsLoggedIn, logged out accounts count as failing.
+func (pool *SessionPool) CheckHealth(ctx context.Context) map[string]error {
+	return pool.Broadcast(ctx, func(account *PoolAccount) error {
+		ok, err := account.Session.IsLoggedIn()
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return ErrSessionExpired
+		}
+
+		return nil
+	})
+}
+
+// Health returns the health of every account by name.
+func (pool *SessionPool) Health() map[string]PoolHealth {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	now := time.Now()
+	health := make(map[string]PoolHealth, len(pool.entries))
+	for _, entry := range pool.entries {
+		h := PoolHealth{
+			Healthy:  pool.healthy(entry, now),
+			Failures: entry.failures,
+			LastUsed: entry.lastUsed,
+		}
+		if entry.lastErr != nil {
+			h.LastError = entry.lastErr.Error()
+		}
+		if !h.Healthy {
+			h.CooldownTill = entry.cooldown
+		}
+
+		health[entry.account.Name] = h
+	}
+
+	return health
+}
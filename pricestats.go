@@ -0,0 +1,154 @@
+package steam
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// priceHistoryLayout is the layout of MarketItemPrice.Date without its
+// trailing " +0" offset, the history is always in UTC.
+const priceHistoryLayout = "Jan 02 2006 15:"
+
+// Time parses the date of the sale bucket.
+func (price *MarketItemPrice) Time() (time.Time, error) {
+	date, _, _ := strings.Cut(price.Date, " +")
+	return time.ParseInLocation(priceHistoryLayout, date, time.UTC)
+}
+
+// Volume is the number of items sold in the bucket.
+func (price *MarketItemPrice) Volume() int {
+	volume, _ := strconv.Atoi(price.Count)
+	return volume
+}
+
+// DailyPrice is the volume weighted price of the sales of one day.
+type DailyPrice struct {
+	Day    time.Time
+	Price  float64
+	Volume int
+}
+
+// DailyPrices folds a price history into one volume weighted entry per
+// day, oldest first.  Entries with an unparseable date are skipped.
+func DailyPrices(prices []*MarketItemPrice) []*DailyPrice {
+	var days []*DailyPrice
+	var last *DailyPrice
+	for _, price := range prices {
+		t, err := price.Time()
+		if err != nil {
+			continue
+		}
+
+		day := t.Truncate(24 * time.Hour)
+		if last == nil || !last.Day.Equal(day) {
+			last = &DailyPrice{Day: day}
+			days = append(days, last)
+		}
+
+		volume := price.Volume()
+		if volume == 0 {
+			volume = 1
+		}
+
+		last.Price = (last.Price*float64(last.Volume) + price.Price*float64(volume)) / float64(last.Volume+volume)
+		last.Volume += volume
+	}
+
+	return days
+}
+
+// MovingAverage returns the simple moving average over window entries of
+// the history, one value per entry starting with the window-th one.
+func MovingAverage(prices []*MarketItemPrice, window int) []float64 {
+	if window <= 0 || len(prices) < window {
+		return nil
+	}
+
+	averages := make([]float64, 0, len(prices)-window+1)
+	var sum float64
+	for i, price := range prices {
+		sum += price.Price
+		if i >= window {
+			sum -= prices[i-window].Price
+		}
+		if i >= window-1 {
+			averages = append(averages, sum/float64(window))
+		}
+	}
+
+	return averages
+}
+
+// MedianPrice is the median price of the history, 0 when it is empty.
+func MedianPrice(prices []*MarketItemPrice) float64 {
+	values := make([]float64, 0, len(prices))
+	for _, price := range prices {
+		values = append(values, price.Price)
+	}
+
+	return median(values)
+}
+
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+
+	return sorted[mid]
+}
+
+// RecentPrices keeps the entries of the history of the last days before now.
+func RecentPrices(prices []*MarketItemPrice, days int, now time.Time) []*MarketItemPrice {
+	since := now.Add(-time.Duration(days) * 24 * time.Hour)
+
+	var recent []*MarketItemPrice
+	for _, price := range prices {
+		if t, err := price.Time(); err == nil && !t.Before(since) {
+			recent = append(recent, price)
+		}
+	}
+
+	return recent
+}
+
+// MedianLastDays is the median price of the last days of the history.
+func MedianLastDays(prices []*MarketItemPrice, days int, now time.Time) float64 {
+	return MedianPrice(RecentPrices(prices, days, now))
+}
+
+// TrimOutliers drops the entries further than k median absolute
+// deviations from the median price, 3 is a common choice for k.  The
+// history is returned as is when it is too flat to tell outliers apart.
+func TrimOutliers(prices []*MarketItemPrice, k float64) []*MarketItemPrice {
+	m := MedianPrice(prices)
+
+	deviations := make([]float64, 0, len(prices))
+	for _, price := range prices {
+		deviations = append(deviations, math.Abs(price.Price-m))
+	}
+
+	mad := median(deviations)
+	if mad == 0 {
+		return prices
+	}
+
+	trimmed := make([]*MarketItemPrice, 0, len(prices))
+	for i, price := range prices {
+		if deviations[i] <= k*mad {
+			trimmed = append(trimmed, price)
+		}
+	}
+
+	return trimmed
+}
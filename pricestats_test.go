@@ -0,0 +1,133 @@
+package steam
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// history builds a price history with one sale per hour from the first
+// of March on.
+func history(prices ...float64) []*MarketItemPrice {
+	start := time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)
+
+	var history []*MarketItemPrice
+	for i, price := range prices {
+		history = append(history, &MarketItemPrice{
+			Date:  start.Add(time.Duration(i)*time.Hour).Format(priceHistoryLayout) + " +0",
+			Price: price,
+			Count: "1",
+		})
+	}
+
+	return history
+}
+
+func TestMedianPrice(t *testing.T) {
+	tests := []struct {
+		name   string
+		prices []*MarketItemPrice
+		want   float64
+	}{
+		{"empty", nil, 0},
+		{"single", history(1.5), 1.5},
+		{"odd", history(3, 1, 2), 2},
+		{"even", history(4, 1, 3, 2), 2.5},
+		{"even with duplicates", history(1, 1, 5, 5), 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MedianPrice(tt.prices); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMovingAverage(t *testing.T) {
+	tests := []struct {
+		name   string
+		prices []*MarketItemPrice
+		window int
+		want   []float64
+	}{
+		{"empty", nil, 2, nil},
+		{"single", history(2), 1, []float64{2}},
+		{"shorter than the window", history(1, 2), 3, nil},
+		{"no window", history(1, 2), 0, nil},
+		{"sliding", history(1, 2, 3, 4), 2, []float64{1.5, 2.5, 3.5}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MovingAverage(tt.prices, tt.window); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDailyPrices(t *testing.T) {
+	tests := []struct {
+		name   string
+		prices []*MarketItemPrice
+		want   []float64
+	}{
+		{"empty", nil, nil},
+		{"single", history(2), []float64{2}},
+		{"volume weighted", append(history(1, 3), &MarketItemPrice{Date: "Mar 01 2024 05: +0", Price: 5, Count: "2"}), []float64{3.5}},
+		{"two days", history(make([]float64, 25)...), []float64{0, 0}},
+		{"bad date skipped", []*MarketItemPrice{{Date: "yesterday", Price: 9}}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []float64
+			for _, day := range DailyPrices(tt.prices) {
+				got = append(got, day.Price)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTrimOutliers(t *testing.T) {
+	tests := []struct {
+		name   string
+		prices []*MarketItemPrice
+		want   int
+	}{
+		{"empty", nil, 0},
+		{"single", history(1), 1},
+		{"flat", history(2, 2, 2, 50), 4},
+		{"outlier", history(1, 2, 3, 2, 100), 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := TrimOutliers(tt.prices, 3); len(got) != tt.want {
+				t.Errorf("kept %d entries, want %d", len(got), tt.want)
+			}
+		})
+	}
+}
+
+func TestMedianLastDays(t *testing.T) {
+	prices := history(make([]float64, 72)...)
+	for i, price := range prices {
+		price.Price = float64(i / 24) // 0, 1 and 2 on the three days
+	}
+
+	now := time.Date(2024, time.March, 4, 0, 0, 0, 0, time.UTC)
+	if got := MedianLastDays(prices, 1, now); got != 2 {
+		t.Errorf("last day median %v, want 2", got)
+	}
+
+	if got := MedianLastDays(prices, 1, now.AddDate(0, 0, 7)); got != 0 {
+		t.Errorf("median of no recent sales %v, want 0", got)
+	}
+}
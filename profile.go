@@ -1,10 +1,11 @@
 package steam
 
 import (
-	"encoding/json"
+	"bytes"
 	"errors"
 	"fmt"
 	"io"
+	"mime/multipart"
 	"net/http"
 	"net/url"
 	"strconv"
@@ -23,14 +24,25 @@ const (
 )
 
 const (
-	apiGetPlayerSummaries = APIBaseUrl + "/ISteamUser/GetPlayerSummaries/v0002/?"
-	apiGetOwnedGames      = APIBaseUrl + "/IPlayerService/GetOwnedGames/v0001/?"
-	apiGetPlayerBans      = APIBaseUrl + "/ISteamUser/GetPlayerBans/v1/?"
-	apiGetPlayerFriends   = APIBaseUrl + "/ISteamUser/GetFriendList/v1/?"
-	apiResolveVanityURL   = APIBaseUrl + "/ISteamUser/ResolveVanityURL/v1/?"
+	apiGetPlayerSummaries = "/ISteamUser/GetPlayerSummaries/v0002/?"
+	apiGetOwnedGames      = "/IPlayerService/GetOwnedGames/v0001/?"
+	apiGetRecentlyPlayed  = "/IPlayerService/GetRecentlyPlayedGames/v1/?"
+	apiGetSteamLevel      = "/IPlayerService/GetSteamLevel/v1/?"
+	apiGetBadges          = "/IPlayerService/GetBadges/v1/?"
+	apiGetPlayerBans      = "/ISteamUser/GetPlayerBans/v1/?"
+	apiGetPlayerFriends   = "/ISteamUser/GetFriendList/v1/?"
+	apiResolveVanityURL   = "/ISteamUser/ResolveVanityURL/v1/?"
 )
 
-var ErrCannotFindVanityMatch = errors.New("no match for the vanity URL")
+const (
+	profileEditInfoURL = "/profiles/%d/edit/info"
+	avatarUploadURL    = "/actions/FileUploader"
+)
+
+var (
+	ErrCannotFindVanityMatch = errors.New("no match for the vanity URL")
+	ErrCannotUploadAvatar    = errors.New("unable to upload avatar")
+)
 
 type PlayerSummary struct {
 	SteamID           SteamID `json:"steamid,string"`
@@ -45,6 +57,7 @@ type PlayerSummary struct {
 	AvatarURL         string  `json:"avatar"`
 	AvatarMediumURL   string  `json:"avatarmedium"`
 	AvatarFullURL     string  `json:"avatarfull"`
+	AvatarHash        string  `json:"avatarhash"`
 	PrimaryClanID     uint64  `json:"primaryclanid,string"`
 	TimeCreated       int64   `json:"timecreated"`
 	LocCountryCode    string  `json:"loccountrycode"`
@@ -57,6 +70,8 @@ type PlayerSummary struct {
 
 type Game struct {
 	AppID           uint32 `json:"appid"`
+	Name            string `json:"name,omitempty"`         // include_appinfo / recently played only
+	IconURL         string `json:"img_icon_url,omitempty"` // include_appinfo / recently played only
 	PlaytimeForever int64  `json:"playtime_forever"`
 	Playtime2Weeks  int64  `json:"playtime_2weeks"`
 }
@@ -66,6 +81,30 @@ type OwnedGamesResponse struct {
 	Games []*Game `json:"games"`
 }
 
+type RecentlyPlayedGamesResponse struct {
+	TotalCount uint32  `json:"total_count"`
+	Games      []*Game `json:"games"`
+}
+
+type Badge struct {
+	BadgeID        uint32 `json:"badgeid"`
+	AppID          uint32 `json:"appid,omitempty"`
+	Level          uint32 `json:"level"`
+	CompletionTime int64  `json:"completion_time"`
+	XP             uint32 `json:"xp"`
+	Scarcity       uint32 `json:"scarcity"`
+	CommunityItem  uint64 `json:"communityitemid,string,omitempty"`
+	BorderColor    uint32 `json:"border_color,omitempty"`
+}
+
+type BadgesResponse struct {
+	Badges                     []*Badge `json:"badges"`
+	PlayerXP                   uint32   `json:"player_xp"`
+	PlayerLevel                uint32   `json:"player_level"`
+	PlayerXPNeededToLevelUp    uint32   `json:"player_xp_needed_to_level_up"`
+	PlayerXPNeededCurrentLevel uint32   `json:"player_xp_needed_current_level"`
+}
+
 type PlayerBan struct {
 	SteamID          uint64 `json:"SteamId,string"`
 	CommunityBanned  bool   `json:"CommunityBanned"`
@@ -83,7 +122,7 @@ type Friend struct {
 }
 
 func (session *Session) GetProfileURL() (string, error) {
-	tmpClient := http.Client{Jar: session.client.Jar}
+	tmpClient := http.Client{Transport: session.client.Transport, Jar: session.client.Jar}
 
 	/* We do not follow redirect, we want to know where it'd redirect us.  */
 	tmpClient.CheckRedirect = func(req *http.Request, via []*http.Request) error {
@@ -91,7 +130,7 @@ func (session *Session) GetProfileURL() (string, error) {
 	}
 
 	/* Query normal, this will redirect us.  */
-	resp, err := tmpClient.Get("https://steamcommunity.com/my")
+	resp, err := tmpClient.Get(session.CommunityBaseURL() + "/my")
 	if resp == nil {
 		return "", err
 	}
@@ -110,6 +149,10 @@ func (session *Session) GetProfileURL() (string, error) {
 }
 
 func (session *Session) SetupProfile(profileURL string) error {
+	if err := session.checkWritable(); err != nil {
+		return err
+	}
+
 	resp, err := session.client.Get(profileURL + "/edit?welcomed=1")
 	if resp != nil {
 		defer func(Body io.ReadCloser) {
@@ -132,6 +175,10 @@ func (session *Session) SetupProfile(profileURL string) error {
 }
 
 func (session *Session) SetProfileInfo(profileURL string, values *map[string][]string) error {
+	if err := session.checkWritable(); err != nil {
+		return err
+	}
+
 	(*values)["sessionID"] = []string{session.sessionID}
 	(*values)["type"] = []string{"profileSave"}
 
@@ -157,6 +204,10 @@ func (session *Session) SetProfileInfo(profileURL string, values *map[string][]s
 }
 
 func (session *Session) SetProfilePrivacy(profileURL string, commentPrivacy string, privacy uint8) error {
+	if err := session.checkWritable(); err != nil {
+		return err
+	}
+
 	resp, err := session.client.PostForm(profileURL+"/edit/settings", url.Values{
 		"sessionID":               {session.sessionID},
 		"type":                    {"profileSettings"},
@@ -186,10 +237,9 @@ func (session *Session) SetProfilePrivacy(profileURL string, commentPrivacy stri
 }
 
 func (session *Session) GetPlayerSummaries(steamids string) ([]*PlayerSummary, error) {
-	resp, err := session.client.Get(apiGetPlayerSummaries + url.Values{
-		"key":      {session.apiKey},
+	resp, err := session.client.Get(session.APIBaseURL() + apiGetPlayerSummaries + session.authorize(url.Values{
 		"steamids": {steamids},
-	}.Encode())
+	}).Encode())
 	if resp != nil {
 		defer resp.Body.Close()
 	}
@@ -207,7 +257,7 @@ func (session *Session) GetPlayerSummaries(steamids string) ([]*PlayerSummary, e
 	}
 
 	var response Response
-	if err = json.NewDecoder(resp.Body).Decode(&response); err != nil {
+	if err = session.readJSON(resp, &response); err != nil {
 		return nil, err
 	}
 
@@ -215,13 +265,12 @@ func (session *Session) GetPlayerSummaries(steamids string) ([]*PlayerSummary, e
 }
 
 func (session *Session) GetOwnedGames(sid SteamID, freeGames bool, appInfo bool) (*OwnedGamesResponse, error) {
-	resp, err := session.client.Get(apiGetOwnedGames + url.Values{
-		"key":                       {session.apiKey},
+	resp, err := session.client.Get(session.APIBaseURL() + apiGetOwnedGames + session.authorize(url.Values{
 		"steamid":                   {sid.ToString()},
 		"format":                    {"json"},
 		"include_appinfo":           {strconv.FormatBool(appInfo)},
 		"include_played_free_games": {strconv.FormatBool(freeGames)},
-	}.Encode())
+	}).Encode())
 	if resp != nil {
 		defer resp.Body.Close()
 	}
@@ -235,18 +284,113 @@ func (session *Session) GetOwnedGames(sid SteamID, freeGames bool, appInfo bool)
 	}
 
 	var response Response
-	if err = json.NewDecoder(resp.Body).Decode(&response); err != nil {
+	if err = session.readJSON(resp, &response); err != nil {
+		return nil, err
+	}
+
+	if response.Inner == nil {
+		return nil, ErrInvalidResponse
+	}
+
+	return response.Inner, nil
+}
+
+func (session *Session) GetRecentlyPlayedGames(sid SteamID, count uint32) (*RecentlyPlayedGamesResponse, error) {
+	params := session.authorize(url.Values{
+		"steamid": {sid.ToString()},
+		"format":  {"json"},
+	})
+	if count != 0 {
+		params.Set("count", strconv.FormatUint(uint64(count), 10))
+	}
+
+	resp, err := session.client.Get(session.APIBaseURL() + apiGetRecentlyPlayed + params.Encode())
+	if resp != nil {
+		defer resp.Body.Close()
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	type Response struct {
+		Inner *RecentlyPlayedGamesResponse `json:"response"`
+	}
+
+	var response Response
+	if err = session.readJSON(resp, &response); err != nil {
+		return nil, err
+	}
+
+	if response.Inner == nil {
+		return nil, ErrInvalidResponse
+	}
+
+	return response.Inner, nil
+}
+
+func (session *Session) GetSteamLevel(sid SteamID) (uint32, error) {
+	resp, err := session.client.Get(session.APIBaseURL() + apiGetSteamLevel + session.authorize(url.Values{
+		"steamid": {sid.ToString()},
+		"format":  {"json"},
+	}).Encode())
+	if resp != nil {
+		defer resp.Body.Close()
+	}
+
+	if err != nil {
+		return 0, err
+	}
+
+	type Level struct {
+		PlayerLevel uint32 `json:"player_level"`
+	}
+
+	type Response struct {
+		Inner Level `json:"response"`
+	}
+
+	var response Response
+	if err = session.readJSON(resp, &response); err != nil {
+		return 0, err
+	}
+
+	return response.Inner.PlayerLevel, nil
+}
+
+func (session *Session) GetBadges(sid SteamID) (*BadgesResponse, error) {
+	resp, err := session.client.Get(session.APIBaseURL() + apiGetBadges + session.authorize(url.Values{
+		"steamid": {sid.ToString()},
+		"format":  {"json"},
+	}).Encode())
+	if resp != nil {
+		defer resp.Body.Close()
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	type Response struct {
+		Inner *BadgesResponse `json:"response"`
+	}
+
+	var response Response
+	if err = session.readJSON(resp, &response); err != nil {
 		return nil, err
 	}
 
+	if response.Inner == nil {
+		return nil, ErrInvalidResponse
+	}
+
 	return response.Inner, nil
 }
 
 func (session *Session) GetPlayerBans(steamids string) ([]*PlayerBan, error) {
-	resp, err := session.client.Get(apiGetPlayerBans + url.Values{
-		"key":      {session.apiKey},
+	resp, err := session.client.Get(session.APIBaseURL() + apiGetPlayerBans + session.authorize(url.Values{
 		"steamids": {steamids},
-	}.Encode())
+	}).Encode())
 
 	if err != nil {
 		return nil, err
@@ -265,19 +409,22 @@ func (session *Session) GetPlayerBans(steamids string) ([]*PlayerBan, error) {
 	}
 
 	var response Response
-	if err = json.NewDecoder(resp.Body).Decode(&response); err != nil {
+	if err = session.readJSON(resp, &response); err != nil {
 		return nil, err
 	}
 
+	if response.Inner == nil {
+		return nil, ErrInvalidResponse
+	}
+
 	return response.Inner, nil
 }
 
 func (session *Session) GetFriends(sid SteamID) ([]*Friend, error) {
-	resp, err := session.client.Get(apiGetPlayerFriends + url.Values{
-		"key":     {session.apiKey},
+	resp, err := session.client.Get(session.APIBaseURL() + apiGetPlayerFriends + session.authorize(url.Values{
 		"steamid": {sid.ToString()},
 		"format":  {"json"},
-	}.Encode())
+	}).Encode())
 	if resp != nil {
 		defer func(Body io.ReadCloser) {
 			err := Body.Close()
@@ -300,7 +447,7 @@ func (session *Session) GetFriends(sid SteamID) ([]*Friend, error) {
 	}
 
 	var friendsList FriendsList
-	if err = json.NewDecoder(resp.Body).Decode(&friendsList); err != nil {
+	if err = session.readJSON(resp, &friendsList); err != nil {
 		return nil, err
 	}
 
@@ -308,10 +455,9 @@ func (session *Session) GetFriends(sid SteamID) ([]*Friend, error) {
 }
 
 func (session *Session) ResolveVanityURL(vanityURL string) (uint64, error) {
-	resp, err := session.client.Get(apiResolveVanityURL + url.Values{
-		"key":       {session.apiKey},
+	resp, err := session.client.Get(session.APIBaseURL() + apiResolveVanityURL + session.authorize(url.Values{
 		"vanityurl": {vanityURL},
-	}.Encode())
+	}).Encode())
 	if resp != nil {
 		defer func(Body io.ReadCloser) {
 			err := Body.Close()
@@ -335,7 +481,7 @@ func (session *Session) ResolveVanityURL(vanityURL string) (uint64, error) {
 	}
 
 	var response Response
-	if err = json.NewDecoder(resp.Body).Decode(&response); err != nil {
+	if err = session.readJSON(resp, &response); err != nil {
 		return 0, err
 	}
 
@@ -345,3 +491,201 @@ func (session *Session) ResolveVanityURL(vanityURL string) (uint64, error) {
 
 	return response.Inner.SteamID, nil
 }
+
+// SetPersonaState changes the online status shown to friends, the session
+// needs to be logged into chat first (see ChatLogin).
+func (session *Session) SetPersonaState(state uint8) error {
+	if err := session.checkWritable(); err != nil {
+		return err
+	}
+
+	resp, err := session.client.PostForm(session.APIBaseURL()+apiUserPresenceMessage, url.Values{
+		"access_token":  {session.oauth.Token},
+		"umqid":         {session.umqID},
+		"type":          {MessageTypeStatus},
+		"persona_state": {strconv.FormatUint(uint64(state), 10)},
+	})
+	if resp != nil {
+		defer resp.Body.Close()
+	}
+
+	if err != nil {
+		return err
+	}
+
+	var response ChatResponse
+	if err = session.readJSON(resp, &response); err != nil {
+		return err
+	}
+
+	if response.ErrorMessage != "OK" {
+		return errors.New(response.ErrorMessage)
+	}
+
+	return nil
+}
+
+// getProfileEdit reads the current profile off its edit page.
+func (session *Session) getProfileEdit() (*profileEdit, error) {
+	resp, err := session.client.Get(session.CommunityBaseURL() + fmt.Sprintf(profileEditInfoURL, session.oauth.SteamID))
+	if resp != nil {
+		defer func(Body io.ReadCloser) {
+			err := Body.Close()
+			if err != nil {
+				return
+			}
+		}(resp.Body)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("http error: %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(session.limitBody(resp))
+	if err != nil {
+		return nil, err
+	}
+
+	return parseProfileEdit(body)
+}
+
+// SetProfileName changes the persona name without touching the rest of the
+// profile: a profileSave replaces every field, so the current ones are read
+// first and sent back along with the new name.
+func (session *Session) SetProfileName(name string) error {
+	if err := session.checkWritable(); err != nil {
+		return err
+	}
+
+	edit, err := session.getProfileEdit()
+	if err != nil {
+		return err
+	}
+
+	values := edit.values()
+	values.Set("personaName", name)
+	values.Set("sessionID", session.sessionID)
+	values.Set("type", "profileSave")
+	values.Set("json", "1")
+
+	resp, err := session.client.PostForm(session.CommunityBaseURL()+fmt.Sprintf(profileEditInfoURL, session.oauth.SteamID), values)
+	if resp != nil {
+		defer func(Body io.ReadCloser) {
+			err := Body.Close()
+			if err != nil {
+				return
+			}
+		}(resp.Body)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("http error: %d", resp.StatusCode)
+	}
+
+	type Response struct {
+		Success int    `json:"success"`
+		ErrMsg  string `json:"errmsg"`
+	}
+
+	var response Response
+	if err = session.readJSON(resp, &response); err != nil {
+		return err
+	}
+
+	if response.Success != 1 {
+		return errors.New(response.ErrMsg)
+	}
+
+	return nil
+}
+
+// UploadAvatar replaces the profile avatar with the image read from r,
+// filename is only used to let Steam guess the image format.
+func (session *Session) UploadAvatar(r io.Reader, filename string) error {
+	if err := session.checkWritable(); err != nil {
+		return err
+	}
+
+	body := new(bytes.Buffer)
+	writer := multipart.NewWriter(body)
+
+	fields := map[string]string{
+		"MAX_FILE_SIZE": "1048576",
+		"type":          "player_avatar_image",
+		"sId":           session.oauth.SteamID.ToString(),
+		"sessionid":     session.sessionID,
+		"doSub":         "1",
+		"json":          "1",
+	}
+
+	for key, value := range fields {
+		if err := writer.WriteField(key, value); err != nil {
+			return err
+		}
+	}
+
+	part, err := writer.CreateFormFile("avatar", filename)
+	if err != nil {
+		return err
+	}
+
+	if _, err = io.Copy(part, r); err != nil {
+		return err
+	}
+
+	if err = writer.Close(); err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, session.CommunityBaseURL()+avatarUploadURL, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+
+	resp, err := session.client.Do(req)
+	if resp != nil {
+		defer func(Body io.ReadCloser) {
+			err := Body.Close()
+			if err != nil {
+				return
+			}
+		}(resp.Body)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("http error: %d", resp.StatusCode)
+	}
+
+	type Response struct {
+		Success bool   `json:"success"`
+		Message string `json:"message"`
+	}
+
+	var response Response
+	if err = session.readJSON(resp, &response); err != nil {
+		return err
+	}
+
+	if !response.Success {
+		if len(response.Message) != 0 {
+			return errors.New(response.Message)
+		}
+
+		return ErrCannotUploadAvatar
+	}
+
+	return nil
+}
@@ -0,0 +1,44 @@
+package steam
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func TestSetProfileName(t *testing.T) {
+	page := readFixture(t, "profile_edit.html")
+
+	var saved url.Values
+	session := newTestSession(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			w.Write(page)
+			return
+		}
+
+		r.ParseForm()
+		saved = r.PostForm
+		answer(`{"success":1}`).ServeHTTP(w, r)
+	}))
+
+	if err := session.SetProfileName("new name"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Everything but the name is sent back as it was.
+	want := map[string]string{
+		"personaName": "new name",
+		"real_name":   "Jo & Co",
+		"customURL":   "tradebot",
+		"country":     "DE",
+		"state":       "16",
+		"city":        "9640",
+		"type":        "profileSave",
+		"sessionID":   "sessionid",
+	}
+	for field, value := range want {
+		if got := saved.Get(field); got != value {
+			t.Errorf("%s: got %q, want %q", field, got, value)
+		}
+	}
+}
@@ -0,0 +1,210 @@
+package steam
+
+import (
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sync"
+	"time"
+)
+
+// DefaultKeyDailyLimit is the number of calls Valve allows a WebAPI key per
+// day.
+const DefaultKeyDailyLimit = 100000
+
+// QuotaConfig configures the WebAPI key quota tracking.
+type QuotaConfig struct {
+	DailyLimit int     // calls per key per day, DefaultKeyDailyLimit when 0
+	SoftLimit  float64 // fraction of DailyLimit OnSoftLimit fires at, e.g. 0.9
+
+	// OnSoftLimit is called once per key and day when its usage reaches
+	// the soft limit, from the goroutine making the request.  key is
+	// redacted to its last characters.
+	OnSoftLimit func(key string, usage KeyUsage)
+}
+
+// KeyUsage is the number of calls made with a WebAPI key during the
+// current day (UTC).
+type KeyUsage struct {
+	Day       time.Time `json:"day"`
+	Used      int       `json:"used"`
+	Remaining int       `json:"remaining"`
+}
+
+type keyCounter struct {
+	day      time.Time
+	used     int
+	notified bool
+}
+
+type quotaTransport struct {
+	config QuotaConfig
+	next   http.RoundTripper
+
+	mu   sync.Mutex
+	keys map[string]*keyCounter
+}
+
+// redactKey keeps the last characters of a key, enough to tell keys apart.
+func redactKey(key string) string {
+	if len(key) <= 4 {
+		return key
+	}
+
+	return "..." + key[len(key)-4:]
+}
+
+// webAPIPathExp matches the path of a WebAPI method,
+// /<interface>/<method>/v<version>/, whichever host or base URL serves it.
+var webAPIPathExp = regexp.MustCompile(`/I\w+/\w+/v\d+/?$`)
+
+// requestKey extracts the WebAPI key of a request, from its query or its
+// form body.
+func requestKey(req *http.Request) string {
+	if !webAPIPathExp.MatchString(req.URL.Path) {
+		return ""
+	}
+
+	if key := req.URL.Query().Get("key"); len(key) != 0 {
+		return key
+	}
+
+	if req.Method != http.MethodPost || req.GetBody == nil ||
+		req.Header.Get("Content-Type") != "application/x-www-form-urlencoded" {
+		return ""
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return ""
+	}
+	defer body.Close()
+
+	b, err := io.ReadAll(body)
+	if err != nil {
+		return ""
+	}
+
+	form, err := url.ParseQuery(string(b))
+	if err != nil {
+		return ""
+	}
+
+	return form.Get("key")
+}
+
+func (t *quotaTransport) limit() int {
+	if t.config.DailyLimit <= 0 {
+		return DefaultKeyDailyLimit
+	}
+
+	return t.config.DailyLimit
+}
+
+func (t *quotaTransport) usage(c *keyCounter) KeyUsage {
+	remaining := t.limit() - c.used
+	if remaining < 0 {
+		remaining = 0
+	}
+
+	return KeyUsage{Day: c.day, Used: c.used, Remaining: remaining}
+}
+
+func (t *quotaTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if key := requestKey(req); len(key) != 0 {
+		t.count(key, time.Now())
+	}
+
+	return t.next.RoundTrip(req)
+}
+
+func (t *quotaTransport) count(key string, now time.Time) {
+	day := now.UTC().Truncate(24 * time.Hour)
+
+	t.mu.Lock()
+	c, ok := t.keys[key]
+	if !ok || !c.day.Equal(day) {
+		c = &keyCounter{day: day}
+		t.keys[key] = c
+	}
+	c.used++
+
+	notify := t.config.OnSoftLimit != nil && t.config.SoftLimit > 0 && !c.notified &&
+		float64(c.used) >= t.config.SoftLimit*float64(t.limit())
+	if notify {
+		c.notified = true
+	}
+	usage := t.usage(c)
+	t.mu.Unlock()
+
+	if notify {
+		t.config.OnSoftLimit(redactKey(key), usage)
+	}
+}
+
+func (t *quotaTransport) get(key string) KeyUsage {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	day := time.Now().UTC().Truncate(24 * time.Hour)
+	c, ok := t.keys[key]
+	if !ok || !c.day.Equal(day) {
+		return KeyUsage{Day: day, Remaining: t.limit()}
+	}
+
+	return t.usage(c)
+}
+
+func (t *quotaTransport) all() map[string]KeyUsage {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	day := time.Now().UTC().Truncate(24 * time.Hour)
+	usage := make(map[string]KeyUsage, len(t.keys))
+	for key, c := range t.keys {
+		if c.day.Equal(day) {
+			usage[redactKey(key)] = t.usage(c)
+		}
+	}
+
+	return usage
+}
+
+// EnableKeyQuota counts the WebAPI calls made through the session client
+// per key and day, so that fleets sharing a key can throttle before Valve
+// blocks it.  The counts are local estimates, calls made by other
+// processes with the same key are not seen.
+func (session *Session) EnableKeyQuota(config QuotaConfig) {
+	next := session.client.Transport
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	t := &quotaTransport{config: config, next: next, keys: make(map[string]*keyCounter)}
+	session.client.Transport = t
+	session.quota = t
+}
+
+// KeyQuota returns today's usage of key, the session API key when empty.
+func (session *Session) KeyQuota(key string) KeyUsage {
+	if len(key) == 0 {
+		key = session.apiKey
+	}
+
+	if session.quota == nil {
+		return KeyUsage{Day: time.Now().UTC().Truncate(24 * time.Hour), Remaining: DefaultKeyDailyLimit}
+	}
+
+	return session.quota.get(key)
+}
+
+// KeyQuotas returns today's usage of every key seen, keyed by the redacted
+// key.
+func (session *Session) KeyQuotas() map[string]KeyUsage {
+	if session.quota == nil {
+		return map[string]KeyUsage{}
+	}
+
+	return session.quota.all()
+}
@@ -0,0 +1,39 @@
+package steam
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestRequestKey(t *testing.T) {
+	tests := []struct {
+		name string
+		url  string
+		body string
+		want string
+	}{
+		{"query", "https://api.steampowered.com/IEconService/GetTradeOffers/v1/?key=abc", "", "abc"},
+		{"form", "https://api.steampowered.com/IEconService/DeclineTradeOffer/v1/", "key=abc&tradeofferid=1", "abc"},
+		{"partner host", "https://partner.steam-api.com/ISteamUser/GetPlayerSummaries/v2/?key=abc", "", "abc"},
+		{"proxied base URL", "http://127.0.0.1:8080/steam/ISteamUser/GetPlayerSummaries/v0002/?key=abc", "", "abc"},
+		{"community page", "https://steamcommunity.com/market/?key=abc", "", ""},
+		{"api host, not a method", "https://api.steampowered.com/robots.txt?key=abc", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var req *http.Request
+			if len(tt.body) == 0 {
+				req, _ = http.NewRequest(http.MethodGet, tt.url, nil)
+			} else {
+				req, _ = http.NewRequest(http.MethodPost, tt.url, strings.NewReader(tt.body))
+				req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			}
+
+			if got := requestKey(req); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
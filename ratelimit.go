@@ -0,0 +1,86 @@
+package steam
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// rateLimitTransport spaces the requests of a session, see SetRateLimit.
+type rateLimitTransport struct {
+	next http.RoundTripper
+
+	mu       sync.Mutex
+	interval time.Duration
+	ready    time.Time // when the next request may go
+}
+
+// rateLimitedKey marks the context of a request that waited for its turn
+// already.
+type rateLimitedKey struct{}
+
+// reserve takes the next turn and returns how long to wait for it.
+func (t *rateLimitTransport) reserve(now time.Time) time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	at := t.ready
+	if at.Before(now) {
+		at = now
+	}
+	t.ready = at.Add(t.interval)
+
+	return at.Sub(now)
+}
+
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// The request of a clone went through the limiter of the clone, the
+	// one of the session it was cloned from is further down.
+	if req.Context().Value(rateLimitedKey{}) != nil {
+		return t.next.RoundTrip(req)
+	}
+
+	if wait := t.reserve(time.Now()); wait > 0 {
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		}
+	}
+
+	return t.next.RoundTrip(req.WithContext(context.WithValue(req.Context(), rateLimitedKey{}, true)))
+}
+
+func (t *rateLimitTransport) getInterval() time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.interval
+}
+
+// SetRateLimit spaces the requests of the session at least interval apart,
+// 0 lifts the limit.  Clones pace their requests with a limiter of their
+// own, set to the same interval.
+func (session *Session) SetRateLimit(interval time.Duration) {
+	if t := session.limiter; t != nil {
+		t.mu.Lock()
+		t.interval = interval
+		t.mu.Unlock()
+		return
+	}
+
+	if interval <= 0 {
+		return
+	}
+
+	next := session.client.Transport
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	session.limiter = &rateLimitTransport{next: next, interval: interval}
+	session.client.Transport = session.limiter
+}
@@ -0,0 +1,24 @@
+package steam
+
+import "errors"
+
+var ErrReadOnly = errors.New("session is read-only")
+
+// SetReadOnly makes every call that changes something on the account
+// (trades, market, confirmations, profile, security settings...) fail with
+// ErrReadOnly, reads and logging in keep working.
+func (session *Session) SetReadOnly(readOnly bool) {
+	session.readOnly = readOnly
+}
+
+func (session *Session) IsReadOnly() bool {
+	return session.readOnly
+}
+
+func (session *Session) checkWritable() error {
+	if session.readOnly {
+		return ErrReadOnly
+	}
+
+	return nil
+}
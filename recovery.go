@@ -0,0 +1,303 @@
+package steam
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+const (
+	helpWizardURL = helpBaseURL + "/en/wizard/"
+
+	storeManageActionURL = "https://store.steampowered.com/twofactor/manage_action"
+
+	// recoveryMethodMobile is the "use the code of my mobile
+	// authenticator" recovery method.
+	recoveryMethodMobile = "8"
+
+	issueChangePassword = "406"
+	issueChangeEmail    = "409"
+)
+
+var (
+	ErrRecoveryFailed = errors.New("account recovery step failed")
+	ErrNoCodeCallback = errors.New("email change needs a callback for the confirmation code")
+)
+
+// recoveryFlow is a help site wizard session, s identifies it in every
+// step.
+type recoveryFlow struct {
+	session *Session
+	s       string
+	account string
+	issueID string
+}
+
+// helpWizardResponse is the shape every wizard AJAX step answers with.
+type helpWizardResponse struct {
+	Success  *bool  `json:"success"`
+	ErrorMsg string `json:"errorMsg"`
+	Hash     string `json:"hash"`
+}
+
+func (r *helpWizardResponse) err(step string) error {
+	if len(r.ErrorMsg) != 0 {
+		return fmt.Errorf("%s: %s", step, r.ErrorMsg)
+	}
+
+	if r.Success != nil && !*r.Success {
+		return fmt.Errorf("%s: %w", step, ErrRecoveryFailed)
+	}
+
+	return nil
+}
+
+func (f *recoveryFlow) do(step, method string, params url.Values) error {
+	params.Set("sessionid", f.session.sessionID)
+	params.Set("wizard_ajax", "1")
+	params.Set("gamepad", "0")
+	params.Set("s", f.s)
+
+	var resp *http.Response
+	var err error
+	if method == http.MethodGet {
+		resp, err = f.session.client.Get(helpWizardURL + step + "?" + params.Encode())
+	} else {
+		resp, err = f.session.client.PostForm(helpWizardURL+step, params)
+	}
+	if resp != nil {
+		defer func(Body io.ReadCloser) {
+			err := Body.Close()
+			if err != nil {
+				return
+			}
+		}(resp.Body)
+	}
+
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("http error: %d", resp.StatusCode)
+	}
+
+	var response helpWizardResponse
+	if err = f.session.readJSON(resp, &response); err != nil {
+		return err
+	}
+
+	return response.err(step)
+}
+
+// startRecovery opens wizard, which redirects to the recovery of the
+// logged in account with a fresh wizard session.
+func (session *Session) startRecovery(wizard, issueID string) (*recoveryFlow, error) {
+	if err := session.checkWritable(); err != nil {
+		return nil, err
+	}
+
+	session.PrepareForHelpSite()
+
+	resp, err := session.client.Get(helpWizardURL + wizard + "?redir=store/account/")
+	if resp != nil {
+		defer func(Body io.ReadCloser) {
+			err := Body.Close()
+			if err != nil {
+				return
+			}
+		}(resp.Body)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("http error: %d", resp.StatusCode)
+	}
+
+	query := resp.Request.URL.Query()
+	if len(query.Get("s")) == 0 || len(query.Get("account")) == 0 {
+		return nil, ErrInvalidResponse
+	}
+
+	return &recoveryFlow{
+		session: session,
+		s:       query.Get("s"),
+		account: query.Get("account"),
+		issueID: issueID,
+	}, nil
+}
+
+// verifyTwoFactor proves ownership with a mobile authenticator code, then
+// moves on to the password step.
+func (f *recoveryFlow) verifyTwoFactor(sharedSecret string, timeOffset time.Duration) error {
+	err := f.do("AjaxSendAccountRecoveryCode", http.MethodPost, url.Values{
+		"method": {recoveryMethodMobile},
+		"link":   {""},
+		"n":      {"1"},
+	})
+	if err != nil {
+		return err
+	}
+
+	code, err := GenerateTwoFactorCode(sharedSecret, time.Now().Add(timeOffset).Unix())
+	if err != nil {
+		return err
+	}
+
+	err = f.do("AjaxVerifyAccountRecoveryCode", http.MethodGet, url.Values{
+		"code":    {code},
+		"reset":   {"1"},
+		"lost":    {"0"},
+		"method":  {recoveryMethodMobile},
+		"issueid": {f.issueID},
+	})
+	if err != nil {
+		return err
+	}
+
+	return f.do("AjaxAccountRecoveryGetNextStep", http.MethodGet, url.Values{
+		"account": {f.account},
+		"reset":   {"1"},
+		"lost":    {"2"},
+		"issueid": {f.issueID},
+	})
+}
+
+// encryptedPassword encrypts password for the account the way Login does.
+func encryptedPassword(accountName, password string) (string, string, error) {
+	key, err := getRSAKey(accountName)
+	if key == nil {
+		return "", "", err
+	}
+
+	crypt, err := encryptPasword(password, key)
+	if err != nil {
+		return "", "", err
+	}
+
+	return crypt, strconv.FormatUint(key.GetTimestamp(), 10), nil
+}
+
+func (f *recoveryFlow) verifyPassword(accountName, password string) error {
+	crypt, timestamp, err := encryptedPassword(accountName, password)
+	if err != nil {
+		return err
+	}
+
+	return f.do("AjaxAccountRecoveryVerifyPassword", http.MethodPost, url.Values{
+		"lost":         {"2"},
+		"reset":        {"1"},
+		"password":     {crypt},
+		"rsatimestamp": {timestamp},
+	})
+}
+
+// ChangePassword changes the password of the logged in account through the
+// help site wizard, proving ownership with a code of the mobile
+// authenticator and the current password.  Other sessions keep working
+// until their tokens expire, see DeauthorizeAllDevices.
+func (session *Session) ChangePassword(accountName, oldPassword, newPassword, sharedSecret string, timeOffset time.Duration) error {
+	flow, err := session.startRecovery("HelpChangePassword", issueChangePassword)
+	if err != nil {
+		return err
+	}
+
+	if err = flow.verifyTwoFactor(sharedSecret, timeOffset); err != nil {
+		return err
+	}
+
+	if err = flow.verifyPassword(accountName, oldPassword); err != nil {
+		return err
+	}
+
+	crypt, timestamp, err := encryptedPassword(accountName, newPassword)
+	if err != nil {
+		return err
+	}
+
+	return flow.do("AjaxAccountRecoveryChangePassword", http.MethodPost, url.Values{
+		"account":      {flow.account},
+		"password":     {crypt},
+		"rsatimestamp": {timestamp},
+	})
+}
+
+// ChangeEmail moves the logged in account to newEmail.  Steam mails a code
+// to the new address to confirm it, code is called to get it once sent
+// and must not be nil.
+func (session *Session) ChangeEmail(accountName, password, newEmail, sharedSecret string, timeOffset time.Duration, code func() (string, error)) error {
+	// Checked first, the address would be left half changed otherwise.
+	if code == nil {
+		return ErrNoCodeCallback
+	}
+
+	flow, err := session.startRecovery("HelpChangeEmail", issueChangeEmail)
+	if err != nil {
+		return err
+	}
+
+	if err = flow.verifyTwoFactor(sharedSecret, timeOffset); err != nil {
+		return err
+	}
+
+	if err = flow.verifyPassword(accountName, password); err != nil {
+		return err
+	}
+
+	err = flow.do("AjaxAccountRecoveryChangeEmail", http.MethodPost, url.Values{
+		"account": {flow.account},
+		"email":   {newEmail},
+	})
+	if err != nil {
+		return err
+	}
+
+	c, err := code()
+	if err != nil {
+		return err
+	}
+
+	return flow.do("AjaxAccountRecoveryConfirmChangeEmail", http.MethodPost, url.Values{
+		"account":           {flow.account},
+		"email":             {newEmail},
+		"email_change_code": {c},
+	})
+}
+
+// DeauthorizeAllDevices logs out every other device and session of the
+// account, this one included once its cookies expire.
+func (session *Session) DeauthorizeAllDevices() error {
+	if err := session.checkWritable(); err != nil {
+		return err
+	}
+
+	session.PrepareForSteamStore()
+
+	resp, err := session.client.PostForm(storeManageActionURL, url.Values{
+		"action":    {"deauthorize"},
+		"sessionid": {session.sessionID},
+	})
+	if resp != nil {
+		defer func(Body io.ReadCloser) {
+			err := Body.Close()
+			if err != nil {
+				return
+			}
+		}(resp.Body)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("http error: %d", resp.StatusCode)
+	}
+
+	return nil
+}
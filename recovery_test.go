@@ -0,0 +1,19 @@
+package steam
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestChangeEmailNeedsCode(t *testing.T) {
+	session := newTestSession(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request to %s", r.URL)
+		http.NotFound(w, r)
+	}))
+
+	err := session.ChangeEmail("user", "password", "new@example.com", "", 0, nil)
+	if !errors.Is(err, ErrNoCodeCallback) {
+		t.Errorf("got %v, want ErrNoCodeCallback", err)
+	}
+}
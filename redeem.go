@@ -0,0 +1,139 @@
+package steam
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+var (
+	ErrKeyAlreadyOwned  = errors.New("the account already owns the product of the key")
+	ErrKeyRegionLocked  = errors.New("the key cannot be activated in this region")
+	ErrKeyInvalid       = errors.New("invalid product key")
+	ErrKeyAlreadyUsed   = errors.New("product key was already activated")
+	ErrKeyMissingBase   = errors.New("the key requires a game the account does not own")
+	ErrKeyIsWalletCode  = errors.New("the key is a wallet code, use RedeemWalletCode")
+	ErrKeyRateLimited   = errors.New("too many key activation attempts, try again later")
+	ErrWalletCodeFailed = errors.New("cannot redeem wallet code")
+)
+
+// WalletCodeResult is what redeeming a wallet code added to the account.
+type WalletCodeResult struct {
+	Amount     uint64 // in cents of Currency
+	Currency   string // one of the Currency* constants, empty when unknown
+	NewBalance string // formatted, e.g. "12,34€"
+}
+
+// RedeemWalletCode adds a wallet code to the account balance.  The
+// currency is looked up with GetWalletBalance afterwards and left empty
+// when that fails, as the code has been redeemed by then.
+func (session *Session) RedeemWalletCode(code string) (*WalletCodeResult, error) {
+	if err := session.checkWritable(); err != nil {
+		return nil, err
+	}
+
+	var response struct {
+		Success    int        `json:"success"`
+		Detail     int        `json:"detail"`
+		Amount     flexNumber `json:"amount"`
+		NewBalance string     `json:"formattednewwalletbalance"`
+	}
+	err := session.postStoreForm("https://store.steampowered.com/account/ajaxredeemwalletcode/", url.Values{
+		"wallet_code": {code},
+	}, &response)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.Success != 1 {
+		return nil, fmt.Errorf("%w: detail %d", ErrWalletCodeFailed, response.Detail)
+	}
+
+	result := &WalletCodeResult{
+		Amount:     uint64(response.Amount),
+		NewBalance: response.NewBalance,
+	}
+	if wallet, err := session.GetWalletBalance(); err == nil {
+		result.Currency = wallet.Currency
+	}
+
+	return result, nil
+}
+
+// KeyActivationError is returned by RegisterCDKey when Steam refuses a
+// key, Detail being its EPurchaseResultDetail.  It matches the ErrKey*
+// errors with errors.Is where applicable.
+type KeyActivationError struct {
+	Detail int
+}
+
+func (e *KeyActivationError) Error() string {
+	return "key activation failed: detail " + strconv.Itoa(e.Detail)
+}
+
+func (e *KeyActivationError) Is(target error) bool {
+	switch target {
+	case ErrKeyAlreadyOwned:
+		return e.Detail == 9 // AlreadyPurchased
+	case ErrKeyRegionLocked:
+		return e.Detail == 13 // RestrictedCountry
+	case ErrKeyInvalid:
+		return e.Detail == 14 // BadActivationCode
+	case ErrKeyAlreadyUsed:
+		return e.Detail == 15 // DuplicateActivationCode
+	case ErrKeyMissingBase:
+		return e.Detail == 24 // DoesNotOwnRequiredApp
+	case ErrKeyIsWalletCode:
+		return e.Detail == 50 // CannotRedeemCodeFromClient
+	case ErrKeyRateLimited:
+		return e.Detail == 53 // AccountLocked, too many activation attempts
+	}
+
+	return false
+}
+
+// ActivatedPackage is a package a product key added to the account.
+type ActivatedPackage struct {
+	PackageID   uint32
+	Description string
+}
+
+// RegisterCDKey activates a product key on the account and returns the
+// packages it granted, failures are *KeyActivationError.
+func (session *Session) RegisterCDKey(key string) ([]*ActivatedPackage, error) {
+	if err := session.checkWritable(); err != nil {
+		return nil, err
+	}
+
+	var response struct {
+		Success int `json:"success"`
+		Detail  int `json:"purchase_result_details"`
+		Receipt struct {
+			LineItems []struct {
+				PackageID   flexNumber `json:"packageid"`
+				Description string     `json:"line_item_description"`
+			} `json:"line_items"`
+		} `json:"purchase_receipt_info"`
+	}
+	err := session.postStoreForm("https://store.steampowered.com/account/ajaxregisterkey/", url.Values{
+		"product_key": {key},
+	}, &response)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.Success != 1 {
+		return nil, &KeyActivationError{Detail: response.Detail}
+	}
+
+	packages := make([]*ActivatedPackage, 0, len(response.Receipt.LineItems))
+	for _, item := range response.Receipt.LineItems {
+		packages = append(packages, &ActivatedPackage{
+			PackageID:   uint32(item.PackageID),
+			Description: item.Description,
+		})
+	}
+
+	return packages, nil
+}
@@ -0,0 +1,121 @@
+package steam
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/hiship/go-steam/currency"
+)
+
+var ErrRegionMismatch = errors.New("wallet currency, wallet country and store region do not match")
+
+// RegionReport compares the regions Steam associates with the account,
+// any mismatch shows up in Warnings.
+type RegionReport struct {
+	WalletCurrency string // one of the Currency* constants
+	WalletCountry  string // country code of the wallet
+	StoreCountry   string // country code the store derives from the IP address
+	Warnings       []string
+}
+
+// storeCountry reads the steamCountry cookie the store sets from the
+// client IP address, "US|<signature>".
+func (session *Session) storeCountry() (string, error) {
+	if _, err := session.getPage("https://store.steampowered.com/"); err != nil {
+		return "", err
+	}
+
+	store, _ := url.Parse("https://store.steampowered.com")
+	for _, cookie := range session.client.Jar.Cookies(store) {
+		if cookie.Name != "steamCountry" {
+			continue
+		}
+
+		value, err := url.QueryUnescape(cookie.Value)
+		if err != nil {
+			value = cookie.Value
+		}
+
+		country, _, _ := strings.Cut(value, "|")
+		return country, nil
+	}
+
+	return "", ErrInvalidResponse
+}
+
+// CheckRegion warns about wallet currency, wallet country and IP region
+// mismatches, which make market prices silently wrong and purchases fail.
+func (session *Session) CheckRegion() (*RegionReport, error) {
+	wallet, err := session.GetWalletBalance()
+	if err != nil {
+		return nil, err
+	}
+
+	store, err := session.storeCountry()
+	if err != nil {
+		return nil, err
+	}
+
+	report := &RegionReport{
+		WalletCurrency: wallet.Currency,
+		WalletCountry:  wallet.Country,
+		StoreCountry:   store,
+	}
+
+	if len(wallet.Country) != 0 && wallet.Country != store {
+		report.Warnings = append(report.Warnings, fmt.Sprintf(
+			"wallet country %s differs from the store country %s of this IP address",
+			wallet.Country, store,
+		))
+	}
+
+	if id, ok := currency.ForCountry(wallet.Country); ok && id.Param() != wallet.Currency {
+		report.Warnings = append(report.Warnings, fmt.Sprintf(
+			"wallet currency %s is not the currency of wallet country %s",
+			wallet.Currency, wallet.Country,
+		))
+	}
+
+	return report, nil
+}
+
+// SetRegionCheck makes SellItem and PlaceBuyOrder run CheckRegion once and
+// log its warnings, strict turns them into ErrRegionMismatch instead.
+func (session *Session) SetRegionCheck(enabled, strict bool) {
+	session.regionMu.Lock()
+	defer session.regionMu.Unlock()
+
+	session.regionCheck = enabled
+	session.regionStrict = strict
+	session.regionReport = nil
+}
+
+// checkRegion runs the check once, concurrent listings wait for it.
+func (session *Session) checkRegion() error {
+	session.regionMu.Lock()
+	defer session.regionMu.Unlock()
+
+	if !session.regionCheck {
+		return nil
+	}
+
+	if session.regionReport == nil {
+		report, err := session.CheckRegion()
+		if err != nil {
+			return err
+		}
+
+		for _, warning := range report.Warnings {
+			session.log().Warnf("region check: %s", warning)
+		}
+		session.regionReport = report
+	}
+
+	if session.regionStrict && len(session.regionReport.Warnings) != 0 {
+		return ErrRegionMismatch
+	}
+
+	return nil
+}
@@ -0,0 +1,48 @@
+package steam
+
+import (
+	"errors"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestCheckRegionConcurrent(t *testing.T) {
+	var wallets int32
+	session := newTestSession(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Host {
+		case "store.steampowered.com":
+			http.SetCookie(w, &http.Cookie{Name: "steamCountry", Value: url.QueryEscape("DE|signature"), Path: "/"})
+		default:
+			atomic.AddInt32(&wallets, 1)
+			w.Write([]byte(`<script>var g_rgWalletInfo = {"success":1,"wallet_currency":1,"wallet_country":"DE","wallet_balance":"100"};</script>`))
+		}
+	}))
+	session.client.Jar, _ = cookiejar.New(nil)
+	session.SetRegionCheck(true, true)
+
+	var wg sync.WaitGroup
+	errs := make([]error, 8)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = session.checkRegion()
+		}(i)
+	}
+	wg.Wait()
+
+	// USD is not the currency of a German wallet.
+	for _, err := range errs {
+		if !errors.Is(err, ErrRegionMismatch) {
+			t.Errorf("got %v, want ErrRegionMismatch", err)
+		}
+	}
+
+	if n := atomic.LoadInt32(&wallets); n != 1 {
+		t.Errorf("the wallet was read %d times, want once", n)
+	}
+}
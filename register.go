@@ -0,0 +1,215 @@
+package steam
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"time"
+)
+
+const (
+	joinURL              = "https://store.steampowered.com/join/"
+	joinVerifyEmailURL   = joinURL + "ajaxverifyemail"
+	joinCheckVerifiedURL = joinURL + "ajaxcheckemailverified"
+	joinCheckAvailURL    = joinURL + "checkavail/"
+	joinCreateAccountURL = joinURL + "createaccount/"
+
+	// joinVerifyInterval is how often the mail verification is checked
+	// once the link was followed.
+	joinVerifyInterval = 3 * time.Second
+)
+
+var (
+	ErrAccountNameTaken = errors.New("account name is not available")
+	ErrEmailNotVerified = errors.New("account email was not verified in time")
+)
+
+// JoinError is returned when a step of the account creation fails, Message
+// is what Steam said when it said anything.
+type JoinError struct {
+	Step    string
+	Message string
+}
+
+func (e *JoinError) Error() string {
+	if len(e.Message) != 0 {
+		return "create account: " + e.Step + ": " + e.Message
+	}
+
+	return "create account: " + e.Step + " failed"
+}
+
+// postJoin POSTs one step of the join flow and decodes its answer.
+func (session *Session) postJoin(endpoint string, params url.Values, response interface{}) error {
+	resp, err := session.client.PostForm(endpoint, params)
+	if resp != nil {
+		defer func(Body io.ReadCloser) {
+			err := Body.Close()
+			if err != nil {
+				return
+			}
+		}(resp.Body)
+	}
+
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("http error: %d", resp.StatusCode)
+	}
+
+	return session.readJSON(resp, response)
+}
+
+// CreateAccount registers a new Steam account through the store join
+// flow and returns a session logged into it.  The captcha of the flow is
+// read by solver and the verification mail sent to email is followed
+// through emailCodes, which is also set on the returned session.
+func CreateAccount(client *http.Client, accountName, email, password string, solver CaptchaSolver, emailCodes EmailCodeProvider) (*Session, error) {
+	if emailCodes == nil {
+		return nil, errors.New("create account: an email code provider is required")
+	}
+
+	if client == nil {
+		client = &http.Client{}
+	}
+
+	if client.Jar == nil {
+		jar, err := cookiejar.New(nil)
+		if err != nil {
+			return nil, err
+		}
+
+		client.Jar = jar
+	}
+
+	session := NewSession(client, "")
+	session.SetCaptchaSolver(solver)
+	session.SetEmailCodeProvider(emailCodes, 0)
+
+	if err := session.createAccount(accountName, email, password); err != nil {
+		return nil, err
+	}
+
+	if err := session.Login(accountName, password, "", 0); err != nil {
+		return nil, fmt.Errorf("account created, login failed: %w", err)
+	}
+
+	return session, nil
+}
+
+func (session *Session) createAccount(accountName, email, password string) error {
+	// The store hands out the cookies of the flow on the join page.
+	if _, err := session.getPage(joinURL); err != nil {
+		return err
+	}
+
+	var avail struct {
+		Available bool `json:"bAvailable"`
+	}
+	err := session.postJoin(joinCheckAvailURL, url.Values{"accountname": {accountName}, "count": {"1"}}, &avail)
+	if err != nil {
+		return err
+	}
+	if !avail.Available {
+		return ErrAccountNameTaken
+	}
+
+	gid, err := session.refreshCaptcha()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), session.emailTimeout)
+	defer cancel()
+
+	text, err := session.solveCaptcha(ctx, storeCaptchaRenderURL, gid)
+	if err != nil {
+		return err
+	}
+
+	start := time.Now()
+	var verify struct {
+		Success   int    `json:"success"`
+		SessionID string `json:"sessionid"` // the creation session
+		Details   string `json:"details"`
+	}
+	err = session.postJoin(joinVerifyEmailURL, url.Values{
+		"email":        {email},
+		"captchagid":   {gid},
+		"captcha_text": {text},
+		"elang":        {"0"},
+	}, &verify)
+	if err != nil {
+		return err
+	}
+	if verify.Success != 1 {
+		return &JoinError{Step: "verify email", Message: verify.Details}
+	}
+
+	if err = session.confirmByEmail("", start); err != nil {
+		return err
+	}
+
+	if err = session.waitEmailVerified(ctx, verify.SessionID); err != nil {
+		return err
+	}
+
+	var created struct {
+		Success bool   `json:"bSuccess"`
+		Details string `json:"details"`
+	}
+	err = session.postJoin(joinCreateAccountURL, url.Values{
+		"accountname":        {accountName},
+		"password":           {password},
+		"count":              {"32"},
+		"lt":                 {"0"},
+		"creation_sessionid": {verify.SessionID},
+		"embedded_appid":     {"0"},
+		"guest":              {"false"},
+	}, &created)
+	if err != nil {
+		return err
+	}
+	if !created.Success {
+		return &JoinError{Step: "create account", Message: created.Details}
+	}
+
+	return nil
+}
+
+// waitEmailVerified polls until Steam saw the verification link opened.
+func (session *Session) waitEmailVerified(ctx context.Context, creationID string) error {
+	ticker := time.NewTicker(joinVerifyInterval)
+	defer ticker.Stop()
+
+	for {
+		var check struct {
+			Success int `json:"success"`
+		}
+		err := session.postJoin(joinCheckVerifiedURL, url.Values{"creationid": {creationID}}, &check)
+		if err != nil {
+			return err
+		}
+
+		// 1 verified, 36 still waiting for the link, anything else
+		// failed.
+		switch check.Success {
+		case 1:
+			return nil
+		case 36:
+		default:
+			return &JoinError{Step: "check email", Message: fmt.Sprintf("eresult %d", check.Success)}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ErrEmailNotVerified
+		case <-ticker.C:
+		}
+	}
+}
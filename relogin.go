@@ -0,0 +1,203 @@
+package steam
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hiship/go-steam/pb"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	clientJSTokenURL = "/chat/clientjstoken"
+
+	defaultReloginInterval = time.Minute
+)
+
+// ErrSessionExpired is returned by requests Steam answered with the login
+// page redirect when the session could not log in again, see
+// SetAutoRelogin.
+var ErrSessionExpired = errors.New("session expired")
+
+// ReloginPolicy is what SetAutoRelogin logs in again with: the refresh
+// token of the last login first, then the credentials when set.
+type ReloginPolicy struct {
+	AccountName  string
+	Password     string
+	SharedSecret string // needed when the account has the mobile authenticator
+	TimeOffset   time.Duration
+
+	// MinInterval is the least time between two logins, requests failing
+	// sooner get ErrSessionExpired.  Defaults to a minute.
+	MinInterval time.Duration
+}
+
+// IsLoggedIn asks the community whether the session cookies are still
+// logged in, unlike IsLogged which only looks at the expiry time.
+func (session *Session) IsLoggedIn() (bool, error) {
+	var response struct {
+		LoggedIn bool    `json:"logged_in"`
+		SteamID  SteamID `json:"steamid,string"`
+	}
+
+	resp, err := session.client.Get(session.CommunityBaseURL() + clientJSTokenURL)
+	if err != nil {
+		if errors.Is(err, ErrSessionExpired) {
+			return false, nil
+		}
+
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("http error: %d", resp.StatusCode)
+	}
+
+	if err = decodeJSON(session.limitBody(resp), &response); err != nil {
+		return false, err
+	}
+
+	return response.LoggedIn && response.SteamID == session.oauth.SteamID, nil
+}
+
+// Relogin logs in again with the refresh token of the session, falling
+// back to the credentials of the auto-relogin policy.
+func (session *Session) Relogin() error {
+	policy := session.reloginPolicy
+	if refreshToken := session.GetRefreshToken(); len(refreshToken) != 0 {
+		err := session.finalizeLogin(&pb.CAuthentication_PollAuthSessionStatus_Response{
+			RefreshToken: proto.String(refreshToken),
+		})
+		if err == nil {
+			session.addMobileAuthCookies()
+			return nil
+		}
+
+		session.log().Warnf("relogin with the refresh token failed: %v", err)
+	}
+
+	if policy == nil || len(policy.AccountName) == 0 {
+		return ErrSessionExpired
+	}
+
+	if err := session.Login(policy.AccountName, policy.Password, policy.SharedSecret, policy.TimeOffset); err != nil {
+		return fmt.Errorf("%w: %v", ErrSessionExpired, err)
+	}
+
+	return nil
+}
+
+type reloginTransport struct {
+	session *Session
+	next    http.RoundTripper
+
+	mu          sync.Mutex
+	lastRelogin time.Time
+}
+
+// loggedOut tells whether resp is the redirect Steam answers requests of a
+// logged out session with.
+func (t *reloginTransport) loggedOut(req *http.Request, resp *http.Response) bool {
+	if req.URL.Host != t.session.communityURL().Host && req.URL.Hostname() != "store.steampowered.com" {
+		return false
+	}
+
+	if resp.StatusCode < 300 || resp.StatusCode >= 400 {
+		return false
+	}
+
+	location, err := resp.Location()
+	if err != nil {
+		return false
+	}
+
+	return strings.HasPrefix(location.Path, "/login") && !strings.HasPrefix(req.URL.Path, "/login")
+}
+
+// relogin logs in again unless another request did since start.
+func (t *reloginTransport) relogin(start time.Time) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.lastRelogin.After(start) {
+		return nil
+	}
+
+	interval := defaultReloginInterval
+	if policy := t.session.reloginPolicy; policy != nil && policy.MinInterval > 0 {
+		interval = policy.MinInterval
+	}
+	if time.Since(t.lastRelogin) < interval {
+		return ErrSessionExpired
+	}
+
+	t.lastRelogin = time.Now()
+	return t.session.Relogin()
+}
+
+func (t *reloginTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	if err != nil || !t.loggedOut(req, resp) {
+		return resp, err
+	}
+
+	if req.Body != nil && req.GetBody == nil {
+		resp.Body.Close()
+		return nil, ErrSessionExpired
+	}
+
+	t.session.log().Infof("%s %s: logged out, logging in again", req.Method, req.URL.Redacted())
+	if err = t.relogin(start); err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+	resp.Body.Close()
+
+	/* The client added the cookies of the old login, the retry carries
+	 * those of the jar the login filled.  */
+	retry := req.Clone(req.Context())
+	retry.Header.Del("Cookie")
+	if jar := t.session.client.Jar; jar != nil {
+		for _, cookie := range jar.Cookies(req.URL) {
+			retry.AddCookie(cookie)
+		}
+	}
+	if req.GetBody != nil {
+		if retry.Body, err = req.GetBody(); err != nil {
+			return nil, err
+		}
+	}
+
+	if resp, err = t.next.RoundTrip(retry); err != nil || !t.loggedOut(retry, resp) {
+		return resp, err
+	}
+
+	resp.Body.Close()
+	return nil, ErrSessionExpired
+}
+
+// SetAutoRelogin makes requests that land on the login page log in again
+// and retry once, failing with ErrSessionExpired when that is not
+// possible.  A nil policy only relies on the refresh token of the session.
+// Clones share the transport, it is the session SetAutoRelogin was called
+// on that logs in again.
+func (session *Session) SetAutoRelogin(policy *ReloginPolicy) {
+	session.reloginPolicy = policy
+	if session.relogin != nil {
+		return
+	}
+
+	next := session.client.Transport
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	session.relogin = &reloginTransport{session: session, next: next}
+	session.client.Transport = session.relogin
+}
@@ -0,0 +1,92 @@
+package steam
+
+import (
+	"errors"
+	"strconv"
+)
+
+var ErrGiveNotAllowed = errors.New("offer gives away items and does not match the give policy")
+
+// PriceProvider values a single item in cents of whatever currency the
+// policy MaxValue is expressed in.
+type PriceProvider interface {
+	ItemValue(item *EconItem) (uint64, error)
+}
+
+// GivePolicy decides whether an offer that takes items from us may be
+// accepted or confirmed.  An offer passes when any of the rules below
+// allows it, offers that do not give anything always pass.
+type GivePolicy struct {
+	Partners map[uint32]bool        // whitelisted partner account IDs
+	Prices   PriceProvider          // used together with MaxValue
+	MaxValue uint64                 // maximum total value given, 0 disables the rule
+	Override func(*TradeOffer) bool // explicit per-offer escape hatch
+}
+
+func (policy *GivePolicy) Check(offer *TradeOffer) error {
+	if len(offer.SendItems) == 0 {
+		return nil
+	}
+
+	if policy.Override != nil && policy.Override(offer) {
+		return nil
+	}
+
+	if policy.Partners[offer.Partner] {
+		return nil
+	}
+
+	if policy.Prices != nil && policy.MaxValue != 0 {
+		var total uint64
+		for _, item := range offer.SendItems {
+			value, err := policy.Prices.ItemValue(item)
+			if err != nil {
+				return err
+			}
+
+			total += value
+		}
+
+		if total <= policy.MaxValue {
+			return nil
+		}
+	}
+
+	return ErrGiveNotAllowed
+}
+
+// SetGivePolicy installs a policy that is checked before accepting offers
+// and allowing trade confirmations, nil disables the check.
+func (session *Session) SetGivePolicy(policy *GivePolicy) {
+	session.givePolicy = policy
+}
+
+func (session *Session) checkGivePolicy(offerID uint64) error {
+	if session.givePolicy == nil {
+		return nil
+	}
+
+	offer, err := session.GetTradeOffer(offerID)
+	if err != nil {
+		return err
+	}
+
+	if offer == nil {
+		return ErrGiveNotAllowed
+	}
+
+	return session.givePolicy.Check(offer)
+}
+
+func (session *Session) checkConfirmationGivePolicy(confirmation *Confirmation, answer string) error {
+	if session.givePolicy == nil || answer != "allow" || confirmation.Type != ConfirmationTypeTrade {
+		return nil
+	}
+
+	offerID, err := strconv.ParseUint(confirmation.Creator, 10, 64)
+	if err != nil {
+		return err
+	}
+
+	return session.checkGivePolicy(offerID)
+}
@@ -0,0 +1,256 @@
+package steam
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const featuredCategoriesURL = "https://store.steampowered.com/api/featuredcategories/?cc=us&l=english"
+
+// MajorSaleMinSpecials is how many featured specials have to end at the
+// same time for the store to be considered in a major sale, outside of
+// those the specials end on scattered dates.
+var MajorSaleMinSpecials = 10
+
+var ErrMarketPaused = errors.New("automatic market actions are paused during the start of a sale")
+
+// SaleStatus is what the store featured categories tell about the current
+// sales.
+type SaleStatus struct {
+	Major      bool      // a store wide sale is running
+	Specials   int       // discounted items featured on the front page
+	EndsAt     time.Time // end of the major sale, zero when there is none
+	CheckedAt  time.Time
+	Spotlights []string // names of the spotlight categories, usually the sale name
+}
+
+type featuredItem struct {
+	ID                 uint32 `json:"id"`
+	Name               string `json:"name"`
+	DiscountPercent    int    `json:"discount_percent"`
+	DiscountExpiration int64  `json:"discount_expiration"`
+}
+
+type featuredCategory struct {
+	ID    string          `json:"id"`
+	Name  string          `json:"name"`
+	Items []*featuredItem `json:"items"`
+}
+
+// GetSaleStatus asks the store featured categories whether a major sale is
+// running, client defaults to http.DefaultClient.
+func GetSaleStatus(client *http.Client) (*SaleStatus, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Get(featuredCategoriesURL)
+	if resp != nil {
+		defer func(Body io.ReadCloser) {
+			err := Body.Close()
+			if err != nil {
+				return
+			}
+		}(resp.Body)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("http error: %d", resp.StatusCode)
+	}
+
+	/* Categories are keyed by name ("specials") or index ("0"), others
+	 * like "status" are not categories at all.  */
+	var response map[string]json.RawMessage
+	if err = readJSON(resp, &response); err != nil {
+		return nil, err
+	}
+
+	status := &SaleStatus{CheckedAt: time.Now()}
+	ends := make(map[int64]int)
+	for _, raw := range response {
+		var category featuredCategory
+		if json.Unmarshal(raw, &category) != nil {
+			continue
+		}
+
+		switch category.ID {
+		case "cat_spotlight":
+			status.Spotlights = append(status.Spotlights, category.Name)
+		case "cat_specials":
+			for _, item := range category.Items {
+				if item == nil || item.DiscountPercent == 0 {
+					continue
+				}
+
+				status.Specials++
+				if item.DiscountExpiration != 0 {
+					ends[item.DiscountExpiration]++
+				}
+			}
+		}
+	}
+
+	for end, count := range ends {
+		if count >= MajorSaleMinSpecials {
+			status.Major = true
+			status.EndsAt = time.Unix(end, 0)
+			break
+		}
+	}
+
+	return status, nil
+}
+
+func (session *Session) GetSaleStatus() (*SaleStatus, error) {
+	return GetSaleStatus(session.client)
+}
+
+const (
+	// defaultSaleLength is how long the shortest major sales, the
+	// seasonal ones, run.
+	defaultSaleLength = 7 * 24 * time.Hour
+
+	// saleRetryInterval is how long a MarketGuard waits before asking the
+	// store again after a failure.
+	saleRetryInterval = time.Minute
+)
+
+// MarketGuard pauses SellItem and PlaceBuyOrder during the first hours of a
+// major sale, when prices swing too much for automatic pricing.
+//
+// The store only tells when a sale ends, its start is worked back from the
+// end with SaleLength.  When the guard saw the store without the sale
+// after that, the sale started later and the first check seeing it is taken
+// for the start instead.
+type MarketGuard struct {
+	PauseFor   time.Duration // how long after a sale starts market actions stay paused, 0 never pauses
+	Volatile   time.Duration // how long after a sale starts prices are volatile
+	Widen      float64       // tolerance multiplier while prices are volatile
+	Interval   time.Duration // how often the store is asked, defaults to an hour
+	SaleLength time.Duration // how long major sales run, defaults to 7 days
+
+	mu     sync.Mutex
+	status *SaleStatus
+	start  time.Time // of the current major sale, zero when there is none
+	quiet  time.Time // last check without a major sale
+	retry  time.Time // when the store may be asked again after a failure
+}
+
+// update asks the store again once Interval has passed.  The request is
+// made outside of the lock, meanwhile the other callers go on with the
+// status known so far.
+func (guard *MarketGuard) update(client *http.Client) error {
+	now := time.Now()
+
+	guard.mu.Lock()
+	interval := guard.Interval
+	if interval == 0 {
+		interval = time.Hour
+	}
+
+	due := (guard.status == nil || now.Sub(guard.status.CheckedAt) >= interval) && !now.Before(guard.retry)
+	if due {
+		// Claimed, so that concurrent callers do not ask too.
+		guard.retry = now.Add(saleRetryInterval)
+	}
+	guard.mu.Unlock()
+
+	if !due {
+		return nil
+	}
+
+	status, err := GetSaleStatus(client)
+	if err != nil {
+		return err
+	}
+
+	guard.mu.Lock()
+	defer guard.mu.Unlock()
+
+	guard.retry = time.Time{}
+	guard.swap(status)
+	return nil
+}
+
+// swap puts status in place of the previous one and works out when the
+// sale started.
+func (guard *MarketGuard) swap(status *SaleStatus) {
+	switch {
+	case !status.Major:
+		guard.start = time.Time{}
+		guard.quiet = status.CheckedAt
+	case guard.start.IsZero() || guard.status == nil || !guard.status.EndsAt.Equal(status.EndsAt):
+		length := guard.SaleLength
+		if length == 0 {
+			length = defaultSaleLength
+		}
+
+		guard.start = status.EndsAt.Add(-length)
+		if !guard.quiet.IsZero() && guard.quiet.After(guard.start) {
+			guard.start = status.CheckedAt
+		}
+	}
+
+	guard.status = status
+}
+
+// SaleAge is how long the current major sale has been running, false when
+// there is none.  When the store cannot be reached the last status known
+// is used and the error returned along with it.
+func (guard *MarketGuard) SaleAge(client *http.Client) (time.Duration, bool, error) {
+	err := guard.update(client)
+
+	guard.mu.Lock()
+	defer guard.mu.Unlock()
+
+	if guard.start.IsZero() {
+		return 0, false, err
+	}
+
+	return time.Since(guard.start), true, err
+}
+
+// Tolerance widens a price tolerance while prices are volatile and returns
+// it untouched otherwise, see SaleAge for errors.
+func (guard *MarketGuard) Tolerance(client *http.Client, tolerance float64) (float64, error) {
+	age, sale, err := guard.SaleAge(client)
+	if sale && age < guard.Volatile && guard.Widen > 0 {
+		return tolerance * guard.Widen, err
+	}
+
+	return tolerance, err
+}
+
+// Check returns ErrMarketPaused while market actions are paused.  It fails
+// open: when the store cannot be reached the last status known decides,
+// and without one market actions go on.
+func (guard *MarketGuard) Check(client *http.Client) error {
+	age, sale, _ := guard.SaleAge(client)
+	if sale && age < guard.PauseFor {
+		return ErrMarketPaused
+	}
+
+	return nil
+}
+
+// SetMarketGuard installs a guard checked before selling items and placing
+// buy orders, nil disables it.
+func (session *Session) SetMarketGuard(guard *MarketGuard) {
+	session.marketGuard = guard
+}
+
+func (session *Session) checkMarketGuard() error {
+	if session.marketGuard == nil {
+		return nil
+	}
+
+	return session.marketGuard.Check(session.client)
+}
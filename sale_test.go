@@ -0,0 +1,90 @@
+package steam
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// featuredStore answers the featured categories with a major sale ending
+// at end, or without one when end is zero, and fails when down is set.
+type featuredStore struct {
+	mu   sync.Mutex
+	end  time.Time
+	down bool
+}
+
+func (store *featuredStore) set(end time.Time, down bool) {
+	store.mu.Lock()
+	store.end, store.down = end, down
+	store.mu.Unlock()
+}
+
+func (store *featuredStore) client() *http.Client {
+	return &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		store.mu.Lock()
+		defer store.mu.Unlock()
+
+		if store.down {
+			return nil, errors.New("store unreachable")
+		}
+
+		var items []string
+		if !store.end.IsZero() {
+			for i := 0; i < MajorSaleMinSpecials; i++ {
+				items = append(items, fmt.Sprintf(`{"id":%d,"discount_percent":50,"discount_expiration":%d}`, i+1, store.end.Unix()))
+			}
+		}
+
+		body := `{"specials":{"id":"cat_specials","name":"Specials","items":[` + strings.Join(items, ",") + `]}}`
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(body)), Header: make(http.Header)}, nil
+	})}
+}
+
+func TestMarketGuard(t *testing.T) {
+	store := &featuredStore{}
+	client := store.client()
+
+	// Started in the middle of a sale: its start is worked back from its
+	// end, four days ago.
+	store.set(time.Now().Add(3*24*time.Hour), false)
+	guard := &MarketGuard{PauseFor: 24 * time.Hour, Interval: time.Nanosecond}
+	if age, sale, err := guard.SaleAge(client); err != nil || !sale || age < 4*24*time.Hour-time.Minute || age > 4*24*time.Hour+time.Minute {
+		t.Errorf("got %v, %v, %v, want a sale of 4 days", age, sale, err)
+	}
+	if err := guard.Check(client); err != nil {
+		t.Errorf("got %v after the pause", err)
+	}
+
+	// Seen without the sale first: it starts when it is first seen.
+	guard = &MarketGuard{PauseFor: 24 * time.Hour, Interval: time.Nanosecond}
+	store.set(time.Time{}, false)
+	if err := guard.Check(client); err != nil {
+		t.Fatal(err)
+	}
+
+	store.set(time.Now().Add(14*24*time.Hour), false)
+	if err := guard.Check(client); !errors.Is(err, ErrMarketPaused) {
+		t.Errorf("got %v at the start of the sale, want ErrMarketPaused", err)
+	}
+
+	// The store going down keeps the status known so far.
+	store.set(time.Time{}, true)
+	if _, _, err := guard.SaleAge(client); err == nil {
+		t.Error("SaleAge hid the store error")
+	}
+	if err := guard.Check(client); !errors.Is(err, ErrMarketPaused) {
+		t.Errorf("got %v with the store down, want the cached pause", err)
+	}
+
+	// Without anything known it fails open.
+	guard = &MarketGuard{PauseFor: 24 * time.Hour}
+	if err := guard.Check(client); err != nil {
+		t.Errorf("got %v with the store down and nothing known, want nil", err)
+	}
+}
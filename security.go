@@ -0,0 +1,86 @@
+package steam
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+const (
+	SecurityEventAPIKeyRegistered = "api_key_registered"
+	SecurityEventAPIKeyChanged    = "api_key_changed"
+)
+
+type SecurityEvent struct {
+	Type    string    `json:"type"`
+	SteamID SteamID   `json:"steamid,string"`
+	Key     string    `json:"key,omitempty"`
+	Time    time.Time `json:"time"`
+}
+
+// currentWebAPIKey reads dev/apikey without touching the key the session
+// uses, an empty key means none is registered.
+func (session *Session) currentWebAPIKey() (string, error) {
+	resp, err := session.client.Get(session.CommunityBaseURL() + apiKeyURL)
+	if resp != nil {
+		defer func(Body io.ReadCloser) {
+			err := Body.Close()
+			if err != nil {
+				return
+			}
+		}(resp.Body)
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	key, err := extractKey(resp)
+	if err == ErrKeyNotFound {
+		return "", nil
+	}
+
+	return key, err
+}
+
+// WatchAPIKey checks dev/apikey every interval and calls notify whenever the
+// registered key is not the expected one (an empty expected key means no key
+// should be registered at all).  Registering an API key is a common first
+// step when an account gets hijacked, so notify should alert someone.
+// Request errors are passed to onError, if set, and do not stop the watch.
+// It blocks until ctx is done.
+func (session *Session) WatchAPIKey(ctx context.Context, interval time.Duration, expected string, notify func(*SecurityEvent), onError func(error)) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	last := expected
+	for {
+		key, err := session.currentWebAPIKey()
+		if err != nil {
+			if onError != nil {
+				onError(err)
+			}
+		} else if key != last {
+			event := &SecurityEvent{
+				Type:    SecurityEventAPIKeyChanged,
+				SteamID: session.oauth.SteamID,
+				Key:     key,
+				Time:    time.Now(),
+			}
+			if len(last) == 0 {
+				event.Type = SecurityEventAPIKeyRegistered
+			}
+
+			if len(key) != 0 {
+				notify(event)
+			}
+			last = key
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,385 @@
+// Package serverquery queries Source engine game servers over the A2S UDP
+// protocol, for the server side of the games the items come from:
+//
+//	c := &serverquery.Client{Addr: "203.0.113.7:27015"}
+//	info, err := c.Info()
+//	...
+//	required, err := session.GetRequiredSteamAppVersion(int(info.AppID()))
+//
+// Challenges are handled transparently, split responses are reassembled
+// unless they are compressed, which only very old servers do.
+package serverquery
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+	"net"
+	"time"
+)
+
+const (
+	singlePacket = 0xFFFFFFFF
+	splitPacket  = 0xFFFFFFFE
+
+	a2sInfo      = 'T'
+	a2sPlayer    = 'U'
+	a2sRules     = 'V'
+	s2cChallenge = 'A'
+	s2aInfo      = 'I'
+	s2aPlayer    = 'D'
+	s2aRules     = 'E'
+
+	infoPayload = "Source Engine Query\x00"
+
+	maxChallenges = 3
+
+	// maxSplitResponses bounds the split responses receive gathers at
+	// once, the one asked for and late ones.
+	maxSplitResponses = 4
+)
+
+var (
+	ErrInvalidResponse = errors.New("invalid server response")
+	ErrCompressed      = errors.New("compressed responses are not supported")
+)
+
+// Client queries one server, the zero Timeout is 3 seconds.
+type Client struct {
+	Addr    string // host:port of the query port, usually the game port
+	Timeout time.Duration
+}
+
+// Info is the A2S_INFO answer of a server.
+type Info struct {
+	Protocol    uint8
+	Name        string
+	Map         string
+	Folder      string
+	Game        string
+	ID          uint16 // app ID, truncated to 16 bits, see AppID
+	Players     uint8
+	MaxPlayers  uint8
+	Bots        uint8
+	ServerType  byte // 'd' dedicated, 'l' listen, 'p' SourceTV relay
+	Environment byte // 'l' Linux, 'w' Windows, 'm' or 'o' macOS
+	Private     bool
+	VAC         bool
+	Version     string
+
+	Port      uint16 // 0 when not reported
+	SteamID   uint64
+	TVPort    uint16
+	TVName    string
+	Keywords  string
+	GameID    uint64 // full app ID in its low 24 bits when reported
+	hasGameID bool
+}
+
+// AppID is the app ID of the game, taken from GameID when the server sends
+// it since ID cannot hold app IDs above 65535.
+func (info *Info) AppID() uint32 {
+	if info.hasGameID {
+		return uint32(info.GameID & 0xFFFFFF)
+	}
+
+	return uint32(info.ID)
+}
+
+// Player is one entry of the A2S_PLAYER answer.
+type Player struct {
+	Index    uint8
+	Name     string
+	Score    int32
+	Duration time.Duration // time connected
+}
+
+func (c *Client) timeout() time.Duration {
+	if c.Timeout <= 0 {
+		return 3 * time.Second
+	}
+
+	return c.Timeout
+}
+
+// Info sends A2S_INFO.
+func (c *Client) Info() (*Info, error) {
+	r, err := c.query(a2sInfo, []byte(infoPayload), s2aInfo, false)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &Info{}
+	info.Protocol = r.byte()
+	info.Name = r.string()
+	info.Map = r.string()
+	info.Folder = r.string()
+	info.Game = r.string()
+	info.ID = r.uint16()
+	info.Players = r.byte()
+	info.MaxPlayers = r.byte()
+	info.Bots = r.byte()
+	info.ServerType = r.byte()
+	info.Environment = r.byte()
+	info.Private = r.byte() == 1
+	info.VAC = r.byte() == 1
+	info.Version = r.string()
+	if r.err != nil {
+		return nil, ErrInvalidResponse
+	}
+
+	if r.len() == 0 {
+		return info, nil
+	}
+
+	edf := r.byte()
+	if edf&0x80 != 0 {
+		info.Port = r.uint16()
+	}
+	if edf&0x10 != 0 {
+		info.SteamID = r.uint64()
+	}
+	if edf&0x40 != 0 {
+		info.TVPort = r.uint16()
+		info.TVName = r.string()
+	}
+	if edf&0x20 != 0 {
+		info.Keywords = r.string()
+	}
+	if edf&0x01 != 0 {
+		info.GameID = r.uint64()
+		info.hasGameID = true
+	}
+	if r.err != nil {
+		return nil, ErrInvalidResponse
+	}
+
+	return info, nil
+}
+
+// Players sends A2S_PLAYER.
+func (c *Client) Players() ([]*Player, error) {
+	r, err := c.query(a2sPlayer, nil, s2aPlayer, true)
+	if err != nil {
+		return nil, err
+	}
+
+	count := int(r.byte())
+	players := make([]*Player, 0, count)
+	for i := 0; i < count && r.err == nil; i++ {
+		player := &Player{
+			Index: r.byte(),
+			Name:  r.string(),
+			Score: int32(r.uint32()),
+		}
+		player.Duration = time.Duration(float64(math.Float32frombits(r.uint32())) * float64(time.Second))
+		players = append(players, player)
+	}
+	if r.err != nil {
+		return nil, ErrInvalidResponse
+	}
+
+	return players, nil
+}
+
+// Rules sends A2S_RULES and returns the server cvars.
+func (c *Client) Rules() (map[string]string, error) {
+	r, err := c.query(a2sRules, nil, s2aRules, true)
+	if err != nil {
+		return nil, err
+	}
+
+	count := int(r.uint16())
+	rules := make(map[string]string, count)
+	for i := 0; i < count && r.err == nil; i++ {
+		name := r.string()
+		rules[name] = r.string()
+	}
+	if r.err != nil {
+		return nil, ErrInvalidResponse
+	}
+
+	return rules, nil
+}
+
+// query sends a request and follows the challenges the server answers with
+// until it gets a response of type want.  Requests that always carry a
+// challenge start with -1, the others only add it when challenged.
+func (c *Client) query(request byte, payload []byte, want byte, challenged bool) (*reader, error) {
+	conn, err := net.DialTimeout("udp", c.Addr, c.timeout())
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	var challenge []byte
+	if challenged {
+		challenge = []byte{0xFF, 0xFF, 0xFF, 0xFF}
+	}
+
+	for i := 0; i < maxChallenges; i++ {
+		packet := binary.LittleEndian.AppendUint32(nil, singlePacket)
+		packet = append(packet, request)
+		packet = append(packet, payload...)
+		packet = append(packet, challenge...)
+
+		if err = conn.SetDeadline(time.Now().Add(c.timeout())); err != nil {
+			return nil, err
+		}
+		if _, err = conn.Write(packet); err != nil {
+			return nil, err
+		}
+
+		body, err := receive(conn)
+		if err != nil {
+			return nil, err
+		}
+
+		r := &reader{b: body}
+		switch r.byte() {
+		case want:
+			return r, nil
+		case s2cChallenge:
+			challenge = r.bytes(4)
+			if r.err != nil {
+				return nil, ErrInvalidResponse
+			}
+		default:
+			return nil, ErrInvalidResponse
+		}
+	}
+
+	return nil, ErrInvalidResponse
+}
+
+// receive reads one response, reassembling split packets, and returns it
+// without its single packet header.  The parts are gathered by their
+// packet ID, so the late parts of an earlier response cannot mix into this
+// one: they never complete.
+func receive(conn net.Conn) ([]byte, error) {
+	buf := make([]byte, 65535)
+
+	responses := make(map[uint32][][]byte)
+	for {
+		n, err := conn.Read(buf)
+		if err != nil {
+			return nil, err
+		}
+
+		r := &reader{b: buf[:n]}
+		switch r.uint32() {
+		case singlePacket:
+			return append([]byte(nil), r.b...), nil
+		case splitPacket:
+		default:
+			return nil, ErrInvalidResponse
+		}
+
+		id := r.uint32()
+		if id&0x80000000 != 0 {
+			return nil, ErrCompressed
+		}
+
+		count := int(r.byte())
+		number := int(r.byte())
+		r.uint16() // packet size
+		if r.err != nil || count == 0 || number >= count {
+			return nil, ErrInvalidResponse
+		}
+
+		parts, ok := responses[id]
+		if !ok {
+			if len(responses) == maxSplitResponses {
+				return nil, ErrInvalidResponse
+			}
+
+			parts = make([][]byte, count)
+			responses[id] = parts
+		}
+		if count != len(parts) {
+			return nil, ErrInvalidResponse
+		}
+
+		if parts[number] == nil {
+			parts[number] = append([]byte{}, r.b...)
+		}
+
+		if complete(parts) {
+			body := bytes.Join(parts, nil)
+			if len(body) < 4 || binary.LittleEndian.Uint32(body) != singlePacket {
+				return nil, ErrInvalidResponse
+			}
+
+			return body[4:], nil
+		}
+	}
+}
+
+func complete(parts [][]byte) bool {
+	for _, part := range parts {
+		if part == nil {
+			return false
+		}
+	}
+
+	return true
+}
+
+// reader decodes the little endian fields of a response, the first error
+// sticks and zero values are returned from then on.
+type reader struct {
+	b   []byte
+	err error
+}
+
+func (r *reader) len() int {
+	return len(r.b)
+}
+
+func (r *reader) bytes(n int) []byte {
+	if r.err != nil {
+		return make([]byte, n)
+	}
+	if len(r.b) < n {
+		r.err = io.ErrUnexpectedEOF
+		return make([]byte, n)
+	}
+
+	b := r.b[:n]
+	r.b = r.b[n:]
+	return b
+}
+
+func (r *reader) byte() byte {
+	return r.bytes(1)[0]
+}
+
+func (r *reader) uint16() uint16 {
+	return binary.LittleEndian.Uint16(r.bytes(2))
+}
+
+func (r *reader) uint32() uint32 {
+	return binary.LittleEndian.Uint32(r.bytes(4))
+}
+
+func (r *reader) uint64() uint64 {
+	return binary.LittleEndian.Uint64(r.bytes(8))
+}
+
+func (r *reader) string() string {
+	if r.err != nil {
+		return ""
+	}
+
+	i := bytes.IndexByte(r.b, 0)
+	if i < 0 {
+		r.err = io.ErrUnexpectedEOF
+		return ""
+	}
+
+	s := string(r.b[:i])
+	r.b = r.b[i+1:]
+	return s
+}
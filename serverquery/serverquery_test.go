@@ -0,0 +1,181 @@
+package serverquery
+
+import (
+	"bytes"
+	"errors"
+	"net"
+	"testing"
+	"time"
+)
+
+const (
+	challenge = "\x0a\x0b\x0c\x0d"
+
+	infoResponse = "\xFF\xFF\xFF\xFF" + "I" + "\x11" +
+		"My Server\x00" + "de_dust2\x00" + "csgo\x00" + "Counter-Strike 2\x00" +
+		"\xda\x02" + // app 730
+		"\x05\x20\x01" + "d" + "l" + "\x00\x01" + "1.40.0.0\x00" +
+		"\xb1" + // EDF: port, steam ID, keywords, game ID
+		"\x87\x69" + // 27015
+		"\xba\x56\x00\x00\x01\x00\x10\x01" +
+		"secure\x00" +
+		"\xda\x02\x00\x00\x00\x00\x00\x00"
+
+	playersResponse = "\xFF\xFF\xFF\xFF" + "D" + "\x02" +
+		"\x00" + "alice\x00" + "\x0a\x00\x00\x00" + "\x00\x00\x70\x42" + // 10, 60s
+		"\x01" + "bob\x00" + "\xff\xff\xff\xff" + "\x00\x00\xc0\x3f" // -1, 1.5s
+
+	rulesResponse = "\xFF\xFF\xFF\xFF" + "E" + "\x02\x00" +
+		"sv_cheats\x00" + "0\x00" +
+		"mp_timelimit\x00" + "30\x00"
+)
+
+// split cuts response in parts with the split packet header of id.
+func split(id uint32, response string, at ...int) [][]byte {
+	var parts [][]byte
+	start := 0
+	for i, end := range append(at, len(response)) {
+		part := []byte{0xFE, 0xFF, 0xFF, 0xFF, byte(id), byte(id >> 8), byte(id >> 16), byte(id >> 24), byte(len(at) + 1), byte(i), 0xe0, 0x04}
+		parts = append(parts, append(part, response[start:end]...))
+		start = end
+	}
+
+	return parts
+}
+
+// serve answers each request with what respond returns for it.
+func serve(t *testing.T, respond func(request []byte) [][]byte) *Client {
+	t.Helper()
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	go func() {
+		buf := make([]byte, 1500)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+
+			for _, reply := range respond(append([]byte(nil), buf[:n]...)) {
+				conn.WriteTo(reply, addr)
+			}
+		}
+	}()
+
+	return &Client{Addr: conn.LocalAddr().String(), Timeout: time.Second}
+}
+
+// challenged answers requests without the challenge with it, the others
+// with replies.
+func challenged(replies ...[]byte) func([]byte) [][]byte {
+	return func(request []byte) [][]byte {
+		if !bytes.HasSuffix(request, []byte(challenge)) {
+			return [][]byte{[]byte("\xFF\xFF\xFF\xFF" + "A" + challenge)}
+		}
+
+		return replies
+	}
+}
+
+func TestInfo(t *testing.T) {
+	for name, respond := range map[string]func([]byte) [][]byte{
+		"single":     func([]byte) [][]byte { return [][]byte{[]byte(infoResponse)} },
+		"challenged": challenged([]byte(infoResponse)),
+		"split":      func([]byte) [][]byte { return split(7, infoResponse, 20, 50) },
+	} {
+		t.Run(name, func(t *testing.T) {
+			info, err := serve(t, respond).Info()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if info.Name != "My Server" || info.Map != "de_dust2" || info.Game != "Counter-Strike 2" || info.Version != "1.40.0.0" {
+				t.Errorf("got %+v", info)
+			}
+			if info.Players != 5 || info.MaxPlayers != 32 || info.Bots != 1 || info.ServerType != 'd' || info.Private || !info.VAC {
+				t.Errorf("got %+v", info)
+			}
+			if info.Port != 27015 || info.SteamID != 76561197960287930 || info.Keywords != "secure" || info.AppID() != 730 {
+				t.Errorf("got %+v", info)
+			}
+		})
+	}
+}
+
+func TestInfoTruncated(t *testing.T) {
+	c := serve(t, func([]byte) [][]byte { return [][]byte{[]byte(infoResponse[:30])} })
+	if _, err := c.Info(); !errors.Is(err, ErrInvalidResponse) {
+		t.Errorf("got %v, want ErrInvalidResponse", err)
+	}
+}
+
+func TestPlayers(t *testing.T) {
+	// The split response comes out of order, after a late part of an
+	// earlier response.
+	parts := split(9, playersResponse, 15)
+	stale := split(8, rulesResponse, 10)[1]
+	c := serve(t, challenged(stale, parts[1], parts[0]))
+
+	players, err := c.Players()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(players) != 2 {
+		t.Fatalf("got %d players, want 2", len(players))
+	}
+	if p := players[0]; p.Index != 0 || p.Name != "alice" || p.Score != 10 || p.Duration != time.Minute {
+		t.Errorf("got %+v", p)
+	}
+	if p := players[1]; p.Index != 1 || p.Name != "bob" || p.Score != -1 || p.Duration != 1500*time.Millisecond {
+		t.Errorf("got %+v", p)
+	}
+}
+
+func TestRules(t *testing.T) {
+	c := serve(t, challenged(split(3, rulesResponse, 12, 24)...))
+
+	rules, err := c.Rules()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(rules) != 2 || rules["sv_cheats"] != "0" || rules["mp_timelimit"] != "30" {
+		t.Errorf("got %v", rules)
+	}
+}
+
+func TestReceiveErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		replies [][]byte
+		err     error
+	}{
+		{"compressed", [][]byte{{0xFE, 0xFF, 0xFF, 0xFF, 1, 0, 0, 0x80, 2, 0, 0xe0, 0x04}}, ErrCompressed},
+		{"unknown header", [][]byte{[]byte("\x01\x02\x03\x04I")}, ErrInvalidResponse},
+		{"part out of range", [][]byte{{0xFE, 0xFF, 0xFF, 0xFF, 1, 0, 0, 0, 2, 2, 0xe0, 0x04}}, ErrInvalidResponse},
+		{"unexpected type", [][]byte{[]byte("\xFF\xFF\xFF\xFF" + "D\x00")}, ErrInvalidResponse},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := serve(t, func([]byte) [][]byte { return tt.replies })
+			if _, err := c.Info(); !errors.Is(err, tt.err) {
+				t.Errorf("got %v, want %v", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestChallengeLimit(t *testing.T) {
+	// A server challenging forever.
+	c := serve(t, func([]byte) [][]byte { return [][]byte{[]byte("\xFF\xFF\xFF\xFF" + "A" + challenge)} })
+	if _, err := c.Rules(); !errors.Is(err, ErrInvalidResponse) {
+		t.Errorf("got %v, want ErrInvalidResponse", err)
+	}
+}
@@ -0,0 +1,80 @@
+package steam
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// ServiceError is returned by ServiceCall when the method answers with a
+// non-OK EResult.
+type ServiceError struct {
+	Method  string
+	EResult int
+	Message string // x-error_message, often empty
+}
+
+func (e *ServiceError) Error() string {
+	if len(e.Message) != 0 {
+		return fmt.Sprintf("%s: eresult %d: %s", e.Method, e.EResult, e.Message)
+	}
+
+	return fmt.Sprintf("%s: eresult %d", e.Method, e.EResult)
+}
+
+// ServiceCall calls a protobuf WebAPI method ("IAuthenticationService",
+// "GetAuthSessionInfo") with req as input_protobuf_encoded and decodes the
+// binary response into resp, which may be nil.  The call is authenticated
+// with the access token of the session (see AccessToken) when it has one
+// and the API key otherwise.  The method version follows SetAPIVersion.
+func (session *Session) ServiceCall(service, method string, req, resp proto.Message) error {
+	return session.postService(service+"/"+method, session.authorize(url.Values{}), req, resp)
+}
+
+// postService is ServiceCall with the credentials already in params.
+func (session *Session) postService(name string, params url.Values, req, resp proto.Message) error {
+	input, err := proto.Marshal(req)
+	if err != nil {
+		return err
+	}
+	params.Set("input_protobuf_encoded", base64.StdEncoding.EncodeToString(input))
+
+	r, err := session.client.PostForm(session.apiURL(name), params)
+	if r != nil {
+		defer func(Body io.ReadCloser) {
+			err := Body.Close()
+			if err != nil {
+				return
+			}
+		}(r.Body)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if xe := r.Header.Get("x-eresult"); len(xe) != 0 && xe != "1" {
+		session.stats.addError(xe)
+		result, _ := strconv.Atoi(xe)
+		return &ServiceError{Method: name, EResult: result, Message: r.Header.Get("x-error_message")}
+	}
+	if r.StatusCode != http.StatusOK {
+		return fmt.Errorf("http error: %d", r.StatusCode)
+	}
+
+	if resp == nil {
+		return nil
+	}
+
+	b, err := io.ReadAll(r.Body)
+	if err != nil {
+		return err
+	}
+
+	return proto.Unmarshal(b, resp)
+}
@@ -0,0 +1,97 @@
+package steam
+
+import (
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"time"
+)
+
+// SessionState is everything needed to resume a logged in session without
+// logging in again, it is meant to be serialized (e.g. as JSON) and kept
+// secret: the cookies grant full access to the account.
+type SessionState struct {
+	SteamID    SteamID                   `json:"steamid,string"`
+	SessionID  string                    `json:"sessionid"`
+	APIKey     string                    `json:"api_key,omitempty"`
+	DeviceID   string                    `json:"device_id"`
+	Language   string                    `json:"language,omitempty"`
+	ExpireTime time.Time                 `json:"expire_time"`
+	Cookies    map[string][]*http.Cookie `json:"cookies"` // by cookieHosts entry
+
+	RefreshToken string `json:"refresh_token,omitempty"`
+}
+
+// State snapshots the session so it can be restored with Restore.
+func (session *Session) State() *SessionState {
+	session.tokenMu.Lock()
+	defer session.tokenMu.Unlock()
+
+	state := &SessionState{
+		SteamID:    session.oauth.SteamID,
+		SessionID:  session.sessionID,
+		APIKey:     session.apiKey,
+		DeviceID:   session.deviceID,
+		Language:   session.language,
+		ExpireTime: session.expireTime,
+		Cookies:    make(map[string][]*http.Cookie),
+
+		RefreshToken: session.refreshToken,
+	}
+
+	if session.client.Jar != nil {
+		for _, host := range cookieHosts {
+			u, _ := url.Parse(host)
+			if cookies := session.client.Jar.Cookies(u); len(cookies) != 0 {
+				state.Cookies[host] = cookies
+			}
+		}
+	}
+
+	return state
+}
+
+// Restore loads a state returned by State into the session, its cookies
+// are added to the client jar.
+func (session *Session) Restore(state *SessionState) error {
+	if session.client.Jar == nil {
+		jar, err := cookiejar.New(nil)
+		if err != nil {
+			return err
+		}
+
+		session.client.Jar = jar
+	}
+
+	for host, cookies := range state.Cookies {
+		u, err := url.Parse(host)
+		if err != nil {
+			return err
+		}
+
+		for _, cookie := range cookies {
+			cookie.Path = "/"
+			cookie.Secure = true
+		}
+
+		session.client.Jar.SetCookies(u, cookies)
+	}
+
+	session.oauth.SteamID = state.SteamID
+	session.sessionID = state.SessionID
+	session.deviceID = state.DeviceID
+	session.tokenMu.Lock()
+	session.expireTime = state.ExpireTime
+	if len(state.RefreshToken) != 0 {
+		session.refreshToken = state.RefreshToken
+	}
+	session.tokenMu.Unlock()
+	if len(state.APIKey) != 0 {
+		session.apiKey = state.APIKey
+	}
+	if len(state.Language) != 0 {
+		session.language = state.Language
+	}
+
+	return nil
+}
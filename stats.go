@@ -0,0 +1,122 @@
+package steam
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Stats is a point-in-time snapshot of what a Session has done since it was
+// created.
+type Stats struct {
+	OffersSent            uint64            `json:"offers_sent"`
+	OffersAccepted        uint64            `json:"offers_accepted"`
+	OffersDeclined        uint64            `json:"offers_declined"`
+	OffersCanceled        uint64            `json:"offers_canceled"`
+	ConfirmationsAnswered uint64            `json:"confirmations_answered"`
+	AvgConfirmLatency     time.Duration     `json:"avg_confirm_latency"`
+	Errors                map[string]uint64 `json:"errors"`   // keyed by EResult
+	Breakers              map[string]string `json:"breakers"` // circuit breaker state by endpoint group
+
+	Endpoints map[string]EndpointStats `json:"endpoints"`  // see EnableLatencyTracking
+	KeyQuotas map[string]KeyUsage      `json:"key_quotas"` // see EnableKeyQuota
+}
+
+// The offer counters of sessionStats.
+const (
+	statOffersSent = iota
+	statOffersAccepted
+	statOffersDeclined
+	statOffersCanceled
+	numOfferStats
+)
+
+// sessionStats counts what a session did.  The methods are safe to call on
+// nil, e.g. for a Session not made by NewSession, which counts nothing.
+type sessionStats struct {
+	mu                    sync.Mutex
+	offers                [numOfferStats]uint64
+	confirmationsAnswered uint64
+	confirmLatency        time.Duration
+	errors                map[string]uint64
+}
+
+func newSessionStats() *sessionStats {
+	return &sessionStats{errors: make(map[string]uint64)}
+}
+
+func (s *sessionStats) add(counter int) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	s.offers[counter]++
+	s.mu.Unlock()
+}
+
+func (s *sessionStats) addConfirmation(latency time.Duration) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	s.confirmationsAnswered++
+	s.confirmLatency += latency
+	s.mu.Unlock()
+}
+
+func (s *sessionStats) addError(eresult string) {
+	if s == nil || eresult == "" {
+		return
+	}
+
+	s.mu.Lock()
+	s.errors[eresult]++
+	s.mu.Unlock()
+}
+
+// Stats returns a snapshot of the session counters.
+func (session *Session) Stats() Stats {
+	s := session.stats
+	if s == nil {
+		return Stats{Errors: map[string]uint64{}, Breakers: session.BreakerStates(), Endpoints: session.EndpointStats(), KeyQuotas: session.KeyQuotas()}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stats := Stats{
+		OffersSent:            s.offers[statOffersSent],
+		OffersAccepted:        s.offers[statOffersAccepted],
+		OffersDeclined:        s.offers[statOffersDeclined],
+		OffersCanceled:        s.offers[statOffersCanceled],
+		ConfirmationsAnswered: s.confirmationsAnswered,
+		Errors:                make(map[string]uint64, len(s.errors)),
+		Breakers:              session.BreakerStates(),
+		Endpoints:             session.EndpointStats(),
+		KeyQuotas:             session.KeyQuotas(),
+	}
+
+	if s.confirmationsAnswered != 0 {
+		stats.AvgConfirmLatency = s.confirmLatency / time.Duration(s.confirmationsAnswered)
+	}
+
+	for k, v := range s.errors {
+		stats.Errors[k] = v
+	}
+
+	return stats
+}
+
+// StatsHandler serves the session Stats as JSON, it is meant to be mounted
+// next to whatever health endpoint the application exposes.
+func (session *Session) StatsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(session.Stats()); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
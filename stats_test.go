@@ -0,0 +1,28 @@
+package steam
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStatsNilSafe(t *testing.T) {
+	// A Session not made by NewSession has no counters.
+	session := &Session{}
+	session.stats.add(statOffersSent)
+	session.stats.addError("EResult 16")
+	session.stats.addConfirmation(time.Second)
+
+	if stats := session.Stats(); stats.OffersSent != 0 || stats.Errors == nil {
+		t.Errorf("got %+v, want empty stats", stats)
+	}
+
+	session.stats = newSessionStats()
+	session.stats.add(statOffersSent)
+	session.stats.addError("EResult 16")
+	session.stats.addConfirmation(2 * time.Second)
+
+	stats := session.Stats()
+	if stats.OffersSent != 1 || stats.Errors["EResult 16"] != 1 || stats.AvgConfirmLatency != 2*time.Second {
+		t.Errorf("got %+v, want the counted offer, error and confirmation", stats)
+	}
+}
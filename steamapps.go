@@ -1,20 +1,34 @@
 package steam
 
 import (
-	"encoding/json"
+	"fmt"
 	"io"
+	"net/http"
 	"net/url"
 	"strconv"
 )
 
 const (
-	apiUpToDateCheck = APIBaseUrl + "/ISteamApps/UpToDateCheck/v1?"
+	apiUpToDateCheck = "/ISteamApps/UpToDateCheck/v1?"
+	apiStoreAppList  = "/IStoreService/GetAppList/v1/?"
 )
 
-func (session *Session) GetRequiredSteamAppVersion(appID int) (int, error) {
-	resp, err := session.client.Get(apiUpToDateCheck + url.Values{
+// UpToDateStatus is what ISteamApps/UpToDateCheck reports about a version
+// of an app.
+type UpToDateStatus struct {
+	Success           bool   `json:"success"`
+	UpToDate          bool   `json:"up_to_date"`
+	VersionIsListable bool   `json:"version_is_listable"`
+	RequiredVersion   int    `json:"required_version"`
+	Message           string `json:"message"`
+}
+
+// UpToDateCheck tells whether version of an app is still accepted by the
+// Steam servers.
+func (session *Session) UpToDateCheck(appID, version int) (*UpToDateStatus, error) {
+	resp, err := session.client.Get(session.APIBaseURL() + apiUpToDateCheck + url.Values{
 		"appid":   {strconv.Itoa(appID)},
-		"version": {"0"},
+		"version": {strconv.Itoa(version)},
 	}.Encode())
 	if resp != nil {
 		defer func(Body io.ReadCloser) {
@@ -25,21 +39,127 @@ func (session *Session) GetRequiredSteamAppVersion(appID int) (int, error) {
 		}(resp.Body)
 	}
 
+	if err != nil {
+		return nil, err
+	}
+
+	type Response struct {
+		Inner *UpToDateStatus `json:"response"`
+	}
+
+	var response Response
+	if err = session.readJSON(resp, &response); err != nil {
+		return nil, err
+	}
+
+	if response.Inner == nil {
+		return nil, ErrInvalidResponse
+	}
+
+	return response.Inner, nil
+}
+
+func (session *Session) GetRequiredSteamAppVersion(appID int) (int, error) {
+	status, err := session.UpToDateCheck(appID, 0)
 	if err != nil {
 		return 0, err
 	}
 
-	type UpToDateCheckResponse struct {
-		RequiredVersion int `json:"required_version"`
+	return status.RequiredVersion, nil
+}
+
+// StoreApp is an app of the store catalog.
+type StoreApp struct {
+	AppID             uint32 `json:"appid"`
+	Name              string `json:"name"`
+	LastModified      int64  `json:"last_modified"`
+	PriceChangeNumber uint32 `json:"price_change_number"`
+}
+
+// AppListQuery selects the apps GetAppList returns, the zero value lists
+// the games only.
+type AppListQuery struct {
+	IfModifiedSince int64 // unix time, 0 lists everything
+	IncludeDLC      bool
+	IncludeSoftware bool
+	IncludeVideos   bool
+	IncludeHardware bool
+	ExcludeGames    bool
+}
+
+func (session *Session) getAppListPage(query *AppListQuery, lastAppID uint32) ([]*StoreApp, bool, uint32, error) {
+	params := session.authorize(url.Values{
+		"include_games":    {strconv.FormatBool(!query.ExcludeGames)},
+		"include_dlc":      {strconv.FormatBool(query.IncludeDLC)},
+		"include_software": {strconv.FormatBool(query.IncludeSoftware)},
+		"include_videos":   {strconv.FormatBool(query.IncludeVideos)},
+		"include_hardware": {strconv.FormatBool(query.IncludeHardware)},
+		"max_results":      {"50000"},
+	})
+	if query.IfModifiedSince != 0 {
+		params.Set("if_modified_since", strconv.FormatInt(query.IfModifiedSince, 10))
+	}
+	if lastAppID != 0 {
+		params.Set("last_appid", strconv.FormatUint(uint64(lastAppID), 10))
+	}
+
+	resp, err := session.client.Get(session.APIBaseURL() + apiStoreAppList + params.Encode())
+	if resp != nil {
+		defer func(Body io.ReadCloser) {
+			err := Body.Close()
+			if err != nil {
+				return
+			}
+		}(resp.Body)
+	}
+
+	if err != nil {
+		return nil, false, 0, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, false, 0, fmt.Errorf("http error: %d", resp.StatusCode)
 	}
 
 	type Response struct {
-		Inner UpToDateCheckResponse `json:"response"`
+		Inner *struct {
+			Apps            []*StoreApp `json:"apps"`
+			HaveMoreResults bool        `json:"have_more_results"`
+			LastAppID       uint32      `json:"last_appid"`
+		} `json:"response"`
 	}
 
 	var response Response
-	if err = json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return 0, err
+	if err = session.readJSON(resp, &response); err != nil {
+		return nil, false, 0, err
+	}
+
+	if response.Inner == nil {
+		return nil, false, 0, ErrInvalidResponse
+	}
+
+	return response.Inner.Apps, response.Inner.HaveMoreResults, response.Inner.LastAppID, nil
+}
+
+// GetAppList lists the store catalog through IStoreService/GetAppList,
+// following its pages until the end.  It needs a WebAPI key.
+func (session *Session) GetAppList(query *AppListQuery) ([]*StoreApp, error) {
+	if query == nil {
+		query = &AppListQuery{}
+	}
+
+	var apps []*StoreApp
+	var lastAppID uint32
+	for {
+		page, hasMore, last, err := session.getAppListPage(query, lastAppID)
+		if err != nil {
+			return nil, err
+		}
+
+		apps = append(apps, page...)
+		if !hasMore || last == 0 || last == lastAppID {
+			return apps, nil
+		}
+
+		lastAppID = last
 	}
-	return response.Inner.RequiredVersion, nil
 }
@@ -206,8 +206,6 @@ func (sid *SteamID) ToSteam3ID() string {
 	case AccountTypeIndividual:
 		accountTypeChar = 'U'
 		doInstance = instance != AccountInstanceDesktop
-	default:
-		panic("unhandled default case")
 	}
 
 	if doInstance {
@@ -0,0 +1,179 @@
+package steamtest
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/hiship/go-steam"
+	"github.com/hiship/go-steam/pb"
+	"google.golang.org/protobuf/proto"
+)
+
+// SetTradeOffers answers IEconService/GetTradeOffers with resp.
+func (s *Server) SetTradeOffers(resp *steam.TradeOfferResponse) {
+	s.HandleJSON(APIHost, "/IEconService/GetTradeOffers/v1/", map[string]interface{}{"response": resp})
+}
+
+// SetTradeOffer answers IEconService/GetTradeOffer with offer.
+func (s *Server) SetTradeOffer(offer *steam.TradeOffer) {
+	s.HandleJSON(APIHost, "/IEconService/GetTradeOffer/v1/", map[string]interface{}{
+		"response": map[string]interface{}{"offer": offer},
+	})
+}
+
+// SetConfirmations answers mobileconf/getlist with confirmations and
+// accepts every ajaxop.
+func (s *Server) SetConfirmations(confirmations []*steam.Confirmation) {
+	s.HandleJSON(CommunityHost, "/mobileconf/getlist", &steam.ConfirmationResponse{
+		Success:       true,
+		Confirmations: confirmations,
+	})
+	s.HandleJSON(CommunityHost, "/mobileconf/ajaxop", map[string]interface{}{"success": true})
+}
+
+// SetInventory answers the inventory of sid for appID/contextID with items
+// in a single page, descriptions are taken from item.Desc.
+func (s *Server) SetInventory(sid steam.SteamID, appID, contextID uint64, items []steam.InventoryItem) {
+	type asset struct {
+		AppID      uint32 `json:"appid"`
+		ContextID  string `json:"contextid"`
+		AssetID    string `json:"assetid"`
+		ClassID    string `json:"classid"`
+		InstanceID string `json:"instanceid"`
+		Amount     string `json:"amount"`
+	}
+
+	assets := []asset{}
+	descs := []*steam.EconItemDesc{}
+	for _, item := range items {
+		assets = append(assets, asset{
+			AppID:      item.AppID,
+			ContextID:  strconv.FormatUint(item.ContextID, 10),
+			AssetID:    strconv.FormatUint(item.AssetID, 10),
+			ClassID:    strconv.FormatUint(item.ClassID, 10),
+			InstanceID: strconv.FormatUint(item.InstanceID, 10),
+			Amount:     item.Amount,
+		})
+		if item.Desc != nil {
+			descs = append(descs, item.Desc)
+		}
+	}
+
+	s.HandleJSON(CommunityHost, fmt.Sprintf("/inventory/%d/%d/%d", sid, appID, contextID), map[string]interface{}{
+		"assets":                assets,
+		"descriptions":          descs,
+		"success":               1,
+		"total_inventory_count": len(assets),
+	})
+}
+
+// SetLogin makes the whole IAuthenticationService login flow succeed for
+// sid, whatever credentials are used.  Login talks through
+// http.DefaultClient, see InstallDefault.
+func (s *Server) SetLogin(sid steam.SteamID) error {
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		return err
+	}
+
+	steamID := uint64(sid)
+	s.Handle(APIHost, "/IAuthenticationService/GetPasswordRSAPublicKey/v1", protobuf(&pb.CAuthentication_GetPasswordRSAPublicKey_Response{
+		PublickeyMod: proto.String(key.N.Text(16)),
+		PublickeyExp: proto.String(strconv.FormatInt(int64(key.E), 16)),
+		Timestamp:    proto.Uint64(1),
+	}))
+	s.Handle(APIHost, "/IAuthenticationService/BeginAuthSessionViaCredentials/v1", protobuf(&pb.CAuthentication_BeginAuthSessionViaCredentials_Response{
+		ClientId:  proto.Uint64(1),
+		RequestId: []byte("steamtest"),
+		Steamid:   &steamID,
+		AllowedConfirmations: []*pb.CAuthentication_AllowedConfirmation{{
+			ConfirmationType: pb.EAuthSessionGuardType_k_EAuthSessionGuardType_DeviceCode.Enum(),
+		}},
+	}))
+	s.Handle(APIHost, "/IAuthenticationService/UpdateAuthSessionWithSteamGuardCode/v1", EResult(1))
+	s.Handle(APIHost, "/IAuthenticationService/PollAuthSessionStatus/v1", protobuf(&pb.CAuthentication_PollAuthSessionStatus_Response{
+		RefreshToken: proto.String("steamtest-refresh"),
+		AccessToken:  proto.String("steamtest-access"),
+	}))
+	s.Handle(LoginHost, "/jwt/finalizelogin", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "steamRefresh_steam", Value: "steamtest"})
+		JSON(map[string]interface{}{
+			"steamID": sid.ToString(),
+			"transfer_info": []map[string]interface{}{{
+				"url":    "https://steamcommunity.com/login/settoken",
+				"params": map[string]string{"nonce": "steamtest", "auth": "steamtest"},
+			}},
+		}).ServeHTTP(w, r)
+	}))
+	s.Handle(CommunityHost, "/login/settoken", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "steamLoginSecure", Value: sid.ToString() + "||steamtest"})
+	}))
+
+	return nil
+}
+
+// SetLoginCaptcha puts a captcha in front of the login set with SetLogin:
+// the authentication service answers RateLimitExceeded until the
+// community login is given text for the captcha.
+func (s *Server) SetLoginCaptcha(text string) {
+	const (
+		gid  = "4242"
+		path = "/IAuthenticationService/BeginAuthSessionViaCredentials/v1"
+	)
+
+	var (
+		mu     sync.Mutex
+		solved bool
+	)
+
+	s.mu.Lock()
+	begin := s.routes[APIHost+path]
+	s.mu.Unlock()
+
+	s.Handle(APIHost, path, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		ok := solved
+		mu.Unlock()
+
+		if !ok {
+			EResult(84).ServeHTTP(w, r)
+			return
+		}
+		begin.ServeHTTP(w, r)
+	}))
+	s.Handle(CommunityHost, "/login/dologin/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		if r.PostFormValue("captchagid") == gid && r.PostFormValue("captcha_text") == text {
+			solved = true
+		}
+		ok := solved
+		mu.Unlock()
+
+		response := map[string]interface{}{"success": false, "captcha_needed": !ok}
+		if !ok {
+			response["captcha_gid"] = gid
+		}
+		JSON(response).ServeHTTP(w, r)
+	}))
+	s.Handle(CommunityHost, "/login/rendercaptcha/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		w.Write([]byte("captcha " + r.URL.Query().Get("gid")))
+	}))
+}
+
+func protobuf(m proto.Message) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, err := proto.Marshal(m)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("x-eresult", "1")
+		w.Write(b)
+	})
+}
@@ -0,0 +1,152 @@
+// Package steamtest provides a fake Steam backed by httptest so code built on
+// top of steam can be exercised without live accounts.
+//
+// Every Steam host is routed to the same local server, handlers are
+// registered per host and path and can be replaced at any time:
+//
+//	fake := steamtest.NewServer()
+//	defer fake.Close()
+//
+//	fake.SetTradeOffers(&steam.TradeOfferResponse{...})
+//	session := steam.NewSession(fake.Client(), "key")
+package steamtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+)
+
+const (
+	CommunityHost = "steamcommunity.com"
+	APIHost       = "api.steampowered.com"
+	LoginHost     = "login.steampowered.com"
+	StoreHost     = "store.steampowered.com"
+)
+
+type Server struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	routes   map[string]http.Handler
+	prefixes map[string]http.Handler
+	requests []*http.Request
+}
+
+func NewServer() *Server {
+	s := &Server{
+		routes:   make(map[string]http.Handler),
+		prefixes: make(map[string]http.Handler),
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	return s
+}
+
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.requests = append(s.requests, r)
+	h, ok := s.routes[r.Host+r.URL.Path]
+	if !ok {
+		for prefix, handler := range s.prefixes {
+			if len(r.Host+r.URL.Path) >= len(prefix) && (r.Host + r.URL.Path)[:len(prefix)] == prefix {
+				h, ok = handler, true
+				break
+			}
+		}
+	}
+	s.mu.Unlock()
+
+	if !ok {
+		http.Error(w, fmt.Sprintf("steamtest: no fixture for %s%s", r.Host, r.URL.Path), http.StatusNotFound)
+		return
+	}
+
+	h.ServeHTTP(w, r)
+}
+
+// Handle serves path on host with h, replacing any previous handler.
+func (s *Server) Handle(host, path string, h http.Handler) {
+	s.mu.Lock()
+	s.routes[host+path] = h
+	s.mu.Unlock()
+}
+
+// HandlePrefix serves every path on host starting with prefix, exact
+// routes registered with Handle take precedence.
+func (s *Server) HandlePrefix(host, prefix string, h http.Handler) {
+	s.mu.Lock()
+	s.prefixes[host+prefix] = h
+	s.mu.Unlock()
+}
+
+// HandleJSON answers path on host with v encoded as JSON and an OK EResult.
+func (s *Server) HandleJSON(host, path string, v interface{}) {
+	s.Handle(host, path, JSON(v))
+}
+
+// JSON is a handler answering with v encoded as JSON and an OK EResult.
+func JSON(v interface{}) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("x-eresult", "1")
+		json.NewEncoder(w).Encode(v)
+	})
+}
+
+// EResult is a handler failing with the given EResult header.
+func EResult(result int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("x-eresult", fmt.Sprint(result))
+	})
+}
+
+// Requests returns every request received so far.
+func (s *Server) Requests() []*http.Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	requests := make([]*http.Request, len(s.requests))
+	copy(requests, s.requests)
+	return requests
+}
+
+// Transport sends every request to the fake server, keeping the original
+// host so handlers can be told apart.
+func (s *Server) Transport() http.RoundTripper {
+	target, _ := url.Parse(s.URL)
+	return &rewriteTransport{target: target, next: s.Server.Client().Transport}
+}
+
+// Client returns a client with a cookie jar talking to the fake server,
+// ready to be passed to steam.NewSession.
+func (s *Server) Client() *http.Client {
+	jar, _ := cookiejar.New(nil)
+	return &http.Client{Transport: s.Transport(), Jar: jar}
+}
+
+// InstallDefault routes http.DefaultClient to the fake server, which is
+// what the login flow uses, until restore is called.
+func (s *Server) InstallDefault() (restore func()) {
+	previous := http.DefaultClient.Transport
+	http.DefaultClient.Transport = s.Transport()
+	return func() {
+		http.DefaultClient.Transport = previous
+	}
+}
+
+type rewriteTransport struct {
+	target *url.URL
+	next   http.RoundTripper
+}
+
+func (t *rewriteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	r := req.Clone(req.Context())
+	r.Host = req.URL.Host
+	r.URL.Scheme = t.target.Scheme
+	r.URL.Host = t.target.Host
+	return t.next.RoundTrip(r)
+}
@@ -0,0 +1,298 @@
+package steamtest_test
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hiship/go-steam"
+	"github.com/hiship/go-steam/steamtest"
+)
+
+const sid steam.SteamID = 76561197960287930
+
+// secret is a made-up shared and identity secret.
+var secret = base64.StdEncoding.EncodeToString([]byte("steamtest secret 20b"))
+
+func newFake(t *testing.T) *steamtest.Server {
+	fake := steamtest.NewServer()
+	t.Cleanup(fake.Close)
+	return fake
+}
+
+func login(t *testing.T, fake *steamtest.Server) *steam.Session {
+	t.Helper()
+
+	if err := fake.SetLogin(sid); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(fake.InstallDefault())
+
+	session := steam.NewSession(fake.Client(), "key")
+	if err := session.Login("user", "password", secret, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	return session
+}
+
+func TestLogin(t *testing.T) {
+	session := login(t, newFake(t))
+
+	if got := session.GetSteamID(); got != sid {
+		t.Errorf("logged in as %d, want %d", got, sid)
+	}
+
+	if got := session.GetRefreshToken(); got != "steamtest-refresh" {
+		t.Errorf("refresh token %q, want the fake one", got)
+	}
+}
+
+func TestLoginDeviceID(t *testing.T) {
+	tests := []struct {
+		name    string
+		prepare func(*steam.Session)
+		want    string
+	}{
+		// MD5 of "userpassword", what authenticators were enrolled with.
+		{"legacy by default", func(*steam.Session) {}, "android:d440-aed1-89a1-3ff9-70da"},
+		{"from the steamid", func(s *steam.Session) { s.SetSteamIDDeviceID(true) }, steam.GenerateDeviceID(sid)},
+		{"persisted", func(s *steam.Session) { s.SetDeviceID("android:kept"); s.SetSteamIDDeviceID(true) }, "android:kept"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := newFake(t)
+			if err := fake.SetLogin(sid); err != nil {
+				t.Fatal(err)
+			}
+			t.Cleanup(fake.InstallDefault())
+
+			session := steam.NewSession(fake.Client(), "key")
+			tt.prepare(session)
+			if err := session.Login("user", "password", secret, 0); err != nil {
+				t.Fatal(err)
+			}
+
+			if got := session.GetDeviceID(); got != tt.want {
+				t.Errorf("device id %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoginNeedsSteamGuard(t *testing.T) {
+	fake := newFake(t)
+	if err := fake.SetLogin(sid); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(fake.InstallDefault())
+
+	session := steam.NewSession(fake.Client(), "key")
+	err := session.Login("user", "password", "", 0)
+	if _, ok := err.(*steam.SteamGuardRequiredError); !ok {
+		t.Fatalf("got %v, want a SteamGuardRequiredError", err)
+	}
+}
+
+func TestLoginCaptcha(t *testing.T) {
+	fake := newFake(t)
+	if err := fake.SetLogin(sid); err != nil {
+		t.Fatal(err)
+	}
+	fake.SetLoginCaptcha("h4x0r")
+	t.Cleanup(fake.InstallDefault())
+
+	session := steam.NewSession(fake.Client(), "key")
+	if err := session.Login("user", "password", secret, 0); !errors.Is(err, steam.ErrLoginThrottled) {
+		t.Fatalf("without a solver got %v, want ErrLoginThrottled", err)
+	}
+
+	var images []string
+	session.SetCaptchaSolver(steam.CaptchaSolverFunc(func(ctx context.Context, gid string, image []byte) (string, error) {
+		images = append(images, string(image))
+		if len(images) == 1 {
+			return "wrong", nil
+		}
+		return "h4x0r", nil
+	}))
+
+	if err := session.Login("user", "password", secret, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(images) != 2 || images[0] != "captcha 4242" {
+		t.Errorf("solver saw %q, want the captcha twice", images)
+	}
+}
+
+func TestTradeOffers(t *testing.T) {
+	fake := newFake(t)
+	fake.SetTradeOffers(&steam.TradeOfferResponse{
+		SentOffers: []*steam.TradeOffer{
+			{ID: 1, Partner: 2, State: steam.TradeStateActive, SendItems: []*steam.EconItem{{AssetID: "10", AppID: 730, ContextID: "2", Amount: "1"}}},
+		},
+		ReceivedOffers: []*steam.TradeOffer{
+			{ID: 3, Partner: 4, State: steam.TradeStateAccepted},
+		},
+	})
+
+	session := steam.NewSession(fake.Client(), "key")
+	resp, err := session.GetTradeOffers(steam.TradeFilterSentOffers|steam.TradeFilterRecvOffers, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(resp.SentOffers) != 1 || len(resp.ReceivedOffers) != 1 {
+		t.Fatalf("got %d sent and %d received offers, want 1 and 1", len(resp.SentOffers), len(resp.ReceivedOffers))
+	}
+
+	sent := resp.SentOffers[0]
+	if sent.ID != 1 || sent.State != steam.TradeStateActive || len(sent.SendItems) != 1 || sent.SendItems[0].AssetID != "10" {
+		t.Errorf("sent offer %+v does not match the fixture", sent)
+	}
+
+	if got := resp.ReceivedOffers[0].State; got != steam.TradeStateAccepted {
+		t.Errorf("received offer state %v, want Accepted", got)
+	}
+
+	requests := fake.Requests()
+	if len(requests) != 1 {
+		t.Fatalf("got %d requests, want 1", len(requests))
+	}
+
+	query := requests[0].URL.Query()
+	if query.Get("key") != "key" || query.Get("get_sent_offers") != "1" || query.Get("get_received_offers") != "1" {
+		t.Errorf("unexpected query %s", requests[0].URL.RawQuery)
+	}
+}
+
+func TestConfirmations(t *testing.T) {
+	fake := newFake(t)
+	session := login(t, fake)
+
+	fake.SetConfirmations([]*steam.Confirmation{
+		{ID: "100", Type: steam.ConfirmationTypeTrade, Creator: "1", Nonce: "n1", Headline: "partner"},
+		{ID: "101", Type: steam.ConfirmationTypeMarketListing, Creator: "2", Nonce: "n2", Headline: "AK-47 | Redline"},
+	})
+
+	now := time.Now().Unix()
+	confirmations, err := session.GetConfirmations(secret, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(confirmations) != 2 {
+		t.Fatalf("got %d confirmations, want 2", len(confirmations))
+	}
+
+	if confirmations[1].Type != steam.ConfirmationTypeMarketListing || confirmations[1].Headline != "AK-47 | Redline" {
+		t.Errorf("confirmation %+v does not match the fixture", confirmations[1])
+	}
+
+	if err = session.AnswerConfirmation(confirmations[0], secret, "allow", now); err != nil {
+		t.Fatal(err)
+	}
+
+	var answered bool
+	for _, r := range fake.Requests() {
+		if r.URL.Path != "/mobileconf/ajaxop" {
+			continue
+		}
+
+		query := r.URL.Query()
+		if query.Get("op") != "allow" || query.Get("cid") != "100" || query.Get("ck") != "n1" {
+			t.Errorf("unexpected ajaxop query %s", r.URL.RawQuery)
+		}
+		answered = true
+	}
+
+	if !answered {
+		t.Error("the confirmation was not answered")
+	}
+}
+
+func TestInventory(t *testing.T) {
+	fake := newFake(t)
+	fake.SetInventory(sid, 730, 2, []steam.InventoryItem{
+		{AppID: 730, ContextID: 2, AssetID: 10, ClassID: 20, Amount: "1", Desc: &steam.EconItemDesc{ClassID: 20, Name: "AK-47 | Redline", Tradable: 1}},
+	})
+
+	session := steam.NewSession(fake.Client(), "key")
+	items, err := session.GetInventory(sid, 730, 2, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(items) != 1 || items[0].AssetID != 10 || items[0].Desc == nil || items[0].Desc.Name != "AK-47 | Redline" {
+		t.Errorf("got %+v, want the fixture item with its description", items)
+	}
+}
+
+func TestAutoRelogin(t *testing.T) {
+	for _, base := range []string{"", "https://community.test"} {
+		t.Run(base, func(t *testing.T) {
+			fake := newFake(t)
+			session := login(t, fake)
+			if err := session.SetCommunityBaseURL(base); err != nil {
+				t.Fatal(err)
+			}
+			session.SetAutoRelogin(nil)
+
+			host := steamtest.CommunityHost
+			if len(base) != 0 {
+				host = "community.test"
+			}
+
+			var expired atomic.Bool
+			expired.Store(true)
+			fake.Handle(steamtest.CommunityHost, "/login/settoken", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				http.SetCookie(w, &http.Cookie{Name: "steamLoginSecure", Value: "steamtest"})
+				expired.Store(false)
+			}))
+			fake.Handle(host, "/market/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if expired.Load() {
+					http.Redirect(w, r, "/login/home/?goto=market", http.StatusFound)
+				}
+			}))
+
+			var wg sync.WaitGroup
+			errs := make(chan error, 8)
+			for i := 0; i < 8; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+
+					session.IsLogged()
+					resp, err := session.GetClient().Get(session.CommunityBaseURL() + "/market/")
+					if err != nil {
+						errs <- err
+						return
+					}
+					resp.Body.Close()
+				}()
+			}
+			wg.Wait()
+			close(errs)
+
+			for err := range errs {
+				t.Error(err)
+			}
+
+			var logins int
+			for _, r := range fake.Requests() {
+				if r.URL.Path == "/jwt/finalizelogin" {
+					logins++
+				}
+			}
+			if logins != 2 {
+				t.Errorf("finalized %d logins, want the login and one relogin", logins)
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package steam
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+var ErrNoEvidenceStorage = errors.New("storage cannot keep confirmation evidence")
+
+// ConfirmationRecord is what gets persisted for every answered confirmation.
+type ConfirmationRecord struct {
+	ID        string           `json:"id"`
+	Type      ConfirmationType `json:"type"`
+	Creator   string           `json:"creator_id"` // trade offer or market listing ID
+	Answer    string           `json:"answer"`     // "allow" or "cancel"
+	Success   bool             `json:"success"`
+	Message   string           `json:"message,omitempty"`
+	Timestamp time.Time        `json:"timestamp"`
+}
+
+// Storage persists records produced by a Session so they outlive the
+// process, implementations must be safe for concurrent use.
+type Storage interface {
+	SaveConfirmation(record *ConfirmationRecord) error
+}
+
+// MemoryStorage keeps everything in memory, it is mostly useful for tests
+// and as a reference implementation.
+type MemoryStorage struct {
+	mu            sync.Mutex
+	confirmations []ConfirmationRecord
+	evidence      []*ConfirmationEvidence
+}
+
+func NewMemoryStorage() *MemoryStorage {
+	return &MemoryStorage{}
+}
+
+func (s *MemoryStorage) SaveConfirmation(record *ConfirmationRecord) error {
+	s.mu.Lock()
+	s.confirmations = append(s.confirmations, *record)
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *MemoryStorage) Confirmations() []ConfirmationRecord {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	records := make([]ConfirmationRecord, len(s.confirmations))
+	copy(records, s.confirmations)
+	return records
+}
+
+// SetStorage makes the session persist its records to storage, nil disables
+// persistence.
+func (session *Session) SetStorage(storage Storage) {
+	session.storage = storage
+}
+
+// ConfirmationEvidence is what Steam showed about a confirmation right
+// before it was answered, for reviewing high-value trades afterwards.
+type ConfirmationEvidence struct {
+	Confirmation *Confirmation `json:"confirmation"`
+	DetailsHTML  string        `json:"details_html"` // the mobile app details page
+	Offer        *TradeOffer   `json:"offer,omitempty"`
+	Answer       string        `json:"answer"`
+	CapturedAt   time.Time     `json:"captured_at"`
+}
+
+// EvidenceStorage is implemented by storages that can keep confirmation
+// evidence, see SetEvidenceCapture.
+type EvidenceStorage interface {
+	SaveEvidence(evidence *ConfirmationEvidence) error
+}
+
+func (s *MemoryStorage) SaveEvidence(evidence *ConfirmationEvidence) error {
+	s.mu.Lock()
+	s.evidence = append(s.evidence, evidence)
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *MemoryStorage) Evidence() []*ConfirmationEvidence {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	evidence := make([]*ConfirmationEvidence, len(s.evidence))
+	copy(evidence, s.evidence)
+	return evidence
+}
+
+// DirStorage writes every record as a JSON file into a directory, one
+// file per answered confirmation and per captured evidence.
+type DirStorage struct {
+	Dir string
+}
+
+func (s *DirStorage) write(name string, v interface{}) error {
+	b, err := json.MarshalIndent(v, "", "\t")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(s.Dir, name), b, 0600)
+}
+
+func (s *DirStorage) SaveConfirmation(record *ConfirmationRecord) error {
+	return s.write(fmt.Sprintf("confirmation-%s-%d.json", record.ID, record.Timestamp.UnixNano()), record)
+}
+
+func (s *DirStorage) SaveEvidence(evidence *ConfirmationEvidence) error {
+	return s.write(fmt.Sprintf("evidence-%s-%d.json", evidence.Confirmation.ID, evidence.CapturedAt.UnixNano()), evidence)
+}
+
+// SetEvidenceCapture makes AnswerConfirmation save evidence of the
+// confirmations capture returns true for before answering them, a nil
+// capture disables it.  The storage set with SetStorage has to implement
+// EvidenceStorage, and a confirmation is not answered when its evidence
+// cannot be saved.
+func (session *Session) SetEvidenceCapture(capture func(*Confirmation) bool) {
+	session.evidenceCapture = capture
+}
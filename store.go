@@ -7,6 +7,8 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"strconv"
+	"time"
 )
 
 var ErrInvalidPhoneNumber = errors.New("invalid phone number specified")
@@ -18,7 +20,7 @@ type PhoneAPIResponse struct {
 }
 
 func (session *Session) PrepareForSteamStore() {
-	community, _ := url.Parse("https://steamcommunity.com")
+	community, _ := url.Parse(session.CommunityBaseURL())
 	store, _ := url.Parse("https://store.steampowered.com")
 
 	session.client.Jar.SetCookies(store, session.client.Jar.Cookies(community))
@@ -40,11 +42,11 @@ func (session *Session) ValidatePhoneNumber(number string) error {
 	}
 
 	if resp == nil {
-		return errors.New("invalid response")
+		return ErrInvalidResponse
 	}
 
 	var response PhoneAPIResponse
-	if err = json.NewDecoder(resp.Body).Decode(&response); err != nil {
+	if err = session.readJSON(resp, &response); err != nil {
 		return err
 	}
 
@@ -56,6 +58,10 @@ func (session *Session) ValidatePhoneNumber(number string) error {
 }
 
 func (session *Session) AddPhoneNumber(number string) error {
+	if err := session.checkWritable(); err != nil {
+		return err
+	}
+
 	resp, err := session.client.Get("https://store.steampowered.com/phone/add_ajaxop?" + url.Values{
 		"op":        {"get_phone_number"},
 		"input":     {number},
@@ -76,11 +82,11 @@ func (session *Session) AddPhoneNumber(number string) error {
 	}
 
 	if resp == nil {
-		return errors.New("invalid response")
+		return ErrInvalidResponse
 	}
 
 	var response PhoneAPIResponse
-	if err = json.NewDecoder(resp.Body).Decode(&response); err != nil {
+	if err = session.readJSON(resp, &response); err != nil {
 		return err
 	}
 
@@ -92,6 +98,10 @@ func (session *Session) AddPhoneNumber(number string) error {
 }
 
 func (session *Session) InitiateRemovePhoneNumber() error {
+	if err := session.checkWritable(); err != nil {
+		return err
+	}
+
 	resp, err := session.client.PostForm("https://store.steampowered.com/phone/remove_confirm_sms", url.Values{
 		"sessionID": {session.sessionID},
 		"bWasEdit":  {""},
@@ -109,7 +119,7 @@ func (session *Session) InitiateRemovePhoneNumber() error {
 		return err
 	}
 	if resp == nil {
-		return errors.New("invalid response")
+		return ErrInvalidResponse
 	}
 	if resp.StatusCode != http.StatusOK {
 		return fmt.Errorf("http error: %d", resp.StatusCode)
@@ -119,6 +129,10 @@ func (session *Session) InitiateRemovePhoneNumber() error {
 }
 
 func (session *Session) ConfirmRemovePhoneNumber(mobileCode string) error {
+	if err := session.checkWritable(); err != nil {
+		return err
+	}
+
 	resp, err := session.client.PostForm("https://store.steampowered.com/phone/remove_confirm_smscode_entry", url.Values{
 		"sessionID": {session.sessionID},
 		"bWasEdit":  {""},
@@ -138,7 +152,7 @@ func (session *Session) ConfirmRemovePhoneNumber(mobileCode string) error {
 		return err
 	}
 	if resp == nil {
-		return errors.New("invalid response")
+		return ErrInvalidResponse
 	}
 	if resp.StatusCode != http.StatusOK {
 		return fmt.Errorf("http error: %d", resp.StatusCode)
@@ -167,10 +181,10 @@ func (session *Session) ReSendVerificationCode() error {
 		return err
 	}
 	if resp == nil {
-		return errors.New("invalid response")
+		return ErrInvalidResponse
 	}
 	var response PhoneAPIResponse
-	if err = json.NewDecoder(resp.Body).Decode(&response); err != nil {
+	if err = session.readJSON(resp, &response); err != nil {
 		return err
 	}
 
@@ -186,6 +200,10 @@ func (session *Session) ReSendVerificationCode() error {
 }
 
 func (session *Session) VerifyPhoneNumber(code string) error {
+	if err := session.checkWritable(); err != nil {
+		return err
+	}
+
 	resp, err := session.client.Get("https://store.steampowered.com/phone/add_ajaxop?" + url.Values{
 		"op":        {"get_sms_code"},
 		"input":     {code},
@@ -205,10 +223,10 @@ func (session *Session) VerifyPhoneNumber(code string) error {
 		return err
 	}
 	if resp == nil {
-		return errors.New("invalid response")
+		return ErrInvalidResponse
 	}
 	var response PhoneAPIResponse
-	if err = json.NewDecoder(resp.Body).Decode(&response); err != nil {
+	if err = session.readJSON(resp, &response); err != nil {
 		return err
 	}
 
@@ -218,3 +236,276 @@ func (session *Session) VerifyPhoneNumber(code string) error {
 
 	return nil
 }
+
+const storeCheckoutURL = "https://store.steampowered.com/checkout/"
+
+var (
+	ErrEmptyCart           = errors.New("shopping cart is empty")
+	ErrTransactionFailed   = errors.New("store transaction failed")
+	ErrTransactionTimedOut = errors.New("store transaction did not complete in time")
+	ErrPriceTooHigh        = errors.New("final price is above the maximum")
+)
+
+// The purchase types of GetFinalPrice, buying for the account itself or as
+// a gift.
+const (
+	PurchaseTypeSelf = "self"
+	PurchaseTypeGift = "gift"
+)
+
+// Transaction is a store checkout in progress, see InitTransaction.
+type Transaction struct {
+	ID      string
+	CartGID string
+}
+
+// FinalPrice amounts are in cents of the wallet currency.
+type FinalPrice struct {
+	Base           uint64
+	Tax            uint64
+	Total          uint64
+	FormattedTotal string
+}
+
+// GiftOptions turns a purchase into a gift, the giftee has to be a friend.
+type GiftOptions struct {
+	Giftee    SteamID
+	Name      string
+	Message   string
+	Signature string
+}
+
+func (session *Session) postStoreForm(u string, values url.Values, v interface{}) error {
+	values.Set("sessionid", session.sessionID)
+
+	resp, err := session.client.PostForm(u, values)
+	if resp != nil {
+		defer func(Body io.ReadCloser) {
+			err := Body.Close()
+			if err != nil {
+				return
+			}
+		}(resp.Body)
+	}
+
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("http error: %d", resp.StatusCode)
+	}
+
+	if v == nil {
+		return nil
+	}
+
+	return session.readJSON(resp, v)
+}
+
+// cartGID is the shopping cart the store keeps in the shoppingCartGID
+// cookie.
+func (session *Session) cartGID() string {
+	store, _ := url.Parse("https://store.steampowered.com")
+	for _, cookie := range session.client.Jar.Cookies(store) {
+		if cookie.Name == "shoppingCartGID" {
+			return cookie.Value
+		}
+	}
+
+	return ""
+}
+
+// AddToCart adds a package (subid) to the store shopping cart,
+// PrepareForSteamStore has to be called first.
+func (session *Session) AddToCart(subID uint32) error {
+	if err := session.checkWritable(); err != nil {
+		return err
+	}
+
+	return session.postStoreForm("https://store.steampowered.com/cart/", url.Values{
+		"action": {"add_to_cart"},
+		"subid":  {strconv.FormatUint(uint64(subID), 10)},
+	}, nil)
+}
+
+// InitTransaction starts paying the cart with the Steam wallet, gift may
+// be nil to buy for the account itself.
+func (session *Session) InitTransaction(gift *GiftOptions) (*Transaction, error) {
+	if err := session.checkWritable(); err != nil {
+		return nil, err
+	}
+
+	cart := session.cartGID()
+	if len(cart) == 0 {
+		return nil, ErrEmptyCart
+	}
+
+	values := url.Values{
+		"gidShoppingCart":           {cart},
+		"gidReplayOfTransID":        {"-1"},
+		"PaymentMethod":             {"steamaccount"},
+		"abortPendingTransactions":  {"0"},
+		"bHasCardInfo":              {"0"},
+		"bIsGift":                   {"0"},
+		"GifteeAccountID":           {"0"},
+		"bSaveBillingAddress":       {"1"},
+		"bUseRemainingSteamAccount": {"1"},
+		"bPreAuthOnly":              {"0"},
+	}
+
+	if gift != nil {
+		values.Set("bIsGift", "1")
+		values.Set("GifteeAccountID", strconv.FormatUint(uint64(gift.Giftee.GetAccountID()), 10))
+		values.Set("GifteeName", gift.Name)
+		values.Set("GiftMessage", gift.Message)
+		values.Set("GiftSignature", gift.Signature)
+		values.Set("GiftSentiment", "Best Wishes")
+		values.Set("ScheduledSendOnDate", "0")
+	}
+
+	var response struct {
+		Success int    `json:"success"`
+		TransID string `json:"transid"`
+	}
+	if err := session.postStoreForm(storeCheckoutURL+"inittransaction/", values, &response); err != nil {
+		return nil, err
+	}
+
+	if response.Success != 1 || len(response.TransID) == 0 {
+		return nil, ErrTransactionFailed
+	}
+
+	return &Transaction{ID: response.TransID, CartGID: cart}, nil
+}
+
+// GetFinalPrice is what finalizing the transaction will take from the
+// wallet, purchaseType is PurchaseTypeGift for a transaction started with
+// GiftOptions and PurchaseTypeSelf otherwise.
+func (session *Session) GetFinalPrice(tx *Transaction, purchaseType string) (*FinalPrice, error) {
+	body, err := session.getPage(storeCheckoutURL + "getfinalprice/?" + url.Values{
+		"count":              {"1"},
+		"transid":            {tx.ID},
+		"purchasetype":       {purchaseType},
+		"microtxnid":         {"-1"},
+		"cart":               {tx.CartGID},
+		"gidReplayOfTransID": {"-1"},
+	}.Encode())
+	if err != nil {
+		return nil, err
+	}
+
+	var response struct {
+		Success        int        `json:"success"`
+		Base           flexNumber `json:"base"`
+		Tax            flexNumber `json:"tax"`
+		Total          flexNumber `json:"total"`
+		FormattedTotal string     `json:"formattedTotal"`
+	}
+	if err = json.Unmarshal(body, &response); err != nil {
+		return nil, err
+	}
+
+	if response.Success != 1 {
+		return nil, ErrTransactionFailed
+	}
+
+	return &FinalPrice{
+		Base:           uint64(response.Base),
+		Tax:            uint64(response.Tax),
+		Total:          uint64(response.Total),
+		FormattedTotal: response.FormattedTotal,
+	}, nil
+}
+
+// FinalizeTransaction pays and waits for the store to report the purchase
+// complete.
+func (session *Session) FinalizeTransaction(tx *Transaction) error {
+	if err := session.checkWritable(); err != nil {
+		return err
+	}
+
+	var response struct {
+		Success int `json:"success"`
+	}
+	err := session.postStoreForm(storeCheckoutURL+"finalizetransaction/", url.Values{
+		"transid":     {tx.ID},
+		"CardCVV2":    {""},
+		"browserInfo": {`{"language":"en-US","javaEnabled":"false","colorDepth":24,"screenHeight":1080,"screenWidth":1920}`},
+	}, &response)
+	if err != nil {
+		return err
+	}
+
+	/* 22 means pending, the status has to be polled.  */
+	if response.Success != 1 && response.Success != 22 {
+		return ErrTransactionFailed
+	}
+
+	for i := 0; i < 10; i++ {
+		body, err := session.getPage(storeCheckoutURL + "transactionstatus/?" + url.Values{
+			"count":   {"1"},
+			"transid": {tx.ID},
+		}.Encode())
+		if err != nil {
+			return err
+		}
+
+		var status struct {
+			Success              int `json:"success"`
+			PurchaseResultDetail int `json:"purchaseresultdetail"`
+		}
+		if err = json.Unmarshal(body, &status); err != nil {
+			return err
+		}
+
+		switch status.Success {
+		case 1:
+			return nil
+		case 22:
+			time.Sleep(time.Second)
+		default:
+			return fmt.Errorf("%w: purchase result %d", ErrTransactionFailed, status.PurchaseResultDetail)
+		}
+	}
+
+	return ErrTransactionTimedOut
+}
+
+// PurchaseWithWallet buys the packages with the wallet in one go and
+// returns what was paid.  maxPrice is the most the purchase may cost in
+// cents of the wallet currency, the transaction is not finalized and
+// ErrPriceTooHigh returned along with the price when the total is above it.
+func (session *Session) PurchaseWithWallet(gift *GiftOptions, maxPrice uint64, subIDs ...uint32) (*FinalPrice, error) {
+	session.PrepareForSteamStore()
+
+	for _, subID := range subIDs {
+		if err := session.AddToCart(subID); err != nil {
+			return nil, err
+		}
+	}
+
+	tx, err := session.InitTransaction(gift)
+	if err != nil {
+		return nil, err
+	}
+
+	purchaseType := PurchaseTypeSelf
+	if gift != nil {
+		purchaseType = PurchaseTypeGift
+	}
+
+	price, err := session.GetFinalPrice(tx, purchaseType)
+	if err != nil {
+		return nil, err
+	}
+
+	if price.Total > maxPrice {
+		return price, fmt.Errorf("%w: %s", ErrPriceTooHigh, price.FormattedTotal)
+	}
+
+	if err = session.FinalizeTransaction(tx); err != nil {
+		return nil, err
+	}
+
+	return price, nil
+}
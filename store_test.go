@@ -0,0 +1,78 @@
+package steam
+
+import (
+	"errors"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"sync"
+	"testing"
+)
+
+func TestPurchaseWithWallet(t *testing.T) {
+	tests := []struct {
+		name      string
+		gift      *GiftOptions
+		maxPrice  uint64
+		err       error
+		finalized bool
+		purchase  string
+	}{
+		{"within the maximum", nil, 1000, nil, true, PurchaseTypeSelf},
+		{"above the maximum", nil, 999, ErrPriceTooHigh, false, PurchaseTypeSelf},
+		{"gift", &GiftOptions{Giftee: testSteamID, Name: "friend"}, 1000, nil, true, PurchaseTypeGift},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				mu        sync.Mutex
+				finalized bool
+				purchase  string
+			)
+			session := newTestSession(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				mu.Lock()
+				defer mu.Unlock()
+
+				switch r.URL.Path {
+				case "/cart/":
+					answer(`{}`).ServeHTTP(w, r)
+				case "/checkout/inittransaction/":
+					answer(`{"success":1,"transid":"42"}`).ServeHTTP(w, r)
+				case "/checkout/getfinalprice/":
+					purchase = r.URL.Query().Get("purchasetype")
+					answer(`{"success":1,"base":"1000","tax":"0","total":"1000","formattedTotal":"$10.00"}`).ServeHTTP(w, r)
+				case "/checkout/finalizetransaction/":
+					finalized = true
+					answer(`{"success":1}`).ServeHTTP(w, r)
+				case "/checkout/transactionstatus/":
+					answer(`{"success":1}`).ServeHTTP(w, r)
+				default:
+					http.NotFound(w, r)
+				}
+			}))
+
+			jar, _ := cookiejar.New(nil)
+			store, _ := url.Parse("https://store.steampowered.com")
+			jar.SetCookies(store, []*http.Cookie{{Name: "shoppingCartGID", Value: "7"}})
+			session.client.Jar = jar
+
+			price, err := session.PurchaseWithWallet(tt.gift, tt.maxPrice, 1)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("got %v, want %v", err, tt.err)
+			}
+			if price == nil || price.Total != 1000 {
+				t.Errorf("got price %+v, want a total of 1000", price)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			if finalized != tt.finalized {
+				t.Errorf("finalized %v, want %v", finalized, tt.finalized)
+			}
+			if purchase != tt.purchase {
+				t.Errorf("purchase type %q, want %q", purchase, tt.purchase)
+			}
+		})
+	}
+}
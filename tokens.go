@@ -0,0 +1,163 @@
+package steam
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/hiship/go-steam/pb"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// accessTokenMargin is how long before its expiry an access token is
+	// renewed.
+	accessTokenMargin = 5 * time.Minute
+
+	// A failed renewal is not tried again before the backoff has passed,
+	// it doubles with every failure in a row up to the maximum.
+	minTokenRenewalBackoff = 30 * time.Second
+	maxTokenRenewalBackoff = 10 * time.Minute
+)
+
+var ErrNoRefreshToken = errors.New("no refresh token to renew the access token with")
+
+// tokenExpiry reads the exp claim of a Steam JWT, the zero time when it
+// cannot be parsed.
+func tokenExpiry(token string) time.Time {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return time.Time{}
+	}
+
+	var claims struct {
+		Exp int64 `json:"exp"`
+	}
+	if err = json.Unmarshal(payload, &claims); err != nil || claims.Exp == 0 {
+		return time.Time{}
+	}
+
+	return time.Unix(claims.Exp, 0)
+}
+
+func (session *Session) setAccessToken(token string) {
+	session.oauth.Token = token
+	session.accessTokenExpiry = tokenExpiry(token)
+}
+
+// RenewAccessToken mints a new access token from the refresh token of the
+// session.  Steam may rotate the refresh token as well, the new one is
+// kept then.
+func (session *Session) RenewAccessToken() error {
+	session.tokenMu.Lock()
+	defer session.tokenMu.Unlock()
+
+	return session.renewAccessToken()
+}
+
+// renewAccessToken renews the token and keeps track of failures for the
+// backoff of AccessToken.
+func (session *Session) renewAccessToken() error {
+	err := session.generateAccessToken()
+	if err == nil {
+		session.tokenRenewalErr = nil
+		session.tokenRenewalBackoff = 0
+		return nil
+	}
+
+	session.tokenRenewalBackoff = min(max(2*session.tokenRenewalBackoff, minTokenRenewalBackoff), maxTokenRenewalBackoff)
+
+	session.tokenRenewalErr = err
+	session.tokenRenewalRetry = time.Now().Add(session.tokenRenewalBackoff)
+	session.log().Warnf("access token renewal failed, next try in %v: %v", session.tokenRenewalBackoff, err)
+	return err
+}
+
+func (session *Session) generateAccessToken() error {
+	if len(session.refreshToken) == 0 {
+		return ErrNoRefreshToken
+	}
+
+	var resp pb.CAuthentication_AccessToken_GenerateForApp_Response
+	err := session.postService("IAuthenticationService/GenerateAccessTokenForApp", url.Values{}, &pb.CAuthentication_AccessToken_GenerateForApp_Request{
+		RefreshToken: proto.String(session.refreshToken),
+		Steamid:      proto.Uint64(uint64(session.oauth.SteamID)),
+		RenewalType:  pb.ETokenRenewalType_k_ETokenRenewalType_Allow.Enum(),
+	}, &resp)
+	if err != nil {
+		return err
+	}
+
+	if len(resp.GetAccessToken()) == 0 {
+		return ErrInvalidResponse
+	}
+
+	session.setAccessToken(resp.GetAccessToken())
+	if len(resp.GetRefreshToken()) != 0 {
+		session.refreshToken = resp.GetRefreshToken()
+	}
+
+	return nil
+}
+
+// AccessToken returns a valid WebAPI access token, renewing it first when
+// it expires within a few minutes.  After a failed renewal the error is
+// returned again, without asking Steam, until the backoff has passed.
+func (session *Session) AccessToken() (string, error) {
+	session.tokenMu.Lock()
+	defer session.tokenMu.Unlock()
+
+	if len(session.oauth.Token) != 0 && time.Until(session.accessTokenExpiry) > accessTokenMargin {
+		return session.oauth.Token, nil
+	}
+
+	if session.tokenRenewalErr != nil && time.Now().Before(session.tokenRenewalRetry) {
+		return "", session.tokenRenewalErr
+	}
+
+	if err := session.renewAccessToken(); err != nil {
+		return "", err
+	}
+
+	return session.oauth.Token, nil
+}
+
+// AccessTokenExpiry is when the current access token expires, the zero
+// time without one.
+func (session *Session) AccessTokenExpiry() time.Time {
+	session.tokenMu.Lock()
+	defer session.tokenMu.Unlock()
+
+	return session.accessTokenExpiry
+}
+
+// authorize sets the credential of a WebAPI call that takes either: the
+// access token when one can be had, the API key otherwise.  Renewal
+// failures are logged by renewAccessToken, and backed off.
+func (session *Session) authorize(params url.Values) url.Values {
+	session.tokenMu.Lock()
+	loggedIn := len(session.oauth.Token) != 0 || len(session.refreshToken) != 0
+	session.tokenMu.Unlock()
+
+	if loggedIn {
+		token, err := session.AccessToken()
+		if err == nil {
+			params.Del("key")
+			params.Set("access_token", token)
+			return params
+		}
+
+		session.log().Debugf("no access token, using the API key: %v", err)
+	}
+
+	params.Set("key", session.apiKey)
+	return params
+}
@@ -0,0 +1,102 @@
+package steam
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// testAccessToken is a JWT, unsigned, expiring at exp.
+func testAccessToken(exp time.Time) string {
+	claims := base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf(`{"exp":%d}`, exp.Unix())))
+	return "eyJhbGciOiJub25lIn0." + claims + ".sig"
+}
+
+func TestAuthorizeBacksOffRenewal(t *testing.T) {
+	var renewals int32
+	session := newTestSession(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&renewals, 1)
+		w.Header().Set("x-eresult", "2")
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	session.refreshToken = "refresh"
+
+	for i := 0; i < 3; i++ {
+		params := session.authorize(url.Values{})
+		if params.Get("key") != "key" || params.Has("access_token") {
+			t.Fatalf("got %s, want the API key", params.Encode())
+		}
+	}
+
+	if n := atomic.LoadInt32(&renewals); n != 1 {
+		t.Errorf("renewal tried %d times, want once during the backoff", n)
+	}
+
+	if session.tokenRenewalBackoff != minTokenRenewalBackoff {
+		t.Errorf("backoff %v, want %v", session.tokenRenewalBackoff, minTokenRenewalBackoff)
+	}
+
+	// Past the backoff the renewal is tried again, and the backoff doubles.
+	session.tokenRenewalRetry = time.Now()
+	if _, err := session.AccessToken(); err == nil {
+		t.Fatal("renewal succeeded")
+	}
+
+	if n := atomic.LoadInt32(&renewals); n != 2 {
+		t.Errorf("renewal tried %d times, want twice", n)
+	}
+
+	if session.tokenRenewalBackoff != 2*minTokenRenewalBackoff {
+		t.Errorf("backoff %v, want %v", session.tokenRenewalBackoff, 2*minTokenRenewalBackoff)
+	}
+}
+
+func TestWebAPIPrefersAccessToken(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		queries []url.Values
+	)
+	session := newTestSession(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/IAuthenticationService/") {
+			w.Header().Set("x-eresult", "2")
+			return
+		}
+
+		mu.Lock()
+		queries = append(queries, r.URL.Query())
+		mu.Unlock()
+		answer(`{"response":{}}`).ServeHTTP(w, r)
+	}))
+	session.refreshToken = "refresh"
+	session.setAccessToken(testAccessToken(time.Now().Add(time.Hour)))
+
+	// Renewals race with the calls reading the token.
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		session.RenewAccessToken()
+	}()
+
+	sid := testSteamID
+	session.GetPlayerSummaries(sid.ToString())
+	session.GetSteamLevel(testSteamID)
+	session.GetOwnedGames(testSteamID, false, false)
+	session.GetGameServerAccounts()
+	wg.Wait()
+
+	if len(queries) != 4 {
+		t.Fatalf("got %d calls, want 4", len(queries))
+	}
+	for _, query := range queries {
+		if query.Has("key") || len(query.Get("access_token")) == 0 {
+			t.Errorf("got %s, want the access token alone", query.Encode())
+		}
+	}
+}
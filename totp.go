@@ -2,10 +2,12 @@ package steam
 
 import (
 	"crypto/hmac"
+	"crypto/md5"
 	"crypto/sha1"
 	"encoding/base64"
 	"encoding/binary"
-	"encoding/json"
+	"encoding/hex"
+	"fmt"
 	"net/http"
 )
 
@@ -72,6 +74,24 @@ func GenerateConfirmationCode(identitySecret, tag string, current int64) (string
 	return base64.StdEncoding.EncodeToString(hash.Sum(nil)), nil
 }
 
+// LegacyDeviceID is the android device ID Login derives by default, from
+// the MD5 of the account name and password.  Authenticators enrolled by
+// this package were registered with it.
+func LegacyDeviceID(accountName, password string) string {
+	sum := md5.Sum([]byte(accountName + password))
+	return fmt.Sprintf("android:%x-%x-%x-%x-%x", sum[:2], sum[2:4], sum[4:6], sum[6:8], sum[8:10])
+}
+
+// GenerateDeviceID derives the android device ID of a SteamID the way the
+// mobile authenticator tools do: "android:" followed by the SHA-1 of the
+// SteamID formatted as a GUID.  Login uses it instead of LegacyDeviceID
+// once SetSteamIDDeviceID is enabled.
+func GenerateDeviceID(sid SteamID) string {
+	sum := sha1.Sum([]byte(sid.ToString()))
+	h := hex.EncodeToString(sum[:])
+	return "android:" + h[:8] + "-" + h[8:12] + "-" + h[12:16] + "-" + h[16:20] + "-" + h[20:32]
+}
+
 func GetTimeTip() (*ServerTimeTip, error) {
 	resp, err := http.Post(APIBaseUrl+"/ITwoFactorService/QueryTime/v1/", "application/x-www-form-urlencoded", nil)
 	if resp != nil {
@@ -87,9 +107,13 @@ func GetTimeTip() (*ServerTimeTip, error) {
 	}
 
 	var response Response
-	if err = json.NewDecoder(resp.Body).Decode(&response); err != nil {
+	if err = readJSON(resp, &response); err != nil {
 		return nil, err
 	}
 
+	if response.Inner == nil {
+		return nil, ErrInvalidResponse
+	}
+
 	return response.Inner, nil
 }
@@ -0,0 +1,86 @@
+package steam
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// tradeErrorExp matches the EResult Steam appends to trade offer errors:
+//
+//	There was an error accepting this trade offer. Please try again later. (11)
+var tradeErrorExp = regexp.MustCompile(`\((\d+)\)\s*$`)
+
+// TradeError is returned when Steam refuses a trade offer action with a
+// strError message, EResult is 0 when the message does not carry one.
+type TradeError struct {
+	Message string
+	EResult int
+}
+
+func (e *TradeError) Error() string {
+	return e.Message
+}
+
+// Temporary reports whether retrying the same action later may succeed.
+func (e *TradeError) Temporary() bool {
+	if strings.Contains(strings.ToLower(e.Message), "try again later") {
+		return true
+	}
+
+	switch e.EResult {
+	case 16, 20, 28: // Timeout, ServiceUnavailable, TryAnotherCM
+		return true
+	}
+
+	return false
+}
+
+func newTradeError(msg string) error {
+	e := &TradeError{Message: msg}
+	if m := tradeErrorExp.FindStringSubmatch(msg); m != nil {
+		e.EResult, _ = strconv.Atoi(m[1])
+	}
+
+	return e
+}
+
+// maxTradeRetryBackoff caps the delay between AcceptTradeOfferWithRetry
+// attempts.
+const maxTradeRetryBackoff = time.Minute
+
+// AcceptTradeOfferWithRetry accepts the offer, retrying up to attempts times
+// while Steam answers with a temporary TradeError.  The offer is tried at
+// least once whatever attempts is.  The delay between attempts starts at
+// backoff and doubles every time, up to a minute.
+func (session *Session) AcceptTradeOfferWithRetry(id uint64, attempts int, backoff time.Duration) (*AcceptTradeOfferResponse, error) {
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	var err error
+	for attempt := 0; attempt < attempts; attempt++ {
+		if attempt != 0 {
+			time.Sleep(backoff)
+			if backoff *= 2; backoff > maxTradeRetryBackoff {
+				backoff = maxTradeRetryBackoff
+			}
+		}
+
+		var response *AcceptTradeOfferResponse
+		if response, err = session.AcceptTradeOffer(id); err == nil {
+			return response, nil
+		}
+
+		var tradeErr *TradeError
+		if !errors.As(err, &tradeErr) || !tradeErr.Temporary() {
+			return nil, err
+		}
+
+		session.log().Warnf("accepting offer %d failed, attempt %d/%d: %v", id, attempt+1, attempts, err)
+	}
+
+	return nil, err
+}
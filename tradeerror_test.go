@@ -0,0 +1,42 @@
+package steam
+
+import (
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+)
+
+func TestAcceptTradeOfferWithRetry(t *testing.T) {
+	tests := []struct {
+		name     string
+		attempts int
+		body     string
+		calls    int32
+	}{
+		{"no attempts still tries once", 0, `{"strError":"There was an error accepting this trade offer. Please try again later. (16)"}`, 1},
+		{"retries temporary errors", 3, `{"strError":"There was an error accepting this trade offer. Please try again later. (16)"}`, 3},
+		{"gives up on permanent errors", 3, `{"strError":"This trade offer is no longer valid. (25)"}`, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int32
+			session := newTestSession(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&calls, 1)
+				w.WriteHeader(http.StatusInternalServerError)
+				w.Write([]byte(tt.body))
+			}))
+
+			response, err := session.AcceptTradeOfferWithRetry(1, tt.attempts, 0)
+			var tradeErr *TradeError
+			if response != nil || !errors.As(err, &tradeErr) {
+				t.Fatalf("got %v, %v, want a TradeError", response, err)
+			}
+
+			if calls := atomic.LoadInt32(&calls); calls != tt.calls {
+				t.Errorf("got %d calls, want %d", calls, tt.calls)
+			}
+		})
+	}
+}
@@ -5,16 +5,16 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"maps"
 	"net/http"
 	"net/url"
-	"regexp"
 	"strconv"
 	"strings"
 	"time"
 )
 
 const (
-	TradeStateNone = iota
+	TradeStateNone TradeState = iota
 	TradeStateInvalid
 	TradeStateActive
 	TradeStateAccepted
@@ -29,45 +29,39 @@ const (
 )
 
 const (
-	TradeConfirmationNone = iota
+	TradeConfirmationNone ConfirmationMethod = iota
 	TradeConfirmationEmail
 	TradeConfirmationMobileApp
 	TradeConfirmationMobile
 )
 
 const (
-	TradeFilterNone             = iota
-	TradeFilterSentOffers       = 1 << 0
-	TradeFilterRecvOffers       = 1 << 1
-	TradeFilterActiveOnly       = 1 << 3
-	TradeFilterHistoricalOnly   = 1 << 4
-	TradeFilterItemDescriptions = 1 << 5
+	TradeFilterNone             TradeFilter = 0
+	TradeFilterSentOffers       TradeFilter = 1 << 0
+	TradeFilterRecvOffers       TradeFilter = 1 << 1
+	TradeFilterActiveOnly       TradeFilter = 1 << 3
+	TradeFilterHistoricalOnly   TradeFilter = 1 << 4
+	TradeFilterItemDescriptions TradeFilter = 1 << 5
 )
 
 var (
-	// receiptExp matches JSON in the following form:
-	//	oItem = {"id":"...",...}; (Javascript code)
-	receiptExp    = regexp.MustCompile("oItem =\\s(.+?});")
-	myEscrowExp   = regexp.MustCompile("var g_daysMyEscrow = (\\d+);")
-	themEscrowExp = regexp.MustCompile("var g_daysTheirEscrow = (\\d+);")
-	errorMsgExp   = regexp.MustCompile("<div id=\"error_msg\">\\s*([^<]+)\\s*</div>")
-	offerInfoExp  = regexp.MustCompile("token=([a-zA-Z0-9-_]+)")
-
-	apiGetTradeOffer         = APIBaseUrl + "/IEconService/GetTradeOffer/v1/?"
-	apiGetTradeOffers        = APIBaseUrl + "/IEconService/GetTradeOffers/v1/?"
-	apiGetTradeOffersSummary = APIBaseUrl + "/IEconService/GetTradeOffersSummary/v1/?"
-	apiDeclineTradeOffer     = APIBaseUrl + "/IEconService/DeclineTradeOffer/v1/"
-	apiCancelTradeOffer      = APIBaseUrl + "/IEconService/CancelTradeOffer/v1/"
+	apiGetTradeOffer         = "IEconService/GetTradeOffer"
+	apiGetTradeOffers        = "IEconService/GetTradeOffers"
+	apiGetTradeOffersSummary = "IEconService/GetTradeOffersSummary"
+	apiDeclineTradeOffer     = "IEconService/DeclineTradeOffer"
+	apiCancelTradeOffer      = "IEconService/CancelTradeOffer"
+	apiGetTradeHoldDurations = "IEconService/GetTradeHoldDurations"
 
 	ErrReceiptMatch        = errors.New("unable to match items in trade receipt")
 	ErrCannotAcceptActive  = errors.New("unable to accept a non-active trade")
 	ErrCannotFindOfferInfo = errors.New("unable to match data from trade offer url")
+	ErrInvalidTradeToken   = errors.New("trade offer token is not valid for this partner")
 )
 
 type EconItem struct {
-	AssetID    string `json:"assetid,string,omitempty"`
-	InstanceID string `json:"instanceid,string,omitempty"`
-	ClassID    string `json:"classid,string,omitempty"`
+	AssetID    string `json:"assetid,omitempty"`
+	InstanceID string `json:"instanceid,omitempty"`
+	ClassID    string `json:"classid,omitempty"`
 	AppID      uint32 `json:"appid"`
 	ContextID  string `json:"contextid"`
 	Amount     string `json:"amount"`
@@ -118,20 +112,20 @@ type EconItemDesc struct {
 }
 
 type TradeOffer struct {
-	ID                 uint64      `json:"tradeofferid,string"`
-	Partner            uint32      `json:"accountid_other"`
-	ReceiptID          uint64      `json:"tradeid,string"`
-	RecvItems          []*EconItem `json:"items_to_receive"`
-	SendItems          []*EconItem `json:"items_to_give"`
-	Message            string      `json:"message"`
-	State              uint8       `json:"trade_offer_state"`
-	ConfirmationMethod uint8       `json:"confirmation_method"`
-	Created            int64       `json:"time_created"`
-	Updated            int64       `json:"time_updated"`
-	Expires            int64       `json:"expiration_time"`
-	EscrowEndDate      int64       `json:"escrow_end_date"`
-	RealTime           bool        `json:"from_real_time_trade"`
-	IsOurOffer         bool        `json:"is_our_offer"`
+	ID                 uint64             `json:"tradeofferid,string"`
+	Partner            uint32             `json:"accountid_other"`
+	ReceiptID          uint64             `json:"tradeid,string"`
+	RecvItems          []*EconItem        `json:"items_to_receive"`
+	SendItems          []*EconItem        `json:"items_to_give"`
+	Message            string             `json:"message"`
+	State              TradeState         `json:"trade_offer_state"`
+	ConfirmationMethod ConfirmationMethod `json:"confirmation_method"`
+	Created            int64              `json:"time_created"`
+	Updated            int64              `json:"time_updated"`
+	Expires            int64              `json:"expiration_time"`
+	EscrowEndDate      int64              `json:"escrow_end_date"`
+	RealTime           bool               `json:"from_real_time_trade"`
+	IsOurOffer         bool               `json:"is_our_offer"`
 }
 
 type TradeOffersSummaryResponse struct {
@@ -152,6 +146,9 @@ type TradeOfferResponse struct {
 	SentOffers     []*TradeOffer   `json:"trade_offers_sent"`     // GetTradeOffers
 	ReceivedOffers []*TradeOffer   `json:"trade_offers_received"` // GetTradeOffers
 	Descriptions   []*EconItemDesc `json:"descriptions"`          // GetTradeOffers
+	NextCursor     uint32          `json:"next_cursor"`           // GetTradeOffers, 0 on the last page
+
+	cache *DescriptionCache
 }
 
 type APIResponse struct {
@@ -159,10 +156,9 @@ type APIResponse struct {
 }
 
 func (session *Session) GetTradeOffer(id uint64) (*TradeOffer, error) {
-	resp, err := session.client.Get(apiGetTradeOffer + url.Values{
-		"key":          {session.apiKey},
+	resp, err := session.client.Get(session.apiURL(apiGetTradeOffer) + "?" + session.authorize(url.Values{
 		"tradeofferid": {strconv.FormatUint(id, 10)},
-	}.Encode())
+	}).Encode())
 	if resp != nil {
 		defer func(Body io.ReadCloser) {
 			err := Body.Close()
@@ -176,29 +172,31 @@ func (session *Session) GetTradeOffer(id uint64) (*TradeOffer, error) {
 		return nil, err
 	}
 	if resp == nil {
-		return nil, errors.New("invalid response")
+		return nil, ErrInvalidResponse
 	}
 	var response APIResponse
-	if err = json.NewDecoder(resp.Body).Decode(&response); err != nil {
+	if err = session.readJSON(resp, &response); err != nil {
 		return nil, err
 	}
 
+	if response.Inner == nil || response.Inner.Offer == nil {
+		return nil, ErrInvalidResponse
+	}
+
 	return response.Inner.Offer, nil
 }
 
-func testBit(bits uint32, bit uint32) bool {
+func testBit(bits TradeFilter, bit TradeFilter) bool {
 	return (bits & bit) == bit
 }
 
 func (session *Session) GetTradeOffersSummary(lastVisitTime uint32) (*TradeOffersSummaryResponse, error) {
-	params := url.Values{
-		"key": {session.apiKey},
-	}
+	params := session.authorize(url.Values{})
 	//用户上次访问的时间。 若未传入，将使用用户上次访问交易报价页面的时间。
 	if lastVisitTime != 0 {
 		params.Add("time_last_visit", strconv.FormatUint(uint64(lastVisitTime), 10))
 	}
-	resp, err := session.client.Get(apiGetTradeOffersSummary + params.Encode())
+	resp, err := session.client.Get(session.apiURL(apiGetTradeOffersSummary) + "?" + params.Encode())
 	if resp != nil {
 		defer func(Body io.ReadCloser) {
 			err := Body.Close()
@@ -212,22 +210,101 @@ func (session *Session) GetTradeOffersSummary(lastVisitTime uint32) (*TradeOffer
 		return nil, err
 	}
 	if resp == nil {
-		return nil, errors.New("invalid response")
+		return nil, ErrInvalidResponse
 	}
 	var response struct {
 		Inner *TradeOffersSummaryResponse `json:"response"`
 	}
-	if err = json.NewDecoder(resp.Body).Decode(&response); err != nil {
+	if err = session.readJSON(resp, &response); err != nil {
 		return nil, err
 	}
 
+	if response.Inner == nil {
+		return nil, ErrInvalidResponse
+	}
+
 	return response.Inner, nil
 }
 
-func (session *Session) GetTradeOffers(filter uint32, timeCutOff time.Time) (*TradeOfferResponse, error) {
-	params := url.Values{
-		"key": {session.apiKey},
+func (session *Session) GetTradeOffers(filter TradeFilter, timeCutOff time.Time) (*TradeOfferResponse, error) {
+	return session.GetTradeOffersProgress(filter, timeCutOff, nil)
+}
+
+// GetTradeOffersProgress is GetTradeOffers calling progress after every
+// page with the number of pages and offers fetched so far.  Steam pages
+// the results, the pages are fetched until the last one and merged.
+func (session *Session) GetTradeOffersProgress(filter TradeFilter, timeCutOff time.Time, progress func(pages, offers int)) (*TradeOfferResponse, error) {
+	params := tradeOffersParams(filter, timeCutOff)
+	session.log().Debugf("GetTradeOffers: get_sent_offers=%s get_received_offers=%s active_only=%s historical_only=%s",
+		params.Get("get_sent_offers"), params.Get("get_received_offers"), params.Get("active_only"), params.Get("historical_only"))
+
+	merged := &TradeOfferResponse{}
+	seen := make(map[[2]uint64]bool)
+	var cursor uint32
+	for pages := 1; ; pages++ {
+		page, err := session.getTradeOffersPage(params, cursor)
+		if err != nil {
+			return nil, err
+		}
+
+		merged.SentOffers = append(merged.SentOffers, page.SentOffers...)
+		merged.ReceivedOffers = append(merged.ReceivedOffers, page.ReceivedOffers...)
+		for _, desc := range page.Descriptions {
+			key := [2]uint64{desc.ClassID, desc.InstanceID}
+			if !seen[key] {
+				seen[key] = true
+				merged.Descriptions = append(merged.Descriptions, desc)
+			}
+		}
+
+		if progress != nil {
+			progress(pages, len(merged.SentOffers)+len(merged.ReceivedOffers))
+		}
+
+		// A cursor that does not move would loop forever.
+		if page.NextCursor == 0 || page.NextCursor == cursor {
+			break
+		}
+		cursor = page.NextCursor
+	}
+
+	if session.descriptions != nil {
+		merged.cache = session.descriptions
+	}
+
+	return merged, nil
+}
+
+// IterateTradeOffers calls fn with the offers matching filter one page at
+// a time, sent offers of a page first, until fn returns false or the last
+// page.  Only the current page is held in memory.
+func (session *Session) IterateTradeOffers(filter TradeFilter, timeCutOff time.Time, fn func(offer *TradeOffer) bool) error {
+	params := tradeOffersParams(filter, timeCutOff)
+
+	var cursor uint32
+	for {
+		page, err := session.getTradeOffersPage(params, cursor)
+		if err != nil {
+			return err
+		}
+
+		for _, offers := range [][]*TradeOffer{page.SentOffers, page.ReceivedOffers} {
+			for _, offer := range offers {
+				if !fn(offer) {
+					return nil
+				}
+			}
+		}
+
+		if page.NextCursor == 0 || page.NextCursor == cursor {
+			return nil
+		}
+		cursor = page.NextCursor
 	}
+}
+
+func tradeOffersParams(filter TradeFilter, timeCutOff time.Time) url.Values {
+	params := url.Values{}
 	if testBit(filter, TradeFilterSentOffers) {
 		params.Set("get_sent_offers", "1")
 	}
@@ -248,8 +325,18 @@ func (session *Session) GetTradeOffers(filter uint32, timeCutOff time.Time) (*Tr
 	if testBit(filter, TradeFilterHistoricalOnly) {
 		params.Set("historical_only", "1")
 	}
-	println(params.Encode())
-	resp, err := session.client.Get(apiGetTradeOffers + params.Encode())
+
+	return params
+}
+
+// getTradeOffersPage fetches the page of GetTradeOffers starting at cursor.
+func (session *Session) getTradeOffersPage(params url.Values, cursor uint32) (*TradeOfferResponse, error) {
+	query := session.authorize(maps.Clone(params))
+	if cursor != 0 {
+		query.Set("cursor", strconv.FormatUint(uint64(cursor), 10))
+	}
+
+	resp, err := session.client.Get(session.apiURL(apiGetTradeOffers) + "?" + query.Encode())
 	if resp != nil {
 		defer func(Body io.ReadCloser) {
 			err := Body.Close()
@@ -263,18 +350,27 @@ func (session *Session) GetTradeOffers(filter uint32, timeCutOff time.Time) (*Tr
 		return nil, err
 	}
 	if resp == nil {
-		return nil, errors.New("invalid response")
+		return nil, ErrInvalidResponse
 	}
 	var response APIResponse
-	if err = json.NewDecoder(resp.Body).Decode(&response); err != nil {
+	if err = session.readJSON(resp, &response); err != nil {
 		return nil, err
 	}
 
+	if response.Inner == nil {
+		return nil, ErrInvalidResponse
+	}
+
+	if session.descriptions != nil {
+		session.descriptions.Add(response.Inner.Descriptions)
+		response.Inner.cache = session.descriptions
+	}
+
 	return response.Inner, nil
 }
 
 func (session *Session) GetMyTradeToken() (string, error) {
-	resp, err := session.client.Get("https://steamcommunity.com/my/tradeoffers/privacy")
+	resp, err := session.client.Get(session.CommunityBaseURL() + "/my/tradeoffers/privacy")
 	if resp != nil {
 		defer func(Body io.ReadCloser) {
 			err := Body.Close()
@@ -288,23 +384,18 @@ func (session *Session) GetMyTradeToken() (string, error) {
 		return "", err
 	}
 	if resp == nil {
-		return "", errors.New("invalid response")
+		return "", ErrInvalidResponse
 	}
 	if resp.StatusCode != http.StatusOK {
 		return "", fmt.Errorf("http error: %d", resp.StatusCode)
 	}
 
-	body, err := io.ReadAll(resp.Body)
+	body, err := io.ReadAll(session.limitBody(resp))
 	if err != nil {
 		return "", err
 	}
 
-	m := offerInfoExp.FindStringSubmatch(string(body))
-	if m == nil || len(m) != 2 {
-		return "", ErrCannotFindOfferInfo
-	}
-
-	return m[1], nil
+	return parseTradeToken(body)
 }
 
 type EscrowSteamGuardInfo struct {
@@ -314,14 +405,14 @@ type EscrowSteamGuardInfo struct {
 }
 
 func (session *Session) GetEscrowGuardInfo(sid SteamID, token string) (*EscrowSteamGuardInfo, error) {
-	return session.GetEscrow("https://steamcommunity.com/tradeoffer/new/?" + url.Values{
+	return session.GetEscrow(session.CommunityBaseURL() + "/tradeoffer/new/?" + url.Values{
 		"partner": {strconv.FormatUint(uint64(sid.GetAccountID()), 10)},
 		"token":   {token},
 	}.Encode())
 }
 
 func (session *Session) GetEscrowGuardInfoForTrade(offerID uint64) (*EscrowSteamGuardInfo, error) {
-	return session.GetEscrow("https://steamcommunity.com/tradeoffer/" + strconv.FormatUint(offerID, 10))
+	return session.GetEscrow(session.CommunityBaseURL() + "/tradeoffer/" + strconv.FormatUint(offerID, 10))
 }
 
 func (session *Session) GetEscrow(url string) (*EscrowSteamGuardInfo, error) {
@@ -339,44 +430,114 @@ func (session *Session) GetEscrow(url string) (*EscrowSteamGuardInfo, error) {
 		return nil, err
 	}
 	if resp == nil {
-		return nil, errors.New("invalid response")
+		return nil, ErrInvalidResponse
 	}
 	if resp.StatusCode != http.StatusOK {
 		return nil, fmt.Errorf("http error: %d", resp.StatusCode)
 	}
 
-	body, err := io.ReadAll(resp.Body)
+	body, err := io.ReadAll(session.limitBody(resp))
+	if err != nil {
+		return nil, err
+	}
+
+	return parseEscrow(body)
+}
+
+type TradeHoldDurations struct {
+	MyEscrow    time.Duration
+	TheirEscrow time.Duration
+	BothEscrow  time.Duration
+}
+
+// GetTradeHoldDurations also works as a cheap check of the partner trade
+// token, Steam refuses to answer when it does not match.
+func (session *Session) GetTradeHoldDurations(sid SteamID, token string) (*TradeHoldDurations, error) {
+	params := session.authorize(url.Values{
+		"steamid_target": {sid.ToString()},
+	})
+	if len(token) != 0 {
+		params.Set("trade_offer_access_token", token)
+	}
+
+	resp, err := session.client.Get(session.apiURL(apiGetTradeHoldDurations) + "?" + params.Encode())
+	if resp != nil {
+		defer func(Body io.ReadCloser) {
+			err := Body.Close()
+			if err != nil {
+				return
+			}
+		}(resp.Body)
+	}
+
 	if err != nil {
 		return nil, err
 	}
 
-	var my int64
-	var them int64
-	var errMsg string
+	switch result := resp.Header.Get("x-eresult"); result {
+	case "1":
+	case "8", "15":
+		return nil, ErrInvalidTradeToken
+	default:
+		session.stats.addError(result)
+		return nil, fmt.Errorf("cannot get trade hold durations: %s", result)
+	}
 
-	m := myEscrowExp.FindStringSubmatch(string(body))
-	if len(m) == 2 {
-		my, _ = strconv.ParseInt(m[1], 10, 32)
+	type Escrow struct {
+		Seconds int64 `json:"escrow_end_duration_seconds"`
 	}
 
-	m = themEscrowExp.FindStringSubmatch(string(body))
-	if len(m) == 2 {
-		them, _ = strconv.ParseInt(m[1], 10, 32)
+	type Durations struct {
+		My    Escrow `json:"my_escrow"`
+		Their Escrow `json:"their_escrow"`
+		Both  Escrow `json:"both_escrow"`
 	}
 
-	m = errorMsgExp.FindStringSubmatch(string(body))
-	if len(m) == 2 {
-		errMsg = m[1]
+	type Response struct {
+		Inner Durations `json:"response"`
 	}
 
-	return &EscrowSteamGuardInfo{
-		MyDays:   my,
-		ThemDays: them,
-		ErrorMsg: errMsg,
+	var response Response
+	if err = session.readJSON(resp, &response); err != nil {
+		return nil, err
+	}
+
+	return &TradeHoldDurations{
+		MyEscrow:    time.Duration(response.Inner.My.Seconds) * time.Second,
+		TheirEscrow: time.Duration(response.Inner.Their.Seconds) * time.Second,
+		BothEscrow:  time.Duration(response.Inner.Both.Seconds) * time.Second,
 	}, nil
 }
 
+// SetTradeTokenPreflight makes SendTradeOffer validate the partner token
+// with GetTradeHoldDurations before sending anything.
+func (session *Session) SetTradeTokenPreflight(enabled bool) {
+	session.tokenPreflight = enabled
+}
+
 func (session *Session) SendTradeOffer(offer *TradeOffer, sid SteamID, token string) error {
+	if err := session.checkWritable(); err != nil {
+		return err
+	}
+
+	if session.tokenPreflight && len(token) != 0 {
+		if _, err := session.GetTradeHoldDurations(sid, token); err != nil {
+			return err
+		}
+	}
+
+	if session.offerDedup {
+		existing, err := session.FindExistingOffer(sid, offer.SendItems, offer.RecvItems)
+		if err != nil {
+			return err
+		}
+
+		if existing != nil {
+			offer.ID = existing.ID
+			return ErrDuplicateOffer
+		}
+	}
+
 	content := map[string]interface{}{
 		"newversion": true,
 		"version":    3,
@@ -399,7 +560,7 @@ func (session *Session) SendTradeOffer(offer *TradeOffer, sid SteamID, token str
 
 	req, err := http.NewRequest(
 		http.MethodPost,
-		"https://steamcommunity.com/tradeoffer/new/send",
+		session.CommunityBaseURL()+"/tradeoffer/new/send",
 		strings.NewReader(url.Values{
 			"sessionid":                 {session.sessionID},
 			"serverid":                  {"1"},
@@ -412,7 +573,7 @@ func (session *Session) SendTradeOffer(offer *TradeOffer, sid SteamID, token str
 	if err != nil {
 		return err
 	}
-	req.Header.Add("Referer", "https://steamcommunity.com/tradeoffer/new/?"+url.Values{
+	req.Header.Add("Referer", session.CommunityBaseURL()+"/tradeoffer/new/?"+url.Values{
 		"partner": {strconv.FormatUint(uint64(sid.GetAccountID()), 10)},
 		"token":   {token},
 	}.Encode())
@@ -432,7 +593,7 @@ func (session *Session) SendTradeOffer(offer *TradeOffer, sid SteamID, token str
 		return err
 	}
 	if resp == nil {
-		return errors.New("invalid response")
+		return ErrInvalidResponse
 	}
 	type Response struct {
 		ErrorMessage               string `json:"strError"`
@@ -443,18 +604,20 @@ func (session *Session) SendTradeOffer(offer *TradeOffer, sid SteamID, token str
 	}
 
 	var response Response
-	if err = json.NewDecoder(resp.Body).Decode(&response); err != nil {
+	if err = session.readJSON(resp, &response); err != nil {
 		return err
 	}
 
 	if len(response.ErrorMessage) != 0 {
-		return errors.New(response.ErrorMessage)
+		return newTradeError(response.ErrorMessage)
 	}
 
 	if response.ID == 0 {
 		return errors.New("no OfferID included")
 	}
 
+	session.stats.add(statOffersSent)
+
 	offer.ID = response.ID
 	offer.Created = time.Now().Unix()
 	offer.Updated = time.Now().Unix()
@@ -475,7 +638,7 @@ func (session *Session) SendTradeOffer(offer *TradeOffer, sid SteamID, token str
 }
 
 func (session *Session) GetTradeReceivedItems(receiptID uint64) ([]*InventoryItem, error) {
-	resp, err := session.client.Get(fmt.Sprintf("https://steamcommunity.com/trade/%d/receipt", receiptID))
+	resp, err := session.client.Get(session.CommunityBaseURL() + fmt.Sprintf("/trade/%d/receipt", receiptID))
 	if resp != nil {
 		defer func(Body io.ReadCloser) {
 			err := Body.Close()
@@ -489,40 +652,28 @@ func (session *Session) GetTradeReceivedItems(receiptID uint64) ([]*InventoryIte
 		return nil, err
 	}
 	if resp == nil {
-		return nil, errors.New("invalid response")
+		return nil, ErrInvalidResponse
 	}
 	if resp.StatusCode != http.StatusOK {
 		return nil, fmt.Errorf("http error: %d", resp.StatusCode)
 	}
 
-	body, err := io.ReadAll(resp.Body)
+	body, err := io.ReadAll(session.limitBody(resp))
 	if err != nil {
 		return nil, err
 	}
 
-	m := receiptExp.FindAllSubmatch(body, -1)
-	if m == nil {
-		return nil, ErrReceiptMatch
-	}
-
-	items := make([]*InventoryItem, len(m))
-	for k := range m {
-		item := &InventoryItem{}
-		if err = json.Unmarshal(m[k][1], item); err != nil {
-			return nil, err
-		}
-
-		items[k] = item
-	}
-
-	return items, nil
+	return parseReceipt(body)
 }
 
 func (session *Session) DeclineTradeOffer(id uint64) error {
-	resp, err := session.client.PostForm(apiDeclineTradeOffer, url.Values{
-		"key":          {session.apiKey},
+	if err := session.checkWritable(); err != nil {
+		return err
+	}
+
+	resp, err := session.client.PostForm(session.apiURL(apiDeclineTradeOffer), session.authorize(url.Values{
 		"tradeofferid": {strconv.FormatUint(id, 10)},
-	})
+	}))
 	if resp != nil {
 		defer func(Body io.ReadCloser) {
 			err := Body.Close()
@@ -536,21 +687,26 @@ func (session *Session) DeclineTradeOffer(id uint64) error {
 		return err
 	}
 	if resp == nil {
-		return errors.New("invalid response")
+		return ErrInvalidResponse
 	}
 	result := resp.Header.Get("x-eresult")
 	if result != "1" {
+		session.stats.addError(result)
 		return fmt.Errorf("cannot decline trade: %s", result)
 	}
 
+	session.stats.add(statOffersDeclined)
 	return nil
 }
 
 func (session *Session) CancelTradeOffer(id uint64) error {
-	resp, err := session.client.PostForm(apiCancelTradeOffer, url.Values{
-		"key":          {session.apiKey},
+	if err := session.checkWritable(); err != nil {
+		return err
+	}
+
+	resp, err := session.client.PostForm(session.apiURL(apiCancelTradeOffer), session.authorize(url.Values{
 		"tradeofferid": {strconv.FormatUint(id, 10)},
-	})
+	}))
 	if resp != nil {
 		defer func(Body io.ReadCloser) {
 			err := Body.Close()
@@ -564,19 +720,38 @@ func (session *Session) CancelTradeOffer(id uint64) error {
 		return err
 	}
 	if resp == nil {
-		return errors.New("invalid response")
+		return ErrInvalidResponse
 	}
 	result := resp.Header.Get("x-eresult")
 	if result != "1" {
+		session.stats.addError(result)
 		return fmt.Errorf("cannot cancel trade: %s", result)
 	}
 
+	session.stats.add(statOffersCanceled)
 	return nil
 }
 
-func (session *Session) AcceptTradeOffer(id uint64) error {
+// AcceptTradeOfferResponse tells what has to happen next: fetch the received
+// items with TradeID, or confirm the trade on the mobile app / by email.
+type AcceptTradeOfferResponse struct {
+	TradeID                    uint64 `json:"tradeid,string"`
+	MobileConfirmationRequired bool   `json:"needs_mobile_confirmation"`
+	EmailConfirmationRequired  bool   `json:"needs_email_confirmation"`
+	EmailDomain                string `json:"email_domain"`
+}
+
+func (session *Session) AcceptTradeOffer(id uint64) (*AcceptTradeOfferResponse, error) {
+	if err := session.checkWritable(); err != nil {
+		return nil, err
+	}
+
+	if err := session.checkGivePolicy(id); err != nil {
+		return nil, err
+	}
+
 	tid := strconv.FormatUint(id, 10)
-	postURL := fmt.Sprintf("https://steamcommunity.com/tradeoffer/%s/", tid)
+	postURL := session.CommunityBaseURL() + fmt.Sprintf("/tradeoffer/%s/", tid)
 	data := strings.NewReader(url.Values{
 		"sessionid":    {session.sessionID},
 		"serverid":     {"1"},
@@ -584,7 +759,7 @@ func (session *Session) AcceptTradeOffer(id uint64) error {
 	}.Encode())
 	req, err := http.NewRequest(http.MethodPost, postURL+"accept", data)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	req.Header.Add("Referer", postURL)
@@ -601,29 +776,38 @@ func (session *Session) AcceptTradeOffer(id uint64) error {
 	}
 
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if resp == nil {
-		return errors.New("invalid response")
-	}
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("http error: %d", resp.StatusCode)
+		return nil, ErrInvalidResponse
 	}
 
 	type Response struct {
+		AcceptTradeOfferResponse
 		ErrorMessage string `json:"strError"`
 	}
 
+	// Steam answers errors with a 500 and a strError, decode before
+	// looking at the status code.
 	var response Response
-	if err = json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return err
+	if err = session.readJSON(resp, &response); err != nil {
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("http error: %d", resp.StatusCode)
+		}
+		return nil, err
 	}
 
 	if len(response.ErrorMessage) != 0 {
-		return errors.New(response.ErrorMessage)
+		return nil, newTradeError(response.ErrorMessage)
 	}
 
-	return nil
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("http error: %d", resp.StatusCode)
+	}
+
+	session.stats.add(statOffersAccepted)
+	session.InvalidateInventory(session.oauth.SteamID)
+	return &response.AcceptTradeOfferResponse, nil
 }
 
 func (offer *TradeOffer) Send(session *Session, sid SteamID, token string) error {
@@ -631,7 +815,19 @@ func (offer *TradeOffer) Send(session *Session, sid SteamID, token string) error
 }
 
 func (offer *TradeOffer) Accept(session *Session) error {
-	return session.AcceptTradeOffer(offer.ID)
+	response, err := session.AcceptTradeOffer(offer.ID)
+	if err != nil {
+		return err
+	}
+
+	offer.ReceiptID = response.TradeID
+	if response.MobileConfirmationRequired {
+		offer.ConfirmationMethod = TradeConfirmationMobileApp
+	} else if response.EmailConfirmationRequired {
+		offer.ConfirmationMethod = TradeConfirmationEmail
+	}
+
+	return nil
 }
 
 func (offer *TradeOffer) Cancel(session *Session) error {
@@ -0,0 +1,148 @@
+package steam
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// TradeState is the state of a trade offer, one of the TradeStateXxx
+// constants.
+type TradeState uint8
+
+// ConfirmationMethod is how a trade offer awaits confirmation, one of the
+// TradeConfirmationXxx constants.
+type ConfirmationMethod uint8
+
+// TradeFilter names the TradeFilterXxx bits passed to GetTradeOffers.
+type TradeFilter uint32
+
+var confirmationMethodStrings = map[ConfirmationMethod]string{
+	TradeConfirmationNone:      "None",
+	TradeConfirmationEmail:     "Email",
+	TradeConfirmationMobileApp: "MobileApp",
+	TradeConfirmationMobile:    "Mobile",
+}
+
+var tradeFilterStrings = []struct {
+	filter TradeFilter
+	name   string
+}{
+	{TradeFilterSentOffers, "SentOffers"},
+	{TradeFilterRecvOffers, "RecvOffers"},
+	{TradeFilterActiveOnly, "ActiveOnly"},
+	{TradeFilterHistoricalOnly, "HistoricalOnly"},
+	{TradeFilterItemDescriptions, "ItemDescriptions"},
+}
+
+// String returns the name of the state in TradeStateNames.
+func (state TradeState) String() string {
+	if name, ok := TradeStateNames[state]; ok {
+		return name
+	}
+
+	return "Unknown(" + strconv.FormatUint(uint64(state), 10) + ")"
+}
+
+// MarshalText writes the name returned by String, or the number of states
+// without one, so JSON and text encoders carry names.
+func (state TradeState) MarshalText() ([]byte, error) {
+	return marshalEnum(state, TradeStateNames), nil
+}
+
+// UnmarshalJSON accepts the numbers Steam sends as well as the names
+// returned by String.
+func (state *TradeState) UnmarshalJSON(b []byte) error {
+	v, err := unmarshalEnum(b, TradeStateNames)
+	if err != nil {
+		return fmt.Errorf("trade state: %w", err)
+	}
+
+	*state = v
+	return nil
+}
+
+func (method ConfirmationMethod) String() string {
+	if name, ok := confirmationMethodStrings[method]; ok {
+		return name
+	}
+
+	return "Unknown(" + strconv.FormatUint(uint64(method), 10) + ")"
+}
+
+// MarshalText writes the name returned by String, or the number of methods
+// this package does not know.
+func (method ConfirmationMethod) MarshalText() ([]byte, error) {
+	return marshalEnum(method, confirmationMethodStrings), nil
+}
+
+// UnmarshalJSON accepts the numbers Steam sends as well as the names
+// returned by String.
+func (method *ConfirmationMethod) UnmarshalJSON(b []byte) error {
+	v, err := unmarshalEnum(b, confirmationMethodStrings)
+	if err != nil {
+		return fmt.Errorf("confirmation method: %w", err)
+	}
+
+	*method = v
+	return nil
+}
+
+// String joins the names of the set bits with "|", e.g.
+// "SentOffers|ActiveOnly".
+func (filter TradeFilter) String() string {
+	if filter == TradeFilterNone {
+		return "None"
+	}
+
+	var names []string
+	for _, f := range tradeFilterStrings {
+		if filter&f.filter == f.filter {
+			names = append(names, f.name)
+			filter &^= f.filter
+		}
+	}
+
+	if filter != 0 {
+		names = append(names, "0x"+strconv.FormatUint(uint64(filter), 16))
+	}
+
+	return strings.Join(names, "|")
+}
+
+// marshalEnum returns the name of v in names, its number without one.
+func marshalEnum[E ~uint8](v E, names map[E]string) []byte {
+	if name, ok := names[v]; ok {
+		return []byte(name)
+	}
+
+	return strconv.AppendUint(nil, uint64(v), 10)
+}
+
+// unmarshalEnum decodes a JSON number, or a string holding either a
+// number or one of names, into the value it stands for.
+func unmarshalEnum[E ~uint8](b []byte, names map[E]string) (E, error) {
+	var v uint8
+	if err := json.Unmarshal(b, &v); err == nil {
+		return E(v), nil
+	}
+
+	var name string
+	if err := json.Unmarshal(b, &name); err != nil {
+		return 0, err
+	}
+
+	for v, n := range names {
+		if strings.EqualFold(n, name) {
+			return v, nil
+		}
+	}
+
+	n, err := strconv.ParseUint(name, 10, 8)
+	if err != nil {
+		return 0, fmt.Errorf("unknown value %q", name)
+	}
+
+	return E(n), nil
+}
@@ -0,0 +1,83 @@
+package steam
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestTradeStateJSON(t *testing.T) {
+	for state := TradeState(0); state <= TradeStateInEscrow+1; state++ {
+		b, err := json.Marshal(state)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var got TradeState
+		if err = json.Unmarshal(b, &got); err != nil {
+			t.Fatalf("unmarshal %s: %v", b, err)
+		}
+
+		if got != state {
+			t.Errorf("%v went through %s as %v", state, b, got)
+		}
+	}
+
+	b, _ := json.Marshal(TradeStateAccepted)
+	if string(b) != `"Accepted"` {
+		t.Errorf("Accepted marshals to %s", b)
+	}
+
+	// Steam sends numbers, old stored offers too.
+	var offer TradeOffer
+	if err := json.Unmarshal([]byte(`{"trade_offer_state":3,"confirmation_method":2}`), &offer); err != nil || offer.State != TradeStateAccepted {
+		t.Errorf("got %v, %v, want Accepted", offer.State, err)
+	}
+
+	b, err := json.Marshal(&offer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(b), `"trade_offer_state":"Accepted"`) || !strings.Contains(string(b), `"confirmation_method":"MobileApp"`) {
+		t.Errorf("offer marshals to %s, want the names", b)
+	}
+}
+
+func TestConfirmationMethodJSON(t *testing.T) {
+	for method := ConfirmationMethod(0); method <= TradeConfirmationMobile+1; method++ {
+		b, err := json.Marshal(method)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var got ConfirmationMethod
+		if err = json.Unmarshal(b, &got); err != nil {
+			t.Fatalf("unmarshal %s: %v", b, err)
+		}
+
+		if got != method {
+			t.Errorf("%v went through %s as %v", method, b, got)
+		}
+	}
+}
+
+func TestTradeStateString(t *testing.T) {
+	tests := []struct {
+		value fmt.Stringer
+		want  string
+	}{
+		{TradeStateInEscrow, "InEscrow"},
+		{TradeState(200), "Unknown(200)"},
+		{TradeConfirmationMobileApp, "MobileApp"},
+		{TradeFilterNone, "None"},
+		{TradeFilterSentOffers | TradeFilterActiveOnly, "SentOffers|ActiveOnly"},
+		{TradeFilterRecvOffers | 1<<7, "RecvOffers|0x80"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.value.String(); got != tt.want {
+			t.Errorf("got %q, want %q", got, tt.want)
+		}
+	}
+}
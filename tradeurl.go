@@ -0,0 +1,160 @@
+package steam
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const (
+	maxTradeURLRedirects = 5
+
+	newTradeURLURL = "/profiles/%d/tradeoffers/newtradeurl"
+)
+
+var (
+	ErrInvalidTradeURL      = errors.New("invalid trade offer url")
+	ErrTooManyTradeRedirect = errors.New("too many redirects while resolving trade offer url")
+	ErrCannotCreateToken    = errors.New("unable to create a new trade token")
+
+	// tradeURLHosts are the only hosts a trade URL is allowed to go through.
+	tradeURLHosts = map[string]bool{
+		"steamcommunity.com":     true,
+		"www.steamcommunity.com": true,
+		"s.team":                 true,
+	}
+)
+
+// ParseTradeOfferURL extracts the partner and token from a trade URL.  Besides
+// the regular https://steamcommunity.com/tradeoffer/new/?partner=..&token=..
+// form it accepts steam://openurl/ deep links and s.team short links, the
+// latter being resolved by following at most a few redirects, none of which
+// may leave Steam.
+func ParseTradeOfferURL(rawURL string) (SteamID, string, error) {
+	return parseTradeOfferURL(http.DefaultClient, rawURL)
+}
+
+// ParseTradeOfferURL is like the package level function but resolves short
+// links with the session client.
+func (session *Session) ParseTradeOfferURL(rawURL string) (SteamID, string, error) {
+	return parseTradeOfferURL(session.client, rawURL)
+}
+
+func parseTradeOfferURL(client *http.Client, rawURL string) (SteamID, string, error) {
+	u, err := normalizeTradeURL(rawURL)
+	if err != nil {
+		return 0, "", err
+	}
+
+	for hops := 0; u.Host == "s.team"; hops++ {
+		if hops == maxTradeURLRedirects {
+			return 0, "", ErrTooManyTradeRedirect
+		}
+
+		if u, err = nextTradeURL(client, u); err != nil {
+			return 0, "", err
+		}
+	}
+
+	if !strings.HasPrefix(u.Path, "/tradeoffer/new") {
+		return 0, "", ErrInvalidTradeURL
+	}
+
+	q := u.Query()
+	accountID, err := strconv.ParseUint(q.Get("partner"), 10, 32)
+	if err != nil {
+		return 0, "", ErrInvalidTradeURL
+	}
+
+	var sid SteamID
+	sid.ParseDefaults(uint32(accountID))
+	return sid, q.Get("token"), nil
+}
+
+func normalizeTradeURL(rawURL string) (*url.URL, error) {
+	rawURL = strings.TrimSpace(rawURL)
+	rawURL = strings.TrimPrefix(rawURL, "steam://openurl/")
+	if !strings.Contains(rawURL, "://") {
+		rawURL = "https://" + rawURL
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, ErrInvalidTradeURL
+	}
+
+	u.Host = strings.ToLower(u.Host)
+	if !tradeURLHosts[u.Host] {
+		return nil, ErrInvalidTradeURL
+	}
+
+	u.Scheme = "https"
+	return u, nil
+}
+
+func nextTradeURL(client *http.Client, u *url.URL) (*url.URL, error) {
+	noRedirect := *client
+	noRedirect.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+
+	resp, err := noRedirect.Get(u.String())
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+
+	location, err := resp.Location()
+	if err != nil {
+		return nil, ErrInvalidTradeURL
+	}
+
+	return normalizeTradeURL(location.String())
+}
+
+// TradeURL builds the trade URL others send offers to sid with.
+func TradeURL(sid SteamID, token string) string {
+	return "https://steamcommunity.com/tradeoffer/new/?" + url.Values{
+		"partner": {strconv.FormatUint(uint64(sid.GetAccountID()), 10)},
+		"token":   {token},
+	}.Encode()
+}
+
+// GetTradeURL returns the trade URL of the account.
+func (session *Session) GetTradeURL() (string, error) {
+	token, err := session.GetMyTradeToken()
+	if err != nil {
+		return "", err
+	}
+
+	return TradeURL(session.oauth.SteamID, token), nil
+}
+
+// CreateNewTradeToken replaces the trade token of the account and returns
+// the new one, the trade URLs handed out so far stop working.
+func (session *Session) CreateNewTradeToken() (string, error) {
+	if err := session.checkWritable(); err != nil {
+		return "", err
+	}
+
+	var token string
+	if err := session.postStoreForm(session.CommunityBaseURL()+fmt.Sprintf(newTradeURLURL, session.oauth.SteamID), url.Values{}, &token); err != nil {
+		return "", err
+	}
+
+	if len(token) == 0 {
+		return "", ErrCannotCreateToken
+	}
+
+	return token, nil
+}
+
+// RevokeTradeToken invalidates the current trade URL.  Steam has no way to
+// go without one, so this is CreateNewTradeToken ignoring the new token.
+func (session *Session) RevokeTradeToken() error {
+	_, err := session.CreateNewTradeToken()
+	return err
+}
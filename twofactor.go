@@ -1,7 +1,6 @@
 package steam
 
 import (
-	"encoding/json"
 	"errors"
 	"io"
 	"net/url"
@@ -27,15 +26,19 @@ type FinalizeTwoFactorInfo struct {
 }
 
 const (
-	enableTwoFactorURL   = APIBaseUrl + "/ITwoFactorService/AddAuthenticator/v1/"
-	finalizeTwoFactorURL = APIBaseUrl + "/ITwoFactorService/FinalizeAddAuthenticator/v1/"
-	disableTwoFactorURL  = APIBaseUrl + "/ITwoFactorService/RemoveAuthenticator/v1/"
+	enableTwoFactorURL   = "/ITwoFactorService/AddAuthenticator/v1/"
+	finalizeTwoFactorURL = "/ITwoFactorService/FinalizeAddAuthenticator/v1/"
+	disableTwoFactorURL  = "/ITwoFactorService/RemoveAuthenticator/v1/"
 )
 
 var ErrCannotDisable = errors.New("unable to process disable two factor request")
 
 func (session *Session) EnableTwoFactor() (*TwoFactorInfo, error) {
-	resp, err := session.client.PostForm(enableTwoFactorURL, url.Values{
+	if err := session.checkWritable(); err != nil {
+		return nil, err
+	}
+
+	resp, err := session.client.PostForm(session.APIBaseURL()+enableTwoFactorURL, url.Values{
 		"steamid":            {session.oauth.SteamID.ToString()},
 		"access_token":       {session.oauth.Token},
 		"authenticator_time": {strconv.FormatInt(time.Now().Unix(), 10)},
@@ -61,15 +64,23 @@ func (session *Session) EnableTwoFactor() (*TwoFactorInfo, error) {
 	}
 
 	var response Response
-	if err = json.NewDecoder(resp.Body).Decode(&response); err != nil {
+	if err = session.readJSON(resp, &response); err != nil {
 		return nil, err
 	}
 
+	if response.Inner == nil {
+		return nil, ErrInvalidResponse
+	}
+
 	return response.Inner, nil
 }
 
 func (session *Session) FinalizeTwoFactor(authCode, mobileCode string) (*FinalizeTwoFactorInfo, error) {
-	resp, err := session.client.PostForm(finalizeTwoFactorURL, url.Values{
+	if err := session.checkWritable(); err != nil {
+		return nil, err
+	}
+
+	resp, err := session.client.PostForm(session.APIBaseURL()+finalizeTwoFactorURL, url.Values{
 		"steamid":            {session.oauth.SteamID.ToString()},
 		"access_token":       {session.oauth.Token},
 		"authenticator_time": {strconv.FormatInt(time.Now().Unix(), 10)},
@@ -94,15 +105,23 @@ func (session *Session) FinalizeTwoFactor(authCode, mobileCode string) (*Finaliz
 	}
 
 	var response Response
-	if err = json.NewDecoder(resp.Body).Decode(&response); err != nil {
+	if err = session.readJSON(resp, &response); err != nil {
 		return nil, err
 	}
 
+	if response.Inner == nil {
+		return nil, ErrInvalidResponse
+	}
+
 	return response.Inner, nil
 }
 
 func (session *Session) DisableTwoFactor(revocationCode string) error {
-	resp, err := session.client.PostForm(disableTwoFactorURL, url.Values{
+	if err := session.checkWritable(); err != nil {
+		return err
+	}
+
+	resp, err := session.client.PostForm(session.APIBaseURL()+disableTwoFactorURL, url.Values{
 		"steamid":           {session.oauth.SteamID.ToString()},
 		"access_token":      {session.oauth.Token},
 		"revocation_code":   {revocationCode},
@@ -129,10 +148,14 @@ func (session *Session) DisableTwoFactor(revocationCode string) error {
 	}
 
 	var response Response
-	if err = json.NewDecoder(resp.Body).Decode(&response); err != nil {
+	if err = session.readJSON(resp, &response); err != nil {
 		return err
 	}
 
+	if response.Inner == nil {
+		return ErrInvalidResponse
+	}
+
 	if !response.Inner.Success {
 		return ErrCannotDisable
 	}
@@ -0,0 +1,204 @@
+package steam
+
+import (
+	"encoding/json"
+	"errors"
+	"net/url"
+	"strconv"
+)
+
+const (
+	apiGetPlayerAchievements       = "/ISteamUserStats/GetPlayerAchievements/v1/?"
+	apiGetUserStatsForGame         = "/ISteamUserStats/GetUserStatsForGame/v2/?"
+	apiGetGlobalAchievementPercent = "/ISteamUserStats/GetGlobalAchievementPercentagesForApp/v2/?"
+	apiGetNumberOfCurrentPlayers   = "/ISteamUserStats/GetNumberOfCurrentPlayers/v1/?"
+)
+
+var ErrCannotLoadStats = errors.New("unable to load user stats")
+
+type PlayerAchievement struct {
+	APIName     string
+	Achieved    bool
+	UnlockTime  int64  // unix time, 0 when not achieved
+	Name        string // localized, with a language only
+	Description string // localized, with a language only
+}
+
+type PlayerAchievements struct {
+	SteamID      SteamID
+	GameName     string
+	Achievements []*PlayerAchievement
+}
+
+type GameStat struct {
+	Name  string  `json:"name"`
+	Value float64 `json:"value"`
+}
+
+type UserStatsForGame struct {
+	SteamID      SteamID
+	GameName     string
+	Stats        []*GameStat
+	Achievements []string // API names of the achievements the user has
+}
+
+type AchievementPercentage struct {
+	Name    string
+	Percent float64
+}
+
+// GetPlayerAchievements lists the achievements of appID and whether sid has
+// them, lang ("english") adds their names and descriptions.  The profile
+// game details have to be public.
+func (session *Session) GetPlayerAchievements(sid SteamID, appID uint32, lang string) (*PlayerAchievements, error) {
+	params := session.authorize(url.Values{
+		"steamid": {sid.ToString()},
+		"appid":   {strconv.FormatUint(uint64(appID), 10)},
+	})
+	if len(lang) != 0 {
+		params.Set("l", lang)
+	}
+
+	var response struct {
+		Stats *struct {
+			SteamID      SteamID `json:"steamID,string"`
+			GameName     string  `json:"gameName"`
+			Achievements []struct {
+				APIName     string `json:"apiname"`
+				Achieved    int    `json:"achieved"`
+				UnlockTime  int64  `json:"unlocktime"`
+				Name        string `json:"name"`
+				Description string `json:"description"`
+			} `json:"achievements"`
+			Success bool   `json:"success"`
+			Error   string `json:"error"`
+		} `json:"playerstats"`
+	}
+	if err := session.getAPI(session.APIBaseURL()+apiGetPlayerAchievements, params, &response); err != nil {
+		return nil, err
+	}
+
+	if response.Stats == nil {
+		return nil, ErrInvalidResponse
+	}
+	if !response.Stats.Success {
+		if len(response.Stats.Error) != 0 {
+			return nil, errors.New(response.Stats.Error)
+		}
+
+		return nil, ErrCannotLoadStats
+	}
+
+	achievements := &PlayerAchievements{
+		SteamID:      response.Stats.SteamID,
+		GameName:     response.Stats.GameName,
+		Achievements: make([]*PlayerAchievement, 0, len(response.Stats.Achievements)),
+	}
+	for _, a := range response.Stats.Achievements {
+		achievements.Achievements = append(achievements.Achievements, &PlayerAchievement{
+			APIName:     a.APIName,
+			Achieved:    a.Achieved != 0,
+			UnlockTime:  a.UnlockTime,
+			Name:        a.Name,
+			Description: a.Description,
+		})
+	}
+
+	return achievements, nil
+}
+
+// GetUserStatsForGame returns the game stats of sid and the names of the
+// achievements it has.
+func (session *Session) GetUserStatsForGame(sid SteamID, appID uint32) (*UserStatsForGame, error) {
+	var response struct {
+		Stats *struct {
+			SteamID      SteamID     `json:"steamID,string"`
+			GameName     string      `json:"gameName"`
+			Stats        []*GameStat `json:"stats"`
+			Achievements []struct {
+				Name     string `json:"name"`
+				Achieved int    `json:"achieved"`
+			} `json:"achievements"`
+		} `json:"playerstats"`
+	}
+	err := session.getAPI(session.APIBaseURL()+apiGetUserStatsForGame, session.authorize(url.Values{
+		"steamid": {sid.ToString()},
+		"appid":   {strconv.FormatUint(uint64(appID), 10)},
+	}), &response)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.Stats == nil {
+		return nil, ErrInvalidResponse
+	}
+
+	stats := &UserStatsForGame{
+		SteamID:  response.Stats.SteamID,
+		GameName: response.Stats.GameName,
+		Stats:    response.Stats.Stats,
+	}
+	for _, a := range response.Stats.Achievements {
+		if a.Achieved != 0 {
+			stats.Achievements = append(stats.Achievements, a.Name)
+		}
+	}
+
+	return stats, nil
+}
+
+// GetGlobalAchievementPercentages returns the share of players having each
+// achievement of appID.
+func (session *Session) GetGlobalAchievementPercentages(appID uint32) ([]*AchievementPercentage, error) {
+	var response struct {
+		Inner *struct {
+			Achievements []struct {
+				Name    string      `json:"name"`
+				Percent json.Number `json:"percent"` // number or string
+			} `json:"achievements"`
+		} `json:"achievementpercentages"`
+	}
+	err := session.getAPI(session.APIBaseURL()+apiGetGlobalAchievementPercent, url.Values{
+		"gameid": {strconv.FormatUint(uint64(appID), 10)},
+	}, &response)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.Inner == nil {
+		return nil, ErrInvalidResponse
+	}
+
+	percentages := make([]*AchievementPercentage, 0, len(response.Inner.Achievements))
+	for _, achievement := range response.Inner.Achievements {
+		percent, _ := achievement.Percent.Float64()
+		percentages = append(percentages, &AchievementPercentage{
+			Name:    achievement.Name,
+			Percent: percent,
+		})
+	}
+
+	return percentages, nil
+}
+
+// GetNumberOfCurrentPlayers returns how many players are in game.
+func (session *Session) GetNumberOfCurrentPlayers(appID uint32) (int, error) {
+	var response struct {
+		Inner *struct {
+			PlayerCount int `json:"player_count"`
+			Result      int `json:"result"`
+		} `json:"response"`
+	}
+	err := session.getAPI(session.APIBaseURL()+apiGetNumberOfCurrentPlayers, url.Values{
+		"appid": {strconv.FormatUint(uint64(appID), 10)},
+	}, &response)
+	if err != nil {
+		return 0, err
+	}
+
+	if response.Inner == nil || response.Inner.Result != 1 {
+		return 0, ErrInvalidResponse
+	}
+
+	return response.Inner.PlayerCount, nil
+}
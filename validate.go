@@ -0,0 +1,124 @@
+package steam
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// MaxClockSkew is how far the local clock may drift from Steam before
+// generated two-factor codes stop being accepted reliably.
+const MaxClockSkew = 15 * time.Second
+
+type ProbeResult struct {
+	OK     bool
+	Detail string
+	Err    error
+}
+
+// ReadinessReport is the outcome of Validate, Ready is only true when every
+// probe passed.
+type ReadinessReport struct {
+	Ready      bool
+	APIKey     ProbeResult
+	Cookies    ProbeResult
+	ClockSkew  ProbeResult
+	TradeToken ProbeResult
+}
+
+func (report *ReadinessReport) String() string {
+	probe := func(name string, p ProbeResult) string {
+		if p.OK {
+			return name + ": ok"
+		}
+		if p.Err != nil {
+			return name + ": " + p.Err.Error()
+		}
+		return name + ": " + p.Detail
+	}
+
+	return strings.Join([]string{
+		probe("api key", report.APIKey),
+		probe("cookies", report.Cookies),
+		probe("clock", report.ClockSkew),
+		probe("trade token", report.TradeToken),
+	}, ", ")
+}
+
+// Validate runs a few cheap probes (API key accepted, community cookies
+// valid, local clock close to Steam's, trade token readable) so a bot can
+// find out it is misconfigured before entering its main loop.  ctx is
+// checked between probes.
+func (session *Session) Validate(ctx context.Context) (*ReadinessReport, error) {
+	report := &ReadinessReport{}
+
+	probes := []func(){
+		func() { report.APIKey = session.probeAPIKey() },
+		func() { report.Cookies = session.probeCookies() },
+		func() { report.ClockSkew = probeClockSkew() },
+		func() { report.TradeToken = session.probeTradeToken() },
+	}
+
+	for _, probe := range probes {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+
+		probe()
+	}
+
+	report.Ready = report.APIKey.OK && report.Cookies.OK && report.ClockSkew.OK && report.TradeToken.OK
+	return report, nil
+}
+
+func (session *Session) probeAPIKey() ProbeResult {
+	if len(session.apiKey) == 0 {
+		return ProbeResult{Detail: "no API key set"}
+	}
+
+	resp, err := session.client.Get(session.apiURL(apiGetTradeOffersSummary) + "?key=" + session.apiKey)
+	if err != nil {
+		return ProbeResult{Err: err}
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return ProbeResult{Detail: fmt.Sprintf("http error: %d", resp.StatusCode)}
+	}
+
+	return ProbeResult{OK: true}
+}
+
+func (session *Session) probeCookies() ProbeResult {
+	profileURL, err := session.GetProfileURL()
+	if err != nil {
+		return ProbeResult{Err: err}
+	}
+
+	if strings.Contains(profileURL, "/login") {
+		return ProbeResult{Detail: "community cookies are not logged in"}
+	}
+
+	return ProbeResult{OK: true, Detail: profileURL}
+}
+
+func probeClockSkew() ProbeResult {
+	tip, err := GetTimeTip()
+	if err != nil {
+		return ProbeResult{Err: err}
+	}
+
+	skew := time.Duration(tip.Time-time.Now().Unix()) * time.Second
+	return ProbeResult{OK: skew <= MaxClockSkew && skew >= -MaxClockSkew, Detail: "skew " + skew.String()}
+}
+
+func (session *Session) probeTradeToken() ProbeResult {
+	token, err := session.GetMyTradeToken()
+	if err != nil {
+		return ProbeResult{Err: err}
+	}
+
+	return ProbeResult{OK: true, Detail: token}
+}
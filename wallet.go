@@ -0,0 +1,140 @@
+package steam
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/hiship/go-steam/currency"
+)
+
+var (
+	walletInfoExp = regexp.MustCompile(`var g_rgWalletInfo = ({.*?});`)
+
+	ErrCannotFindWallet = errors.New("unable to find wallet info, is the session logged in?")
+)
+
+// WalletBalance amounts are in cents of Currency, one of the Currency*
+// constants.
+type WalletBalance struct {
+	Currency       string
+	Country        string
+	Balance        uint64
+	DelayedBalance uint64 // pending funds that cannot be spent yet
+	MaxBalance     uint64
+}
+
+type walletInfo struct {
+	Success        int    `json:"success"`
+	Currency       uint32 `json:"wallet_currency"`
+	Country        string `json:"wallet_country"`
+	Balance        uint64 `json:"wallet_balance,string"`
+	DelayedBalance uint64 `json:"wallet_delayed_balance,string"`
+	MaxBalance     uint64 `json:"wallet_max_balance,string"`
+}
+
+// StoreAccountInfo is what the store account page shows about the
+// account, WalletBalance is formatted in the account currency.
+type StoreAccountInfo struct {
+	Email         string
+	Country       string
+	WalletBalance string
+	Balance       currency.Amount // WalletBalance in cents
+}
+
+func (session *Session) getPage(pageURL string) ([]byte, error) {
+	resp, err := session.client.Get(pageURL)
+	if resp != nil {
+		defer func(Body io.ReadCloser) {
+			err := Body.Close()
+			if err != nil {
+				return
+			}
+		}(resp.Body)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("http error: %d", resp.StatusCode)
+	}
+
+	return io.ReadAll(session.limitBody(resp))
+}
+
+// GetWalletBalance reads the wallet the market page uses for listings and
+// buy orders.
+func (session *Session) GetWalletBalance() (*WalletBalance, error) {
+	body, err := session.getPage(session.CommunityBaseURL() + "/market/")
+	if err != nil {
+		return nil, err
+	}
+
+	m := walletInfoExp.FindSubmatch(body)
+	if m == nil {
+		return nil, ErrCannotFindWallet
+	}
+
+	var info walletInfo
+	if err = json.Unmarshal(m[1], &info); err != nil {
+		return nil, err
+	}
+
+	if info.Success != 1 {
+		return nil, ErrCannotFindWallet
+	}
+
+	return &WalletBalance{
+		Currency:       currency.ID(info.Currency).Param(),
+		Country:        info.Country,
+		Balance:        info.Balance,
+		DelayedBalance: info.DelayedBalance,
+		MaxBalance:     info.MaxBalance,
+	}, nil
+}
+
+// GetStoreAccountInfo scrapes the store account page, PrepareForSteamStore
+// has to be called first.
+func (session *Session) GetStoreAccountInfo() (*StoreAccountInfo, error) {
+	body, err := session.getPage("https://store.steampowered.com/account/?l=english")
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(string(body)))
+	if err != nil {
+		return nil, err
+	}
+
+	info := &StoreAccountInfo{
+		WalletBalance: strings.TrimSpace(doc.Find("#header_wallet_balance").First().Text()),
+	}
+
+	doc.Find(".account_data_field").Each(func(i int, s *goquery.Selection) {
+		label := strings.ToLower(s.Prev().Text())
+		value := strings.TrimSpace(s.Text())
+
+		switch {
+		case strings.Contains(label, "email"):
+			info.Email = value
+		case strings.Contains(label, "country"):
+			info.Country = value
+		}
+	})
+
+	if len(info.WalletBalance) != 0 {
+		info.Balance, _, _ = currency.Parse(info.WalletBalance)
+	}
+
+	if info.Email == "" && info.WalletBalance == "" {
+		return nil, ErrInvalidResponse
+	}
+
+	return info, nil
+}
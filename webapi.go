@@ -2,18 +2,20 @@ package steam
 
 import (
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"net/url"
 	"regexp"
+	"time"
 )
 
 const (
 	APIBaseUrl = "https://api.steampowered.com"
 
-	apiKeyURL         = "https://steamcommunity.com/dev/apikey"
-	apiKeyRegisterURL = "https://steamcommunity.com/dev/registerkey"
-	apiKeyRevokeURL   = "https://steamcommunity.com/dev/revokekey"
+	apiKeyURL         = "/dev/apikey"
+	apiKeyRegisterURL = "/dev/registerkey"
+	apiKeyRevokeURL   = "/dev/revokekey"
 
 	accessDeniedPattern = "<h2>Access Denied</h2>"
 )
@@ -25,10 +27,25 @@ var (
 	ErrCannotRevokeKey   = errors.New("unable to revoke API key")
 	ErrAccessDenied      = errors.New("access is denied")
 	ErrKeyNotFound       = errors.New("key not found")
+
+	// ErrInvalidResponse is returned whenever Steam answers with something
+	// that does not have the expected shape (missing response object, no
+	// offer...), instead of dereferencing what is not there.
+	ErrInvalidResponse = errors.New("invalid response")
 )
 
 func (session *Session) parseKey(resp *http.Response) (string, error) {
-	body, err := io.ReadAll(resp.Body)
+	key, err := extractKey(resp)
+	if err != nil {
+		return "", err
+	}
+
+	session.apiKey = key
+	return key, nil
+}
+
+func extractKey(resp *http.Response) (string, error) {
+	body, err := io.ReadAll(limitBody(resp))
 	if err != nil {
 		return "", err
 	}
@@ -44,12 +61,16 @@ func (session *Session) parseKey(resp *http.Response) (string, error) {
 		return "", ErrKeyNotFound
 	}
 
-	session.apiKey = submatch[1]
 	return submatch[1], nil
 }
 
 func (session *Session) RegisterWebAPIKey(domain string) (string, error) {
-	resp, err := session.client.PostForm(apiKeyRegisterURL, url.Values{
+	if err := session.checkWritable(); err != nil {
+		return "", err
+	}
+
+	start := time.Now()
+	resp, err := session.client.PostForm(session.CommunityBaseURL()+apiKeyRegisterURL, url.Values{
 		"domain":       {domain},
 		"agreeToTerms": {"agreed"},
 		"sessionid":    {session.sessionID},
@@ -72,11 +93,16 @@ func (session *Session) RegisterWebAPIKey(domain string) (string, error) {
 		return "", ErrCannotRegisterKey
 	}
 
-	return session.parseKey(resp)
+	key, err := session.parseKey(resp)
+	if errors.Is(err, ErrKeyNotFound) && session.emailCodes != nil {
+		return session.registerKeyByEmail(start)
+	}
+
+	return key, err
 }
 
 func (session *Session) GetWebAPIKey() (string, error) {
-	resp, err := session.client.Get(apiKeyURL)
+	resp, err := session.client.Get(session.CommunityBaseURL() + apiKeyURL)
 	if resp != nil {
 		defer func(Body io.ReadCloser) {
 			err := Body.Close()
@@ -94,7 +120,11 @@ func (session *Session) GetWebAPIKey() (string, error) {
 }
 
 func (session *Session) RevokeWebAPIKey() error {
-	resp, err := session.client.PostForm(apiKeyRevokeURL, url.Values{
+	if err := session.checkWritable(); err != nil {
+		return err
+	}
+
+	resp, err := session.client.PostForm(session.CommunityBaseURL()+apiKeyRevokeURL, url.Values{
 		"Revoke":    {"Revoke My Steam Web API Key"},
 		"sessionid": {session.sessionID},
 	})
@@ -117,3 +147,57 @@ func (session *Session) RevokeWebAPIKey() error {
 
 	return nil
 }
+
+// getAPI calls a WebAPI method returning JSON and decodes its response
+// into inner.
+func (session *Session) getAPI(endpoint string, params url.Values, inner interface{}) error {
+	resp, err := session.client.Get(endpoint + params.Encode())
+	if resp != nil {
+		defer func(Body io.ReadCloser) {
+			err := Body.Close()
+			if err != nil {
+				return
+			}
+		}(resp.Body)
+	}
+
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("http error: %d", resp.StatusCode)
+	}
+
+	return session.readJSON(resp, inner)
+}
+
+// postAPI is getAPI for the methods taking a POST, failing with the
+// EResult Steam reports in x-eresult.  inner may be nil.
+func (session *Session) postAPI(endpoint string, params url.Values, inner interface{}) error {
+	resp, err := session.client.PostForm(endpoint, params)
+	if resp != nil {
+		defer func(Body io.ReadCloser) {
+			err := Body.Close()
+			if err != nil {
+				return
+			}
+		}(resp.Body)
+	}
+
+	if err != nil {
+		return err
+	}
+	if result := resp.Header.Get("x-eresult"); len(result) != 0 && result != "1" {
+		session.stats.addError(result)
+		return fmt.Errorf("eresult %s", result)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("http error: %d", resp.StatusCode)
+	}
+
+	if inner == nil {
+		return nil
+	}
+
+	return session.readJSON(resp, inner)
+}
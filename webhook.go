@@ -0,0 +1,155 @@
+package steam
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Webhook events.
+const (
+	WebhookOfferAccepted = "offer.accepted"
+	WebhookOfferDeclined = "offer.declined"
+	WebhookOfferEscrow   = "offer.escrow"
+	WebhookItemsReceived = "items.received"
+)
+
+// WebhookSignatureHeader carries the hex HMAC-SHA256 of the body, keyed
+// with Webhook.Secret, as "sha256=<hex>".
+const WebhookSignatureHeader = "X-Steam-Signature"
+
+// WebhookEvent is the JSON body POSTed by a Webhook.
+type WebhookEvent struct {
+	Event     string           `json:"event"`
+	OfferID   uint64           `json:"offer_id,string"`
+	Partner   SteamID          `json:"partner,string"`
+	State     string           `json:"state"`
+	OldState  string           `json:"old_state"`
+	TradeID   uint64           `json:"trade_id,string,omitempty"`
+	Items     []*InventoryItem `json:"items,omitempty"` // items.received only
+	Timestamp int64            `json:"timestamp"`
+}
+
+// Webhook POSTs offer changes to URL, see OfferWatcher.Webhook.  Only
+// acceptance, declines, escrow holds and the items then received are sent.
+type Webhook struct {
+	URL    string
+	Secret []byte       // signs the body when set
+	Client *http.Client // defaults to one timing out after webhookTimeout
+}
+
+// webhookTimeout bounds the requests of webhooks without a Client, a
+// receiver that stalls must not hold up the offer watcher for good.
+const webhookTimeout = 10 * time.Second
+
+var defaultWebhookClient = &http.Client{Timeout: webhookTimeout}
+
+// Sign returns the value of WebhookSignatureHeader for body, receivers
+// compare it with hmac.Equal.
+func (h *Webhook) Sign(body []byte) string {
+	mac := hmac.New(sha256.New, h.Secret)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Send POSTs one event.
+func (h *Webhook) Send(event *WebhookEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, h.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if len(h.Secret) != 0 {
+		req.Header.Set(WebhookSignatureHeader, h.Sign(body))
+	}
+
+	client := h.Client
+	if client == nil {
+		client = defaultWebhookClient
+	}
+
+	resp, err := client.Do(req)
+	if resp != nil {
+		defer func(Body io.ReadCloser) {
+			err := Body.Close()
+			if err != nil {
+				return
+			}
+		}(resp.Body)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("http error: %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// Emit sends the events of change, fetching the received items of
+// accepted offers with the session.
+func (h *Webhook) Emit(session *Session, change *OfferChange) error {
+	offer := change.Offer
+
+	var name string
+	switch offer.State {
+	case TradeStateAccepted:
+		name = WebhookOfferAccepted
+	case TradeStateDeclined:
+		name = WebhookOfferDeclined
+	case TradeStateInEscrow:
+		name = WebhookOfferEscrow
+	default:
+		return nil
+	}
+
+	var partner SteamID
+	partner.ParseDefaults(offer.Partner)
+
+	oldState := ""
+	if change.OldState != TradeStateNone {
+		oldState = change.OldState.String()
+	}
+
+	event := &WebhookEvent{
+		Event:     name,
+		OfferID:   offer.ID,
+		Partner:   partner,
+		State:     offer.StateName(),
+		OldState:  oldState,
+		TradeID:   offer.ReceiptID,
+		Timestamp: time.Now().Unix(),
+	}
+	if err := h.Send(event); err != nil {
+		return err
+	}
+
+	if name != WebhookOfferAccepted || len(offer.RecvItems) == 0 || offer.ReceiptID == 0 {
+		return nil
+	}
+
+	items, err := session.GetTradeReceivedItems(offer.ReceiptID)
+	if err != nil {
+		return fmt.Errorf("received items of trade %s: %w", strconv.FormatUint(offer.ReceiptID, 10), err)
+	}
+
+	received := *event
+	received.Event = WebhookItemsReceived
+	received.Items = items
+	return h.Send(&received)
+}
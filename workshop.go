@@ -0,0 +1,250 @@
+package steam
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+const (
+	apiGetPublishedFileDetails = "/IPublishedFileService/GetDetails/v1/?"
+	apiQueryPublishedFiles     = "/IPublishedFileService/QueryFiles/v1/?"
+
+	workshopSubscribeURL   = "/sharedfiles/subscribe"
+	workshopUnsubscribeURL = "/sharedfiles/unsubscribe"
+)
+
+var ErrCannotSubscribe = errors.New("unable to change the workshop subscription")
+
+// Workshop query types, see WorkshopQuery.
+const (
+	WorkshopQueryRankedByVote                     = 0
+	WorkshopQueryRankedByPublicationDate          = 1
+	WorkshopQueryRankedByTrend                    = 3
+	WorkshopQueryRankedByTotalUniqueSubscriptions = 9
+	WorkshopQueryRankedByTextSearch               = 12
+	WorkshopQueryRankedByLastUpdatedDate          = 21
+)
+
+// PublishedFile is a workshop item, FileSize is in bytes.
+type PublishedFile struct {
+	ID                    uint64
+	Result                int // EResult, 1 when the other fields are set
+	Creator               SteamID
+	CreatorAppID          uint32
+	ConsumerAppID         uint32
+	Title                 string
+	Description           string
+	FileName              string
+	FileSize              uint64
+	FileURL               string
+	PreviewURL            string
+	TimeCreated           int64
+	TimeUpdated           int64
+	Visibility            int
+	Banned                bool
+	Subscriptions         uint64
+	LifetimeSubscriptions uint64
+	Favorited             uint64
+	Views                 uint64
+	Tags                  []string
+}
+
+type apiPublishedFile struct {
+	ID                    flexNumber `json:"publishedfileid"`
+	Result                int        `json:"result"`
+	Creator               flexNumber `json:"creator"`
+	CreatorAppID          flexNumber `json:"creator_appid"`
+	ConsumerAppID         flexNumber `json:"consumer_appid"`
+	Title                 string     `json:"title"`
+	Description           string     `json:"file_description"`
+	FileName              string     `json:"filename"`
+	FileSize              flexNumber `json:"file_size"`
+	FileURL               string     `json:"file_url"`
+	PreviewURL            string     `json:"preview_url"`
+	TimeCreated           flexNumber `json:"time_created"`
+	TimeUpdated           flexNumber `json:"time_updated"`
+	Visibility            flexNumber `json:"visibility"`
+	Banned                bool       `json:"banned"`
+	Subscriptions         flexNumber `json:"subscriptions"`
+	LifetimeSubscriptions flexNumber `json:"lifetime_subscriptions"`
+	Favorited             flexNumber `json:"favorited"`
+	Views                 flexNumber `json:"views"`
+	Tags                  []struct {
+		Tag string `json:"tag"`
+	} `json:"tags"`
+}
+
+func (f *apiPublishedFile) file() *PublishedFile {
+	file := &PublishedFile{
+		ID:                    uint64(f.ID),
+		Result:                f.Result,
+		Creator:               SteamID(f.Creator),
+		CreatorAppID:          uint32(f.CreatorAppID),
+		ConsumerAppID:         uint32(f.ConsumerAppID),
+		Title:                 f.Title,
+		Description:           f.Description,
+		FileName:              f.FileName,
+		FileSize:              uint64(f.FileSize),
+		FileURL:               f.FileURL,
+		PreviewURL:            f.PreviewURL,
+		TimeCreated:           int64(f.TimeCreated),
+		TimeUpdated:           int64(f.TimeUpdated),
+		Visibility:            int(f.Visibility),
+		Banned:                f.Banned,
+		Subscriptions:         uint64(f.Subscriptions),
+		LifetimeSubscriptions: uint64(f.LifetimeSubscriptions),
+		Favorited:             uint64(f.Favorited),
+		Views:                 uint64(f.Views),
+	}
+
+	for _, tag := range f.Tags {
+		file.Tags = append(file.Tags, tag.Tag)
+	}
+
+	return file
+}
+
+func publishedFiles(files []*apiPublishedFile) []*PublishedFile {
+	result := make([]*PublishedFile, 0, len(files))
+	for _, f := range files {
+		result = append(result, f.file())
+	}
+
+	return result
+}
+
+// GetPublishedFileDetails looks workshop items up by ID.
+func (session *Session) GetPublishedFileDetails(ids []uint64) ([]*PublishedFile, error) {
+	params := session.authorize(url.Values{
+		"includetags": {"true"},
+	})
+	for i, id := range ids {
+		params.Set(fmt.Sprintf("publishedfileids[%d]", i), strconv.FormatUint(id, 10))
+	}
+
+	var response struct {
+		Inner *struct {
+			Files []*apiPublishedFile `json:"publishedfiledetails"`
+		} `json:"response"`
+	}
+	if err := session.getAPI(session.APIBaseURL()+apiGetPublishedFileDetails, params, &response); err != nil {
+		return nil, err
+	}
+
+	if response.Inner == nil {
+		return nil, ErrInvalidResponse
+	}
+
+	return publishedFiles(response.Inner.Files), nil
+}
+
+// WorkshopQuery selects the workshop items QueryPublishedFiles returns.
+type WorkshopQuery struct {
+	AppID        uint32
+	QueryType    int // one of the WorkshopQuery* constants
+	SearchText   string
+	RequiredTags []string
+	Cursor       string // from the previous page, empty for the first one
+	PerPage      int    // defaults to 100
+}
+
+// QueryPublishedFiles searches the workshop of an app and returns a page of
+// items, the total count and the cursor of the next page.
+func (session *Session) QueryPublishedFiles(query *WorkshopQuery) ([]*PublishedFile, int, string, error) {
+	cursor := query.Cursor
+	if len(cursor) == 0 {
+		cursor = "*"
+	}
+
+	perPage := query.PerPage
+	if perPage <= 0 {
+		perPage = 100
+	}
+
+	params := session.authorize(url.Values{
+		"appid":           {strconv.FormatUint(uint64(query.AppID), 10)},
+		"query_type":      {strconv.Itoa(query.QueryType)},
+		"cursor":          {cursor},
+		"numperpage":      {strconv.Itoa(perPage)},
+		"return_tags":     {"true"},
+		"return_previews": {"true"},
+		"return_metadata": {"true"},
+	})
+	if len(query.SearchText) != 0 {
+		params.Set("search_text", query.SearchText)
+	}
+	for i, tag := range query.RequiredTags {
+		params.Set(fmt.Sprintf("requiredtags[%d]", i), tag)
+	}
+
+	var response struct {
+		Inner *struct {
+			Total      int                 `json:"total"`
+			Files      []*apiPublishedFile `json:"publishedfiledetails"`
+			NextCursor string              `json:"next_cursor"`
+		} `json:"response"`
+	}
+	if err := session.getAPI(session.APIBaseURL()+apiQueryPublishedFiles, params, &response); err != nil {
+		return nil, 0, "", err
+	}
+
+	if response.Inner == nil {
+		return nil, 0, "", ErrInvalidResponse
+	}
+
+	return publishedFiles(response.Inner.Files), response.Inner.Total, response.Inner.NextCursor, nil
+}
+
+func (session *Session) execWorkshopSubscription(u string, appID uint32, id uint64) error {
+	if err := session.checkWritable(); err != nil {
+		return err
+	}
+
+	resp, err := session.client.PostForm(u, url.Values{
+		"id":        {strconv.FormatUint(id, 10)},
+		"appid":     {strconv.FormatUint(uint64(appID), 10)},
+		"sessionid": {session.sessionID},
+	})
+	if resp != nil {
+		defer func(Body io.ReadCloser) {
+			err := Body.Close()
+			if err != nil {
+				return
+			}
+		}(resp.Body)
+	}
+
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("http error: %d", resp.StatusCode)
+	}
+
+	var response struct {
+		Success int `json:"success"`
+	}
+	if err = session.readJSON(resp, &response); err != nil {
+		return err
+	}
+
+	if response.Success != 1 {
+		return ErrCannotSubscribe
+	}
+
+	return nil
+}
+
+// SubscribeWorkshopItem subscribes the account to a workshop item of appID.
+func (session *Session) SubscribeWorkshopItem(appID uint32, id uint64) error {
+	return session.execWorkshopSubscription(session.CommunityBaseURL()+workshopSubscribeURL, appID, id)
+}
+
+// UnsubscribeWorkshopItem removes the subscription to a workshop item.
+func (session *Session) UnsubscribeWorkshopItem(appID uint32, id uint64) error {
+	return session.execWorkshopSubscription(session.CommunityBaseURL()+workshopUnsubscribeURL, appID, id)
+}